@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -62,7 +63,7 @@ func (c *Client) ListAddonsWithContext(ctx context.Context, o ListAddonOptions)
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/addons?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListAddonsWithContext", "/addons?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +76,14 @@ func (c *Client) ListAddonsWithContext(ctx context.Context, o ListAddonOptions)
 	return &result, nil
 }
 
+// Addons returns an iterator over all of the add-ons installed on your
+// account, automatically fetching additional pages as needed.
+func (c *Client) Addons(ctx context.Context, o ListAddonOptions) iter.Seq2[Addon, error] {
+	return offsetSeq(ctx, c, "Addons", "/addons", o, func(r *ListAddonResponse) ([]Addon, APIListObject) {
+		return r.Addons, r.APIListObject
+	})
+}
+
 // InstallAddon installs an add-on for your account.
 //
 // Deprecated: Use InstallAddonWithContext instead.
@@ -88,7 +97,7 @@ func (c *Client) InstallAddonWithContext(ctx context.Context, a Addon) (*Addon,
 		"addon": a,
 	}
 
-	resp, err := c.post(ctx, "/addons", d, nil)
+	resp, err := c.post(ctx, "InstallAddonWithContext", "/addons", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +118,7 @@ func (c *Client) DeleteAddon(id string) error {
 
 // DeleteAddonWithContext deletes an add-on from your account.
 func (c *Client) DeleteAddonWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/addons/"+id)
+	_, err := c.delete(ctx, "DeleteAddonWithContext", "/addons/"+id)
 	return err
 }
 
@@ -122,7 +131,7 @@ func (c *Client) GetAddon(id string) (*Addon, error) {
 
 // GetAddonWithContext gets details about an existing add-on.
 func (c *Client) GetAddonWithContext(ctx context.Context, id string) (*Addon, error) {
-	resp, err := c.get(ctx, "/addons/"+id, nil)
+	resp, err := c.get(ctx, "GetAddonWithContext", "/addons/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +152,7 @@ func (c *Client) UpdateAddonWithContext(ctx context.Context, id string, a Addon)
 		"addon": a,
 	}
 
-	resp, err := c.put(ctx, "/addons/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateAddonWithContext", "/addons/"+id, d, nil)
 	if err != nil {
 		return nil, err
 	}
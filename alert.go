@@ -0,0 +1,369 @@
+package pagerduty
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// V2EventManager sends Events API V2 events. It's satisfied by both Client and
+// EventsClient.
+type V2EventManager interface {
+	ManageEventWithContext(ctx context.Context, e *V2Event) (*V2EventResponse, error)
+}
+
+var (
+	_ V2EventManager = (*Client)(nil)       // assert it satisfies the V2EventManager interface.
+	_ V2EventManager = (*EventsClient)(nil) // assert it satisfies the V2EventManager interface.
+)
+
+// DedupKeyFields selects the fields of an alert's payload its dedup key is
+// derived from, so that the same alert always gets the same dedup key. The
+// routing key is always included.
+//
+// The selected fields should identify the problem the alert is about, and so
+// shouldn't include values which change while it's ongoing (e.g., a summary
+// including the current disk usage).
+type DedupKeyFields struct {
+	Source    bool
+	Component bool
+	Group     bool
+	Class     bool
+
+	// CustomDetails are the keys of the custom details to include, when the
+	// custom details of the payload are a JSON object.
+	CustomDetails []string
+}
+
+// defaultDedupKeyFields are the fields used by Alerts by default.
+var defaultDedupKeyFields = DedupKeyFields{Source: true, Component: true, Group: true, Class: true}
+
+// DedupKey returns the dedup key of the alert with the payload, which is the
+// hex encoded SHA-256 hash of the routing key and selected fields.
+func (f DedupKeyFields) DedupKey(routingKey string, p V2Payload) (string, error) {
+	// the fields are hashed as a JSON array, so that their values can't run
+	// into each other (e.g., source "ab" and component "c", and source "a" and
+	// component "bc", get different keys)
+	values := []interface{}{routingKey}
+
+	add := func(name string, selected bool, value string) {
+		if selected {
+			values = append(values, name, value)
+		}
+	}
+
+	add("source", f.Source, p.Source)
+	add("component", f.Component, p.Component)
+	add("group", f.Group, p.Group)
+	add("class", f.Class, p.Class)
+
+	if len(f.CustomDetails) > 0 {
+		var details map[string]interface{}
+
+		// round-trip the custom details, which can be of any type, through
+		// JSON to look their keys up
+		if p.Details != nil {
+			data, err := json.Marshal(p.Details)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode custom details: %w", err)
+			}
+
+			if err := json.Unmarshal(data, &details); err != nil {
+				return "", fmt.Errorf("custom details must be a JSON object to derive a dedup key from them: %w", err)
+			}
+		}
+
+		for _, key := range f.CustomDetails {
+			values = append(values, "custom_details."+key, details[key])
+		}
+	}
+
+	// encoding/json sorts map keys, so the encoding of nested custom details
+	// is stable
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode dedup key fields: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Alerts manages the lifecycle of alerts sent to a service using the Events API
+// V2. Each alert gets a dedup key derived from its payload, so that separate
+// processes triggering and resolving the same alert agree on its dedup key,
+// without having to share the one returned by the API.
+//
+// When configured with a state file using WithAlertState, the alerts which are
+// triggered are remembered until they're resolved, so that a restarted process
+// can resolve the alerts it previously triggered using Open.
+//
+// Alerts is safe for concurrent use.
+type Alerts struct {
+	client     V2EventManager
+	routingKey string
+	fields     DedupKeyFields
+	statePath  string
+
+	mu   sync.Mutex
+	open map[string]openAlert
+}
+
+// openAlert is an alert which was triggered and not yet resolved, as persisted
+// to the state file.
+type openAlert struct {
+	DedupKey    string    `json:"dedup_key"`
+	RoutingKey  string    `json:"routing_key"`
+	Payload     V2Payload `json:"payload"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+// AlertsOption configures Alerts.
+type AlertsOption func(*Alerts)
+
+// WithDedupKeyFields sets the fields of the payload the dedup keys of alerts
+// are derived from. By default, these are the source, component, group, and
+// class of the payload.
+func WithDedupKeyFields(fields DedupKeyFields) AlertsOption {
+	return func(a *Alerts) {
+		a.fields = fields
+	}
+}
+
+// WithAlertState configures Alerts to remember the alerts which are open in
+// the file, which is created if it doesn't exist.
+func WithAlertState(path string) AlertsOption {
+	return func(a *Alerts) {
+		a.statePath = path
+	}
+}
+
+// NewAlerts returns Alerts sending events with the routing key using the client,
+// which is usually an EventsClient. When a state file is configured, the alerts
+// which were open are loaded from it.
+func NewAlerts(client V2EventManager, routingKey string, opts ...AlertsOption) (*Alerts, error) {
+	a := &Alerts{
+		client:     client,
+		routingKey: routingKey,
+		fields:     defaultDedupKeyFields,
+		open:       make(map[string]openAlert),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if a.statePath == "" {
+		return a, nil
+	}
+
+	data, err := os.ReadFile(a.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert state: %w", err)
+	}
+
+	var open []openAlert
+	if err := json.Unmarshal(data, &open); err != nil {
+		return nil, fmt.Errorf("failed to decode alert state %s: %w", a.statePath, err)
+	}
+
+	for _, oa := range open {
+		a.open[oa.DedupKey] = oa
+	}
+
+	return a, nil
+}
+
+// Alert returns the alert with the payload, whose dedup key is derived from
+// the payload's fields selected using WithDedupKeyFields.
+func (a *Alerts) Alert(payload V2Payload) (*Alert, error) {
+	key, err := a.fields.DedupKey(a.routingKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Alert{alerts: a, routingKey: a.routingKey, dedupKey: key, payload: payload}, nil
+}
+
+// Open returns the alerts which were triggered, and not resolved since, in the
+// order they were triggered. Alerts are only remembered when a state file is
+// configured using WithAlertState.
+func (a *Alerts) Open() []*Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	open := make([]openAlert, 0, len(a.open))
+	for _, oa := range a.open {
+		open = append(open, oa)
+	}
+
+	sort.Slice(open, func(i, j int) bool {
+		if !open[i].TriggeredAt.Equal(open[j].TriggeredAt) {
+			return open[i].TriggeredAt.Before(open[j].TriggeredAt)
+		}
+
+		return open[i].DedupKey < open[j].DedupKey
+	})
+
+	alerts := make([]*Alert, len(open))
+	for i, oa := range open {
+		alerts[i] = &Alert{alerts: a, routingKey: oa.RoutingKey, dedupKey: oa.DedupKey, payload: oa.Payload}
+	}
+
+	return alerts
+}
+
+// setOpen records whether the alert is open, and persists it to the state
+// file if there's one.
+func (a *Alerts) setOpen(alert *Alert, open bool) error {
+	if a.statePath == "" {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if open {
+		triggeredAt := time.Now().UTC()
+		if oa, ok := a.open[alert.dedupKey]; ok {
+			triggeredAt = oa.TriggeredAt
+		}
+
+		a.open[alert.dedupKey] = openAlert{
+			DedupKey:    alert.dedupKey,
+			RoutingKey:  alert.routingKey,
+			Payload:     alert.payload,
+			TriggeredAt: triggeredAt,
+		}
+	} else {
+		if _, ok := a.open[alert.dedupKey]; !ok {
+			return nil
+		}
+
+		delete(a.open, alert.dedupKey)
+	}
+
+	return a.saveState()
+}
+
+// saveState writes the open alerts to the state file, using a temporary file
+// so that a partially written state is never read. a.mu must be held.
+func (a *Alerts) saveState() error {
+	open := make([]openAlert, 0, len(a.open))
+	for _, oa := range a.open {
+		open = append(open, oa)
+	}
+
+	sort.Slice(open, func(i, j int) bool { return open[i].DedupKey < open[j].DedupKey })
+
+	data, err := json.MarshalIndent(open, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alert state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(a.statePath), filepath.Base(a.statePath)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), a.statePath); err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	return nil
+}
+
+// Alert is a handle to an alert managed using Alerts, to trigger, acknowledge,
+// and resolve it.
+type Alert struct {
+	alerts     *Alerts
+	routingKey string
+	dedupKey   string
+	payload    V2Payload
+}
+
+// DedupKey returns the dedup key of the alert.
+func (a *Alert) DedupKey() string {
+	return a.dedupKey
+}
+
+// Payload returns the payload the alert is triggered with.
+func (a *Alert) Payload() V2Payload {
+	return a.payload
+}
+
+// Trigger triggers the alert, or updates it if it's already open. When the
+// alert was triggered, but its state couldn't be saved, both the response and
+// an error are returned.
+func (a *Alert) Trigger(ctx context.Context) (*V2EventResponse, error) {
+	payload := a.payload
+
+	resp, err := a.send(ctx, V2ActionTrigger, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.alerts.setOpen(a, true); err != nil {
+		return resp, fmt.Errorf("alert was triggered, but %w", err)
+	}
+
+	return resp, nil
+}
+
+// Acknowledge acknowledges the alert.
+func (a *Alert) Acknowledge(ctx context.Context) (*V2EventResponse, error) {
+	return a.send(ctx, V2ActionAcknowledge, nil)
+}
+
+// Resolve resolves the alert, and forgets it. When the alert was resolved, but
+// its state couldn't be saved, both the response and an error are returned.
+func (a *Alert) Resolve(ctx context.Context) (*V2EventResponse, error) {
+	resp, err := a.send(ctx, V2ActionResolve, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.alerts.setOpen(a, false); err != nil {
+		return resp, fmt.Errorf("alert was resolved, but %w", err)
+	}
+
+	return resp, nil
+}
+
+func (a *Alert) send(ctx context.Context, action V2Action, payload *V2Payload) (*V2EventResponse, error) {
+	return a.alerts.client.ManageEventWithContext(ctx, &V2Event{
+		RoutingKey: a.routingKey,
+		Action:     action,
+		DedupKey:   a.dedupKey,
+		Payload:    payload,
+	})
+}
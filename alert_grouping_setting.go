@@ -3,7 +3,10 @@ package pagerduty
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"net/http"
 
 	"github.com/google/go-querystring/query"
 )
@@ -63,7 +66,7 @@ type AlertGroupingSettingService struct {
 func (c *Client) CreateAlertGroupingSetting(ctx context.Context, a AlertGroupingSetting) (*AlertGroupingSetting, error) {
 	d := map[string]AlertGroupingSetting{"alert_grouping_setting": a}
 
-	resp, err := c.post(ctx, "/alert_grouping_settings", d, nil)
+	resp, err := c.post(ctx, "CreateAlertGroupingSetting", "/alert_grouping_settings", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +110,7 @@ func (c *Client) ListAlertGroupingSettings(ctx context.Context, o ListAlertGroup
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/alert_grouping_settings?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListAlertGroupingSettings", "/alert_grouping_settings?"+v.Encode(), nil)
 
 	// If there are no alert grouping settings, return an empty response.
 	if resp.StatusCode == 404 {
@@ -140,9 +143,49 @@ func (c *Client) ListAlertGroupingSettings(ctx context.Context, o ListAlertGroup
 	return result, nil
 }
 
+// AlertGroupingSettings returns an iterator over all of your alert grouping
+// settings, automatically fetching additional pages as needed by following
+// the "after" cursor returned by the API.
+func (c *Client) AlertGroupingSettings(ctx context.Context, o ListAlertGroupingSettingsOptions) iter.Seq2[AlertGroupingSetting, error] {
+	type page struct {
+		After                 string                    `json:"after,omitempty"`
+		Limit                 uint                      `json:"limit,omitempty"`
+		AlertGroupingSettings []alertGroupingSettingRaw `json:"alert_grouping_settings"`
+	}
+
+	seq := cursorSeq(ctx, c, "AlertGroupingSettings", "/alert_grouping_settings", "after", o, func(r *page) ([]alertGroupingSettingRaw, cursor) {
+		return r.AlertGroupingSettings, cursor{Limit: r.Limit, NextCursor: r.After}
+	})
+
+	return func(yield func(AlertGroupingSetting, error) bool) {
+		for raw, err := range seq {
+			// If there are no alert grouping settings, the API responds with a 404.
+			var aerr APIError
+			if errors.As(err, &aerr) && aerr.StatusCode == http.StatusNotFound {
+				return
+			}
+
+			if err != nil {
+				yield(AlertGroupingSetting{}, err)
+				return
+			}
+
+			s, err := getAlertGroupingSettingFromRaw(raw)
+			if err != nil {
+				yield(AlertGroupingSetting{}, fmt.Errorf("failed to decode the config of alert grouping setting %q: %w", raw.ID, err))
+				return
+			}
+
+			if !yield(*s, nil) {
+				return
+			}
+		}
+	}
+}
+
 // GetAlertGroupingSetting get an existing Alert Grouping Setting.
 func (c *Client) GetAlertGroupingSetting(ctx context.Context, id string) (*AlertGroupingSetting, error) {
-	resp, err := c.get(ctx, "/alert_grouping_settings/"+id, nil)
+	resp, err := c.get(ctx, "GetAlertGroupingSetting", "/alert_grouping_settings/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +203,7 @@ func (c *Client) GetAlertGroupingSetting(ctx context.Context, id string) (*Alert
 
 // DeleteAlertGroupingSetting deletes an existing Alert Grouping Setting.
 func (c *Client) DeleteAlertGroupingSetting(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/alert_grouping_settings/"+id)
+	_, err := c.delete(ctx, "DeleteAlertGroupingSetting", "/alert_grouping_settings/"+id)
 	return err
 }
 
@@ -168,7 +211,7 @@ func (c *Client) DeleteAlertGroupingSetting(ctx context.Context, id string) erro
 func (c *Client) UpdateAlertGroupingSetting(ctx context.Context, a AlertGroupingSetting) (*AlertGroupingSetting, error) {
 	d := map[string]AlertGroupingSetting{"alert_grouping_setting": a}
 
-	resp, err := c.put(ctx, "/alert_grouping_settings/"+a.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateAlertGroupingSetting", "/alert_grouping_settings/"+a.ID, d, nil)
 	if err != nil {
 		return nil, err
 	}
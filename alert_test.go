@@ -0,0 +1,183 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestDedupKeyFields_DedupKey(t *testing.T) {
+	payload := V2Payload{
+		Summary:   "Disk is 95% full",
+		Source:    "db1",
+		Component: "postgres",
+		Severity:  V2SeverityCritical,
+		Details:   map[string]interface{}{"mount": "/var", "usage": 95},
+	}
+
+	key := func(fields DedupKeyFields, routingKey string, p V2Payload) string {
+		t.Helper()
+
+		k, err := fields.DedupKey(routingKey, p)
+		testErrCheck(t, "DedupKey()", "", err)
+
+		return k
+	}
+
+	base := key(defaultDedupKeyFields, "rk", payload)
+	testEqual(t, 64, len(base))
+
+	// changes to fields which aren't selected don't change the key
+	changed := payload
+	changed.Summary = "Disk is 96% full"
+	changed.Severity = V2SeverityWarning
+	testEqual(t, base, key(defaultDedupKeyFields, "rk", changed))
+
+	// changes to the selected fields, or the routing key, do
+	changed = payload
+	changed.Component = "mysql"
+	if key(defaultDedupKeyFields, "rk", changed) == base {
+		t.Error("DedupKey() didn't change with the component")
+	}
+
+	if key(defaultDedupKeyFields, "other", payload) == base {
+		t.Error("DedupKey() didn't change with the routing key")
+	}
+
+	// values can't run into each other
+	a := key(defaultDedupKeyFields, "rk", V2Payload{Source: "ab", Component: "c"})
+	b := key(defaultDedupKeyFields, "rk", V2Payload{Source: "a", Component: "bc"})
+	if a == b {
+		t.Error("DedupKey() is the same for different fields")
+	}
+
+	fields := DedupKeyFields{Source: true, CustomDetails: []string{"mount"}}
+	withMount := key(fields, "rk", payload)
+
+	changed = payload
+	changed.Details = struct {
+		Mount string `json:"mount"`
+		Usage int    `json:"usage"`
+	}{Mount: "/var", Usage: 96}
+	testEqual(t, withMount, key(fields, "rk", changed))
+
+	changed.Details = map[string]string{"mount": "/home"}
+	if key(fields, "rk", changed) == withMount {
+		t.Error("DedupKey() didn't change with the custom details")
+	}
+
+	_, err := fields.DedupKey("rk", V2Payload{Details: "not an object"})
+	testErrCheck(t, "DedupKey()", "custom details must be a JSON object", err)
+}
+
+// recordingEventManager is a V2EventManager recording the events it's sent.
+type recordingEventManager struct {
+	mu     sync.Mutex
+	events []V2Event
+}
+
+func (m *recordingEventManager) ManageEventWithContext(_ context.Context, e *V2Event) (*V2EventResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, *e)
+
+	return &V2EventResponse{Status: "success", DedupKey: e.DedupKey}, nil
+}
+
+func TestAlerts(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "alerts.json")
+	client := &recordingEventManager{}
+
+	alerts, err := NewAlerts(client, "rk", WithAlertState(state))
+	testErrCheck(t, "NewAlerts()", "", err)
+
+	disk, err := alerts.Alert(V2Payload{Summary: "Disk is full", Source: "db1", Severity: V2SeverityCritical})
+	testErrCheck(t, "Alert()", "", err)
+
+	cpu, err := alerts.Alert(V2Payload{Summary: "CPU is busy", Source: "db2", Severity: V2SeverityWarning})
+	testErrCheck(t, "Alert()", "", err)
+
+	ctx := context.Background()
+
+	for _, a := range []*Alert{disk, cpu} {
+		resp, err := a.Trigger(ctx)
+		testErrCheck(t, "Trigger()", "", err)
+		testEqual(t, a.DedupKey(), resp.DedupKey)
+	}
+
+	_, err = disk.Acknowledge(ctx)
+	testErrCheck(t, "Acknowledge()", "", err)
+
+	// a restarted process remembers the open alerts, and can resolve them
+	alerts, err = NewAlerts(client, "rk", WithAlertState(state))
+	testErrCheck(t, "NewAlerts()", "", err)
+
+	open := alerts.Open()
+	testEqual(t, 2, len(open))
+	testEqual(t, disk.DedupKey(), open[0].DedupKey())
+	testEqual(t, disk.Payload(), open[0].Payload())
+
+	_, err = open[0].Resolve(ctx)
+	testErrCheck(t, "Resolve()", "", err)
+
+	alerts, err = NewAlerts(client, "rk", WithAlertState(state))
+	testErrCheck(t, "NewAlerts()", "", err)
+
+	open = alerts.Open()
+	testEqual(t, 1, len(open))
+	testEqual(t, cpu.DedupKey(), open[0].DedupKey())
+
+	payload := disk.Payload()
+	want := []V2Event{
+		{RoutingKey: "rk", Action: V2ActionTrigger, DedupKey: disk.DedupKey(), Payload: &payload},
+		{RoutingKey: "rk", Action: V2ActionTrigger, DedupKey: cpu.DedupKey(), Payload: func() *V2Payload { p := cpu.Payload(); return &p }()},
+		{RoutingKey: "rk", Action: V2ActionAcknowledge, DedupKey: disk.DedupKey()},
+		{RoutingKey: "rk", Action: V2ActionResolve, DedupKey: disk.DedupKey()},
+	}
+	testEqual(t, want, client.events)
+}
+
+func TestAlerts_eventsClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var actions []V2Action
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		var e V2Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+
+		actions = append(actions, e.Action)
+
+		if e.Action == V2ActionResolve {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": "invalid event", "message": "Event object is invalid"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "dedup_key": "` + e.DedupKey + `"}`))
+	})
+
+	alerts, err := NewAlerts(NewEventsClient("", WithV2EventsAPIEndpoint(server.URL)), "rk")
+	testErrCheck(t, "NewAlerts()", "", err)
+
+	alert, err := alerts.Alert(V2Payload{Summary: "Disk is full", Source: "db1", Severity: V2SeverityCritical})
+	testErrCheck(t, "Alert()", "", err)
+
+	_, err = alert.Trigger(context.Background())
+	testErrCheck(t, "Trigger()", "", err)
+
+	_, err = alert.Resolve(context.Background())
+	testErrCheck(t, "Resolve()", "status code 400", err)
+
+	testEqual(t, []V2Action{V2ActionTrigger, V2ActionResolve}, actions)
+
+	// without a state file, open alerts aren't remembered
+	testEqual(t, 0, len(alerts.Open()))
+}
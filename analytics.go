@@ -52,8 +52,8 @@ type AnalyticsRawIncidentsResponse struct {
 // AnalyticsFilter represents the set of filters as part of the request to PagerDuty when
 // requesting analytics.
 type AnalyticsFilter struct {
-	CreatedAtStart        string   `json:"created_at_start,omitempty"`
-	CreatedAtEnd          string   `json:"created_at_end,omitempty"`
+	CreatedAtStart        *Time    `json:"created_at_start,omitempty"`
+	CreatedAtEnd          *Time    `json:"created_at_end,omitempty"`
 	Urgency               string   `json:"urgency,omitempty"`
 	Major                 bool     `json:"major,omitempty"`
 	MinAcknowledgements   int      `json:"min_acknowledgements,omitempty"`
@@ -149,31 +149,31 @@ type AnalyticsRawIncident struct {
 
 // GetAggregatedIncidentData gets the aggregated incident analytics for the requested data.
 func (c *Client) GetAggregatedIncidentData(ctx context.Context, analytics AnalyticsRequest) (AnalyticsResponse, error) {
-	return c.getAggregatedData(ctx, analytics, "all")
+	return c.getAggregatedData(ctx, "GetAggregatedIncidentData", analytics, "all")
 }
 
 // GetAggregatedServiceData gets the aggregated service analytics for the requested data.
 func (c *Client) GetAggregatedServiceData(ctx context.Context, analytics AnalyticsRequest) (AnalyticsResponse, error) {
-	return c.getAggregatedData(ctx, analytics, "services")
+	return c.getAggregatedData(ctx, "GetAggregatedServiceData", analytics, "services")
 }
 
 // GetAggregatedTeamData gets the aggregated team analytics for the requested data.
 func (c *Client) GetAggregatedTeamData(ctx context.Context, analytics AnalyticsRequest) (AnalyticsResponse, error) {
-	return c.getAggregatedData(ctx, analytics, "teams")
+	return c.getAggregatedData(ctx, "GetAggregatedTeamData", analytics, "teams")
 }
 
 // GetAggregatedEscalationPolicyData gets the aggregated escalation policy analytics for the requested data.
 func (c *Client) GetAggregatedEscalationPolicyData(ctx context.Context, analytics AnalyticsRequest) (AnalyticsResponse, error) {
-	return c.getAggregatedData(ctx, analytics, "escalation_policies")
+	return c.getAggregatedData(ctx, "GetAggregatedEscalationPolicyData", analytics, "escalation_policies")
 }
 
-func (c *Client) getAggregatedData(ctx context.Context, analytics AnalyticsRequest, endpoint string) (AnalyticsResponse, error) {
+func (c *Client) getAggregatedData(ctx context.Context, op string, analytics AnalyticsRequest, endpoint string) (AnalyticsResponse, error) {
 	h := map[string]string{
 		"X-EARLY-ACCESS": "analytics-v2",
 	}
 
 	u := fmt.Sprintf("%s/%s", analyticsBaseURL, endpoint)
-	resp, err := c.post(ctx, u, analytics, h)
+	resp, err := c.post(ctx, op, u, analytics, h)
 	if err != nil {
 		return AnalyticsResponse{}, err
 	}
@@ -189,7 +189,7 @@ func (c *Client) getAggregatedData(ctx context.Context, analytics AnalyticsReque
 // GetAnalyticsIncidentsById gets the raw analytics for the requested incident.
 func (c *Client) GetAnalyticsIncidentsById(ctx context.Context, id string) (*AnalyticsRawIncident, error) {
 	path := fmt.Sprintf("%s/%s/%s", rawDataBaseURL, "incidents", id)
-	resp, err := c.get(ctx, path, nil)
+	resp, err := c.get(ctx, "GetAnalyticsIncidentsById", path, nil)
 
 	if err != nil {
 		return &AnalyticsRawIncident{}, err
@@ -208,7 +208,7 @@ func (c *Client) GetAnalyticsIncidents(ctx context.Context, rawDataReq Analytics
 	h := map[string]string{}
 
 	path := fmt.Sprintf("%s/%s", rawDataBaseURL, "incidents")
-	resp, err := c.post(ctx, path, rawDataReq, h)
+	resp, err := c.post(ctx, "GetAnalyticsIncidents", path, rawDataReq, h)
 
 	if err != nil {
 		return &AnalyticsRawIncidentsResponse{}, err
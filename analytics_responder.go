@@ -59,16 +59,16 @@ type AnalyticsResponderData struct {
 }
 
 func (c *Client) GetAggregatedResponderData(ctx context.Context, analytics AnalyticsResponderRequest) (AnalyticsResponderResponse, error) {
-	return c.getAggregatedResponderData(ctx, analytics, "all")
+	return c.getAggregatedResponderData(ctx, "GetAggregatedResponderData", analytics, "all")
 }
 
-func (c *Client) getAggregatedResponderData(ctx context.Context, analytics AnalyticsResponderRequest, endpoint string) (AnalyticsResponderResponse, error) {
+func (c *Client) getAggregatedResponderData(ctx context.Context, op string, analytics AnalyticsResponderRequest, endpoint string) (AnalyticsResponderResponse, error) {
 	h := map[string]string{
 		"X-EARLY-ACCESS": "analytics-v2",
 	}
 
 	u := fmt.Sprintf("%s/%s", analyticsResponderBaseURL, endpoint)
-	resp, err := c.post(ctx, u, analytics, h)
+	resp, err := c.post(ctx, op, u, analytics, h)
 	if err != nil {
 		return AnalyticsResponderResponse{}, err
 	}
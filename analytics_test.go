@@ -14,15 +14,15 @@ func TestAnalytics_GetAggregatedIncidentData(t *testing.T) {
 
 	analyticsRequest := AnalyticsRequest{
 		Filters: &AnalyticsFilter{
-			CreatedAtStart: "2021-01-01T15:00:32Z",
-			CreatedAtEnd:   "2021-01-08T15:00:32Z",
+			CreatedAtStart: mustParseTimePtr("2021-01-01T15:00:32Z"),
+			CreatedAtEnd:   mustParseTimePtr("2021-01-08T15:00:32Z"),
 			TeamIDs:        []string{"PCDYDX0"},
 		},
 		AggregateUnit: "day",
 		TimeZone:      "Etc/UTC",
 	}
 	analyticsDataWanted := AnalyticsData{MeanSecondsToResolve: 34550, MeanSecondsToFirstAck: 70, MeanEngagedSeconds: 502, MeanAssignmentCount: 1, TotalBusinessHourInterruptions: 1, TotalEngagedSeconds: 2514, TotalIncidentCount: 5, RangeStart: "2021-01-06T00:00:00.000000"}
-	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: "2021-01-06T09:21:41Z", CreatedAtEnd: "2021-01-13T09:21:41Z", TeamIDs: []string{"PCDYDX0"}}
+	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: mustParseTimePtr("2021-01-06T09:21:41Z"), CreatedAtEnd: mustParseTimePtr("2021-01-13T09:21:41Z"), TeamIDs: []string{"PCDYDX0"}}
 	analyticsResponse := AnalyticsResponse{
 		Data:          []AnalyticsData{analyticsDataWanted},
 		Filters:       &analyticsFilterWanted,
@@ -59,15 +59,15 @@ func TestAnalytics_GetAggregatedServiceData(t *testing.T) {
 
 	analyticsRequest := AnalyticsRequest{
 		Filters: &AnalyticsFilter{
-			CreatedAtStart: "2021-01-01T15:00:32Z",
-			CreatedAtEnd:   "2021-01-08T15:00:32Z",
+			CreatedAtStart: mustParseTimePtr("2021-01-01T15:00:32Z"),
+			CreatedAtEnd:   mustParseTimePtr("2021-01-08T15:00:32Z"),
 			TeamIDs:        []string{"PCDYDX0"},
 		},
 		AggregateUnit: "day",
 		TimeZone:      "Etc/UTC",
 	}
 	analyticsDataWanted := AnalyticsData{MeanAssignmentCount: 1, MeanEngagedSeconds: 502, MeanEngagedUserCount: 0, MeanSecondsToResolve: 34550, MeanSecondsToFirstAck: 70, TotalBusinessHourInterruptions: 1, TotalEngagedSeconds: 2514, TotalIncidentCount: 5, RangeStart: "2021-01-06T00:00:00.000000", ServiceID: "PSEJLIN", ServiceName: "FooAlerts", TeamID: "PCDYDX0", TeamName: "FooTeam", UpTimePct: 89.86111111111111}
-	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: "2021-01-06T09:21:41Z", CreatedAtEnd: "2021-01-13T09:21:41Z", TeamIDs: []string{"PCDYDX0"}}
+	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: mustParseTimePtr("2021-01-06T09:21:41Z"), CreatedAtEnd: mustParseTimePtr("2021-01-13T09:21:41Z"), TeamIDs: []string{"PCDYDX0"}}
 	analyticsResponse := AnalyticsResponse{
 		Data:          []AnalyticsData{analyticsDataWanted},
 		Filters:       &analyticsFilterWanted,
@@ -103,15 +103,15 @@ func TestAnalytics_GetAggregatedTeamData(t *testing.T) {
 
 	analyticsRequest := AnalyticsRequest{
 		Filters: &AnalyticsFilter{
-			CreatedAtStart: "2021-01-01T15:00:32Z",
-			CreatedAtEnd:   "2021-01-08T15:00:32Z",
+			CreatedAtStart: mustParseTimePtr("2021-01-01T15:00:32Z"),
+			CreatedAtEnd:   mustParseTimePtr("2021-01-08T15:00:32Z"),
 			TeamIDs:        []string{"PCDYDX0"},
 		},
 		AggregateUnit: "day",
 		TimeZone:      "Etc/UTC",
 	}
 	analyticsDataWanted := AnalyticsData{MeanAssignmentCount: 1, MeanEngagedSeconds: 502, MeanEngagedUserCount: 0, MeanSecondsToResolve: 34550, MeanSecondsToFirstAck: 70, TotalBusinessHourInterruptions: 1, TotalEngagedSeconds: 2514, TotalIncidentCount: 5, RangeStart: "2021-01-06T00:00:00.000000", TeamID: "PCDYDX0", TeamName: "FooTeam", UpTimePct: 89.86111111111111}
-	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: "2021-01-06T09:21:41Z", CreatedAtEnd: "2021-01-13T09:21:41Z", TeamIDs: []string{"PCDYDX0"}}
+	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: mustParseTimePtr("2021-01-06T09:21:41Z"), CreatedAtEnd: mustParseTimePtr("2021-01-13T09:21:41Z"), TeamIDs: []string{"PCDYDX0"}}
 	analyticsResponse := AnalyticsResponse{
 		Data:          []AnalyticsData{analyticsDataWanted},
 		Filters:       &analyticsFilterWanted,
@@ -147,15 +147,15 @@ func TestAnalytics_GetAggregatedEscalationPolicyData(t *testing.T) {
 
 	analyticsRequest := AnalyticsRequest{
 		Filters: &AnalyticsFilter{
-			CreatedAtStart:      "2021-01-01T15:00:32Z",
-			CreatedAtEnd:        "2021-01-08T15:00:32Z",
+			CreatedAtStart:      mustParseTimePtr("2021-01-01T15:00:32Z"),
+			CreatedAtEnd:        mustParseTimePtr("2021-01-08T15:00:32Z"),
 			EscalationPolicyIDs: []string{"PCDYDX0"},
 		},
 		AggregateUnit: "day",
 		TimeZone:      "Etc/UTC",
 	}
 	analyticsDataWanted := AnalyticsData{MeanAssignmentCount: 1, MeanEngagedSeconds: 502, MeanEngagedUserCount: 0, MeanSecondsToResolve: 34550, MeanSecondsToFirstAck: 70, TotalBusinessHourInterruptions: 1, TotalEngagedSeconds: 2514, TotalIncidentCount: 5, RangeStart: "2021-01-06T00:00:00.000000", EscalationPolicyID: "PCDYDX0", EscalationPolicyName: "FooEscalationPolicy", UpTimePct: 89.86111111111111}
-	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: "2021-01-06T09:21:41Z", CreatedAtEnd: "2021-01-13T09:21:41Z", EscalationPolicyIDs: []string{"PCDYDX0"}}
+	analyticsFilterWanted := AnalyticsFilter{CreatedAtStart: mustParseTimePtr("2021-01-06T09:21:41Z"), CreatedAtEnd: mustParseTimePtr("2021-01-13T09:21:41Z"), EscalationPolicyIDs: []string{"PCDYDX0"}}
 	analyticsResponse := AnalyticsResponse{
 		Data:          []AnalyticsData{analyticsDataWanted},
 		Filters:       &analyticsFilterWanted,
@@ -273,8 +273,8 @@ func TestAnalytics_GetAnalyticsIncidents(t *testing.T) {
 
 	rawDataRequest := AnalyticsRawIncidentsRequest{
 		Filters: &AnalyticsFilter{
-			CreatedAtStart: "2021-01-01T15:00:32Z",
-			CreatedAtEnd:   "2021-01-08T15:00:32Z",
+			CreatedAtStart: mustParseTimePtr("2021-01-01T15:00:32Z"),
+			CreatedAtEnd:   mustParseTimePtr("2021-01-08T15:00:32Z"),
 			TeamIDs:        []string{"PCDYDX0"},
 		},
 		StartingAfter: "eyJpZCI6IlEwTUlFTUtXTVNYOFZFIiwib3JkZXJfYnkiOiJjcmVhdGVkX2F0IiwidmFsdWUiOiIyMDIzLTA0LTMwVDA4OjU0OjAzIn0=",
@@ -284,7 +284,7 @@ func TestAnalytics_GetAnalyticsIncidents(t *testing.T) {
 		OrderBy:       "created_at",
 		TimeZone:      "Etc/UTC",
 	}
-	rawFilterWanted := AnalyticsFilter{CreatedAtStart: "2021-01-06T09:21:41Z", CreatedAtEnd: "2021-01-13T09:21:41Z", TeamIDs: []string{"PCDYDX0"}}
+	rawFilterWanted := AnalyticsFilter{CreatedAtStart: mustParseTimePtr("2021-01-06T09:21:41Z"), CreatedAtEnd: mustParseTimePtr("2021-01-13T09:21:41Z"), TeamIDs: []string{"PCDYDX0"}}
 	rawDataResponse := AnalyticsRawIncidentsResponse{
 		Data:     []AnalyticsRawIncident{rawDataWanted},
 		Filters:  &rawFilterWanted,
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -46,7 +47,7 @@ type ListAuditRecordsResponse struct {
 type AuditRecord struct {
 	ID               string           `json:"id,omitempty"`
 	Self             string           `json:"self,omitempty"`
-	ExecutionTime    string           `json:"execution_time,omitempty"`
+	ExecutionTime    Time             `json:"execution_time,omitempty"`
 	ExecutionContext ExecutionContext `json:"execution_context,omitempty"`
 	Actors           []APIObject      `json:"actors,omitempty"`
 	Method           Method           `json:"method,omitempty"`
@@ -109,7 +110,7 @@ func (c *Client) ListAuditRecords(ctx context.Context, o ListAuditRecordsOptions
 	}
 
 	u := fmt.Sprintf("%s?%s", auditBaseURL, v.Encode())
-	resp, err := c.get(ctx, u, nil)
+	resp, err := c.get(ctx, "ListAuditRecords", u, nil)
 	if err != nil {
 		return ListAuditRecordsResponse{}, err
 	}
@@ -158,9 +159,23 @@ func (c *Client) ListAuditRecordsPaginated(ctx context.Context, o ListAuditRecor
 	}
 
 	u := fmt.Sprintf("%s?%s", auditBaseURL, v.Encode())
-	if err := c.cursorGet(ctx, u, responseHandler); err != nil {
+	if err := c.cursorGet(ctx, "ListAuditRecordsPaginated", u, responseHandler); err != nil {
 		return nil, err
 	}
 
 	return records, nil
 }
+
+// AuditRecords returns an iterator over the audit trail records matching the
+// provided query params or default criteria, automatically fetching additional
+// pages as needed.
+func (c *Client) AuditRecords(ctx context.Context, o ListAuditRecordsOptions) iter.Seq2[AuditRecord, error] {
+	return cursorSeq(ctx, c, "AuditRecords", auditBaseURL, "cursor", o, func(r *ListAuditRecordsResponse) ([]AuditRecord, cursor) {
+		var next string
+		if r.NextCursor != nil {
+			next = *r.NextCursor
+		}
+
+		return r.Records, cursor{Limit: r.Limit, NextCursor: next}
+	})
+}
@@ -30,7 +30,7 @@ func TestAudit_List(t *testing.T) {
 		Records: []AuditRecord{
 			{
 				ID:            "PDRECORDID4_UPDATED_USERS_NOTIFICATION_RULE",
-				ExecutionTime: "2020-06-04T15:30:16.272Z",
+				ExecutionTime: mustParseTime("2020-06-04T15:30:16.272Z"),
 				ExecutionContext: ExecutionContext{
 					RequestID:     "222lDEOIH-534-4ljhLHJjh222",
 					RemoteAddress: "201.19.20.19",
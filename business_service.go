@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -109,13 +110,21 @@ func (c *Client) ListBusinessServicesPaginated(ctx context.Context, o ListBusine
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/business_services?"+queryParms.Encode(), responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListBusinessServicesPaginated", "/business_services?"+queryParms.Encode(), responseHandler); err != nil {
 		return nil, err
 	}
 
 	return businessServices, nil
 }
 
+// BusinessServices returns an iterator over all of the existing business
+// services, automatically fetching additional pages as needed.
+func (c *Client) BusinessServices(ctx context.Context, o ListBusinessServiceOptions) iter.Seq2[*BusinessService, error] {
+	return offsetSeq(ctx, c, "BusinessServices", "/business_services", o, func(r *ListBusinessServicesResponse) ([]*BusinessService, APIListObject) {
+		return r.BusinessServices, APIListObject{Limit: r.Limit, Offset: r.Offset, More: r.More}
+	})
+}
+
 // CreateBusinessService creates a new business service.
 //
 // Deprecated: Use CreateBusinessServiceWithContext instead
@@ -129,7 +138,7 @@ func (c *Client) CreateBusinessServiceWithContext(ctx context.Context, b *Busine
 		"business_service": b,
 	}
 
-	resp, err := c.post(ctx, "/business_services", d, nil)
+	resp, err := c.post(ctx, "CreateBusinessServiceWithContext", "/business_services", d, nil)
 	return getBusinessServiceFromResponse(resp, err, c.decodeJSON)
 }
 
@@ -142,7 +151,7 @@ func (c *Client) GetBusinessService(id string) (*BusinessService, error) {
 
 // GetBusinessServiceWithContext gets details about a business service.
 func (c *Client) GetBusinessServiceWithContext(ctx context.Context, id string) (*BusinessService, error) {
-	resp, err := c.get(ctx, "/business_services/"+id, nil)
+	resp, err := c.get(ctx, "GetBusinessServiceWithContext", "/business_services/"+id, nil)
 	return getBusinessServiceFromResponse(resp, err, c.decodeJSON)
 }
 
@@ -155,7 +164,7 @@ func (c *Client) DeleteBusinessService(id string) error {
 
 // DeleteBusinessServiceWithContext deletes a business_service.
 func (c *Client) DeleteBusinessServiceWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/business_services/"+id)
+	_, err := c.delete(ctx, "DeleteBusinessServiceWithContext", "/business_services/"+id)
 	return err
 }
 
@@ -175,7 +184,7 @@ func (c *Client) UpdateBusinessServiceWithContext(ctx context.Context, b *Busine
 		"business_service": b,
 	}
 
-	resp, err := c.put(ctx, "/business_services/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateBusinessServiceWithContext", "/business_services/"+id, d, nil)
 	return getBusinessServiceFromResponse(resp, err, c.decodeJSON)
 }
 
@@ -0,0 +1,248 @@
+package pagerduty
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const icalTimeLayout = "20060102T150405Z"
+
+// CalendarGroup is how NewOnCallCalendars groups on-call entries into
+// calendars.
+type CalendarGroup string
+
+// The supported calendar groups.
+const (
+	CalendarGroupUser             CalendarGroup = "user"
+	CalendarGroupSchedule         CalendarGroup = "schedule"
+	CalendarGroupEscalationPolicy CalendarGroup = "escalation_policy"
+)
+
+// CalendarEvent is an on-call shift of a Calendar.
+type CalendarEvent struct {
+	// UID identifies the shift, and is the same every time the shift is
+	// exported, so that calendar applications update the existing event
+	// instead of adding a duplicate.
+	UID string
+
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Calendar is a feed of on-call shifts, which is written as an iCalendar
+// (RFC 5545) calendar using WriteTo.
+type Calendar struct {
+	// ID is the ID of the user, schedule, or escalation policy of the
+	// calendar.
+	ID     string
+	Name   string
+	Events []CalendarEvent
+}
+
+// NewScheduleCalendar returns a calendar with an event per rendered entry of
+// the final schedule, such as returned by GetScheduleWithContext,
+// PreviewScheduleWithContext, or RenderSchedule.
+func NewScheduleCalendar(s *Schedule) (*Calendar, error) {
+	c := &Calendar{ID: s.ID, Name: firstNonEmpty(s.Name, s.Summary, s.ID)}
+
+	for _, e := range s.FinalSchedule.RenderedScheduleEntries {
+		start, end := e.Start.Time, e.End.Time
+
+		c.Events = append(c.Events, CalendarEvent{
+			UID:     calendarUID(s.ID, start.UTC().Format(time.RFC3339)),
+			Start:   start,
+			End:     end,
+			Summary: "On call: " + firstNonEmpty(e.User.Summary, e.User.ID),
+			URL:     s.HTMLURL,
+		})
+	}
+
+	c.sortEvents()
+
+	return c, nil
+}
+
+// NewOnCallCalendars returns a calendar per user, schedule, or escalation
+// policy of the on-call entries, such as returned by ListOnCallsWithContext,
+// in the order they first appear. On-call entries without an end, such as
+// when users are targeted directly by escalation policies, and entries
+// without a schedule when grouping by schedule, are left out.
+//
+// The UIDs of the events of schedule and escalation policy calendars don't
+// depend on the user on call, so that reassigning a shift, such as with an
+// override, updates its event. A shift of a schedule used by several
+// escalation policies is included once in the schedule's calendar.
+func NewOnCallCalendars(oncalls []OnCall, group CalendarGroup) ([]*Calendar, error) {
+	switch group {
+	case CalendarGroupUser, CalendarGroupSchedule, CalendarGroupEscalationPolicy:
+	default:
+		return nil, fmt.Errorf("unknown calendar group %q", group)
+	}
+
+	var calendars []*Calendar
+	byID := make(map[string]*Calendar)
+	seen := make(map[string]bool)
+
+	for _, oc := range oncalls {
+		if oc.Start.IsZero() || oc.End.IsZero() {
+			continue
+		}
+
+		start, end := oc.Start.Time, oc.End.Time
+
+		user := firstNonEmpty(oc.User.Name, oc.User.Summary, oc.User.ID)
+		schedule := firstNonEmpty(oc.Schedule.Name, oc.Schedule.Summary, oc.Schedule.ID)
+		policy := firstNonEmpty(oc.EscalationPolicy.Name, oc.EscalationPolicy.Summary, oc.EscalationPolicy.ID)
+
+		level := strconv.FormatUint(uint64(oc.EscalationLevel), 10)
+		startUTC := start.UTC().Format(time.RFC3339)
+
+		var id, name, summary, uid string
+		switch group {
+		case CalendarGroupUser:
+			id, name = oc.User.ID, user
+			summary = fmt.Sprintf("On call: %s (level %d)", policy, oc.EscalationLevel)
+			uid = calendarUID(oc.EscalationPolicy.ID, level, oc.Schedule.ID, oc.User.ID, startUTC)
+		case CalendarGroupSchedule:
+			if oc.Schedule.ID == "" {
+				continue
+			}
+			id, name = oc.Schedule.ID, schedule
+			summary = "On call: " + user
+			uid = calendarUID(oc.Schedule.ID, startUTC)
+		case CalendarGroupEscalationPolicy:
+			id, name = oc.EscalationPolicy.ID, policy
+			summary = fmt.Sprintf("Level %d: %s", oc.EscalationLevel, user)
+			uid = calendarUID(oc.EscalationPolicy.ID, level, oc.Schedule.ID, startUTC)
+		}
+
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+
+		c, ok := byID[id]
+		if !ok {
+			c = &Calendar{ID: id, Name: name}
+			byID[id] = c
+			calendars = append(calendars, c)
+		}
+
+		description := []string{"User: " + user, "Escalation policy: " + policy, "Level: " + level}
+		if schedule != "" {
+			description = append(description, "Schedule: "+schedule)
+		}
+
+		c.Events = append(c.Events, CalendarEvent{
+			UID:         uid,
+			Start:       start,
+			End:         end,
+			Summary:     summary,
+			Description: strings.Join(description, "\n"),
+			URL:         firstNonEmpty(oc.Schedule.HTMLURL, oc.EscalationPolicy.HTMLURL),
+		})
+	}
+
+	for _, c := range calendars {
+		c.sortEvents()
+	}
+
+	return calendars, nil
+}
+
+// WriteTo writes the calendar to w as an iCalendar (RFC 5545) calendar, with
+// an event per shift. It implements the io.WriterTo interface.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICalLine(name + ":" + value))
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//PagerDuty//go-pagerduty//EN")
+	line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeICalText(c.Name))
+	}
+
+	stamp := time.Now().UTC().Format(icalTimeLayout)
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp)
+		line("DTSTART", e.Start.UTC().Format(icalTimeLayout))
+		line("DTEND", e.End.UTC().Format(icalTimeLayout))
+		line("SUMMARY", escapeICalText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeICalText(e.Description))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (c *Calendar) sortEvents() {
+	sort.SliceStable(c.Events, func(i, j int) bool {
+		if !c.Events[i].Start.Equal(c.Events[j].Start) {
+			return c.Events[i].Start.Before(c.Events[j].Start)
+		}
+
+		return c.Events[i].UID < c.Events[j].UID
+	})
+}
+
+// calendarUID returns a UID derived from the parts identifying an event.
+func calendarUID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16]) + "@pagerduty.com"
+}
+
+// escapeICalText escapes the text of a property value.
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICalLine returns the content line terminated by CRLF, folded so that no
+// line is longer than 75 octets, without splitting UTF-8 characters.
+func foldICalLine(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
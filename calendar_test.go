@@ -0,0 +1,147 @@
+package pagerduty
+
+import (
+	"strings"
+	"testing"
+)
+
+func testOnCalls() []OnCall {
+	policy := EscalationPolicy{APIObject: APIObject{ID: "PEP1", Summary: "Default"}}
+	primary := Schedule{APIObject: APIObject{ID: "PS1", Summary: "Primary", HTMLURL: "https://example.pagerduty.com/schedules/PS1"}}
+
+	return []OnCall{
+		{
+			User:             User{APIObject: APIObject{ID: "PU2", Summary: "Bob"}},
+			Schedule:         primary,
+			EscalationPolicy: policy,
+			EscalationLevel:  1,
+			Start:            mustParseTime("2026-01-02T00:00:00Z"),
+			End:              mustParseTime("2026-01-03T00:00:00Z"),
+		},
+		{
+			User:             User{APIObject: APIObject{ID: "PU1", Summary: "Alice"}},
+			Schedule:         primary,
+			EscalationPolicy: policy,
+			EscalationLevel:  1,
+			Start:            mustParseTime("2026-01-01T00:00:00Z"),
+			End:              mustParseTime("2026-01-02T00:00:00Z"),
+		},
+		{
+			// always on call, as a target of the escalation policy
+			User:             User{APIObject: APIObject{ID: "PU1", Summary: "Alice"}},
+			EscalationPolicy: policy,
+			EscalationLevel:  2,
+		},
+	}
+}
+
+func TestNewOnCallCalendars(t *testing.T) {
+	calendars, err := NewOnCallCalendars(testOnCalls(), CalendarGroupUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, 2, len(calendars))
+	testEqual(t, "PU2", calendars[0].ID)
+	testEqual(t, "Bob", calendars[0].Name)
+	testEqual(t, "PU1", calendars[1].ID)
+
+	testEqual(t, []CalendarEvent{{
+		UID:         calendarUID("PEP1", "1", "PS1", "PU1", "2026-01-01T00:00:00Z"),
+		Start:       testTime(t, "2026-01-01T00:00:00Z"),
+		End:         testTime(t, "2026-01-02T00:00:00Z"),
+		Summary:     "On call: Default (level 1)",
+		Description: "User: Alice\nEscalation policy: Default\nLevel: 1\nSchedule: Primary",
+		URL:         "https://example.pagerduty.com/schedules/PS1",
+	}}, calendars[1].Events)
+
+	calendars, err = NewOnCallCalendars(testOnCalls(), CalendarGroupEscalationPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, 1, len(calendars))
+	testEqual(t, "Default", calendars[0].Name)
+	testEqual(t, 2, len(calendars[0].Events))
+	testEqual(t, "Level 1: Alice", calendars[0].Events[0].Summary)
+	testEqual(t, "Level 1: Bob", calendars[0].Events[1].Summary)
+
+	testEqual(t, calendarUID("PEP1", "1", "PS1", "2026-01-01T00:00:00Z"), calendars[0].Events[0].UID)
+
+	// the shift is included once in the schedule's calendar, although it's
+	// listed for each escalation policy using the schedule
+	oncalls := testOnCalls()
+	other := oncalls[1]
+	other.EscalationPolicy = EscalationPolicy{APIObject: APIObject{ID: "PEP2", Summary: "Other"}}
+
+	calendars, err = NewOnCallCalendars(append(oncalls, other), CalendarGroupSchedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, 1, len(calendars))
+	testEqual(t, 2, len(calendars[0].Events))
+
+	testEqual(t, calendarUID("PS1", "2026-01-01T00:00:00Z"), calendars[0].Events[0].UID)
+
+	// the UIDs of schedule and escalation policy calendars don't depend on the
+	// user on call, so that an event is updated when its shift is reassigned
+	reassigned := testOnCalls()
+	reassigned[1].User = User{APIObject: APIObject{ID: "PU3", Summary: "Carol"}}
+
+	for _, group := range []CalendarGroup{CalendarGroupSchedule, CalendarGroupEscalationPolicy} {
+		before, err := NewOnCallCalendars(testOnCalls(), group)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		after, err := NewOnCallCalendars(reassigned, group)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testEqual(t, before[0].Events[0].UID, after[0].Events[0].UID)
+	}
+
+	_, err = NewOnCallCalendars(nil, "team")
+	testErrCheck(t, "NewOnCallCalendars()", `unknown calendar group "team"`, err)
+}
+
+func TestCalendar_WriteTo(t *testing.T) {
+	s := &Schedule{
+		APIObject: APIObject{ID: "PS1"},
+		Name:      "Primary, EMEA",
+		FinalSchedule: ScheduleLayer{RenderedScheduleEntries: []RenderedScheduleEntry{
+			// the same instant as 2026-01-01T00:00:00Z
+			{Start: mustParseTime("2026-01-01T01:00:00+01:00"), End: mustParseTime("2026-01-02T00:00:00Z"), User: APIObject{ID: "PU1", Summary: "Alice"}},
+		}},
+	}
+
+	c, err := NewScheduleCalendar(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, calendarUID("PS1", "2026-01-01T00:00:00Z"), c.Events[0].UID)
+
+	var b strings.Builder
+	if _, err := c.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	ics := b.String()
+	for _, s := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Primary\\, EMEA\r\n",
+		"UID:" + c.Events[0].UID + "\r\n",
+		"DTSTART:20260101T000000Z\r\nDTEND:20260102T000000Z\r\nSUMMARY:On call: Alice\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, s) {
+			t.Errorf("WriteTo() = %q, should contain %q", ics, s)
+		}
+	}
+
+	testEqual(t, "abc\r\n", foldICalLine("abc"))
+	testEqual(t, strings.Repeat("a", 75)+"\r\n "+"é\r\n", foldICalLine(strings.Repeat("a", 75)+"é"))
+}
@@ -0,0 +1,372 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// The CI/CD providers whose metadata is detected by DetectCIMetadata.
+const (
+	CIProviderGitHubActions = "GitHub Actions"
+	CIProviderGitLab        = "GitLab CI"
+	CIProviderJenkins       = "Jenkins"
+	CIProviderBuildkite     = "Buildkite"
+)
+
+// CIMetadata describes a CI/CD build, such as a deploy, from which a change
+// event can be created. It's usually detected using DetectCIMetadata, but its
+// fields can be set or overridden before creating the change event.
+type CIMetadata struct {
+	// Provider is the name of the CI/CD provider running the build, such as
+	// CIProviderGitHubActions, or empty when the build doesn't run in a known
+	// CI/CD provider.
+	Provider string
+
+	// Pipeline is the name of the pipeline, workflow, or job running the
+	// build.
+	Pipeline string
+
+	// BuildNumber and BuildURL identify the build in the CI/CD provider.
+	BuildNumber string
+	BuildURL    string
+
+	// Repository is the name of the repository being built, such as
+	// "PagerDuty/go-pagerduty", or its URL when its name isn't known. The
+	// credentials of URLs are removed before they're added to change events.
+	Repository string
+
+	// Commit is the full hash of the commit being built, and CommitURL the
+	// URL to view it, when known.
+	Commit    string
+	CommitURL string
+
+	// CommitMessage is the subject of the message of the commit being built.
+	CommitMessage string
+
+	// Branch or Tag is the git ref being built.
+	Branch string
+	Tag    string
+
+	// Actor is the user who triggered the build, or authored the commit.
+	Actor string
+
+	// Environment is the name of the environment being deployed to, when
+	// known.
+	Environment string
+}
+
+// DetectCIMetadata returns the metadata of the CI/CD build the process runs in,
+// using the environment variables set by GitHub Actions, GitLab CI, Jenkins,
+// and Buildkite. Metadata not provided by the environment is looked up in the
+// git repository of the working directory, if any.
+func DetectCIMetadata(ctx context.Context) CIMetadata {
+	m := ciMetadataFromEnv(os.Getenv)
+	m.addGitMetadata(ctx, gitOutput)
+
+	return m
+}
+
+// ciMetadataFromEnv returns the metadata of the CI/CD build, using the
+// environment variables set by the CI/CD provider.
+func ciMetadataFromEnv(getenv func(string) string) CIMetadata {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		m := CIMetadata{
+			Provider:    CIProviderGitHubActions,
+			Pipeline:    getenv("GITHUB_WORKFLOW"),
+			BuildNumber: getenv("GITHUB_RUN_NUMBER"),
+			Repository:  getenv("GITHUB_REPOSITORY"),
+			Commit:      getenv("GITHUB_SHA"),
+			Actor:       getenv("GITHUB_ACTOR"),
+		}
+
+		if getenv("GITHUB_REF_TYPE") == "tag" {
+			m.Tag = getenv("GITHUB_REF_NAME")
+		} else {
+			m.Branch = getenv("GITHUB_REF_NAME")
+		}
+
+		if server, repo := getenv("GITHUB_SERVER_URL"), m.Repository; server != "" && repo != "" {
+			if id := getenv("GITHUB_RUN_ID"); id != "" {
+				m.BuildURL = server + "/" + repo + "/actions/runs/" + id
+			}
+
+			if m.Commit != "" {
+				m.CommitURL = server + "/" + repo + "/commit/" + m.Commit
+			}
+		}
+
+		return m
+
+	case getenv("GITLAB_CI") == "true":
+		m := CIMetadata{
+			Provider:      CIProviderGitLab,
+			Pipeline:      getenv("CI_JOB_NAME"),
+			BuildNumber:   getenv("CI_PIPELINE_IID"),
+			BuildURL:      getenv("CI_PIPELINE_URL"),
+			Repository:    getenv("CI_PROJECT_PATH"),
+			Commit:        getenv("CI_COMMIT_SHA"),
+			CommitMessage: getenv("CI_COMMIT_TITLE"),
+			Branch:        getenv("CI_COMMIT_BRANCH"),
+			Tag:           getenv("CI_COMMIT_TAG"),
+			Actor:         getenv("GITLAB_USER_LOGIN"),
+			Environment:   getenv("CI_ENVIRONMENT_NAME"),
+		}
+
+		if url := getenv("CI_PROJECT_URL"); url != "" && m.Commit != "" {
+			m.CommitURL = url + "/-/commit/" + m.Commit
+		}
+
+		return m
+
+	case getenv("BUILDKITE") == "true":
+		return CIMetadata{
+			Provider:      CIProviderBuildkite,
+			Pipeline:      getenv("BUILDKITE_PIPELINE_SLUG"),
+			BuildNumber:   getenv("BUILDKITE_BUILD_NUMBER"),
+			BuildURL:      getenv("BUILDKITE_BUILD_URL"),
+			Repository:    redactURL(getenv("BUILDKITE_REPO")),
+			Commit:        getenv("BUILDKITE_COMMIT"),
+			CommitMessage: firstLine(getenv("BUILDKITE_MESSAGE")),
+			Branch:        getenv("BUILDKITE_BRANCH"),
+			Tag:           getenv("BUILDKITE_TAG"),
+			Actor:         getenv("BUILDKITE_BUILD_CREATOR"),
+		}
+
+	case getenv("JENKINS_URL") != "":
+		return CIMetadata{
+			Provider:    CIProviderJenkins,
+			Pipeline:    getenv("JOB_NAME"),
+			BuildNumber: getenv("BUILD_NUMBER"),
+			BuildURL:    getenv("BUILD_URL"),
+			Repository:  redactURL(getenv("GIT_URL")),
+			Commit:      getenv("GIT_COMMIT"),
+			// the Git plugin sets the branch of the remote (e.g.,
+			// "origin/main")
+			Branch: strings.TrimPrefix(getenv("GIT_BRANCH"), "origin/"),
+		}
+
+	default:
+		return CIMetadata{}
+	}
+}
+
+// addGitMetadata sets the fields which are empty using the git repository of
+// the working directory. The git function returns the output of the git
+// command with the arguments, or an empty string if it failed.
+func (m *CIMetadata) addGitMetadata(ctx context.Context, git func(ctx context.Context, args ...string) string) {
+	if m.Commit == "" {
+		m.Commit = git(ctx, "rev-parse", "HEAD")
+	}
+
+	if m.Commit == "" {
+		// not in a git repository, or git isn't installed
+		return
+	}
+
+	if m.CommitMessage == "" {
+		m.CommitMessage = git(ctx, "log", "-1", "--format=%s", m.Commit)
+	}
+
+	if m.Actor == "" {
+		m.Actor = git(ctx, "log", "-1", "--format=%an", m.Commit)
+	}
+
+	if m.Repository == "" {
+		m.Repository = redactURL(git(ctx, "config", "--get", "remote.origin.url"))
+	}
+
+	if m.Branch == "" && m.Tag == "" {
+		if branch := git(ctx, "rev-parse", "--abbrev-ref", "HEAD"); branch != "HEAD" {
+			m.Branch = branch
+		}
+	}
+}
+
+// gitOutput returns the trimmed output of the git command with the arguments,
+// or an empty string if it failed.
+func gitOutput(ctx context.Context, args ...string) string {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// redactURL returns s without the user information, such as an access token,
+// if it's a URL. Other values, including scp-like git remotes such as
+// "git@github.com:PagerDuty/go-pagerduty.git", are returned as is.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+
+	u.User = nil
+
+	return u.String()
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
+
+// ref returns the branch or tag being built.
+func (m CIMetadata) ref() string {
+	if m.Tag != "" {
+		return m.Tag
+	}
+
+	return m.Branch
+}
+
+// ChangeEvent returns a change event describing the build, with its summary,
+// source, timestamp, links, and custom details populated from the metadata.
+// The routing key of the change event isn't set.
+func (m CIMetadata) ChangeEvent() ChangeEvent {
+	// the fields may have been set by the caller, rather than detected
+	m.Repository = redactURL(m.Repository)
+
+	var summary strings.Builder
+
+	summary.WriteString("Deployed")
+
+	switch commit := shortCommit(m.Commit); {
+	case m.Repository != "" && commit != "":
+		summary.WriteString(" " + m.Repository + "@" + commit)
+
+	case m.Repository != "" || commit != "":
+		summary.WriteString(" " + m.Repository + commit)
+	}
+
+	if ref := m.ref(); ref != "" {
+		summary.WriteString(" (" + ref + ")")
+	}
+
+	if m.Environment != "" {
+		summary.WriteString(" to " + m.Environment)
+	}
+
+	if m.CommitMessage != "" {
+		summary.WriteString(": " + m.CommitMessage)
+	}
+
+	source := m.Provider
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+
+	e := ChangeEvent{
+		Payload: ChangeEventPayload{
+			Summary:       truncate(summary.String(), v2MaxSummaryLength),
+			Source:        source,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			CustomDetails: m.customDetails(),
+		},
+	}
+
+	if m.BuildURL != "" {
+		text := "View build"
+		if m.Provider != "" {
+			text = "View build in " + m.Provider
+		}
+
+		e.Links = append(e.Links, ChangeEventLink{Href: m.BuildURL, Text: text})
+	}
+
+	if m.CommitURL != "" {
+		e.Links = append(e.Links, ChangeEventLink{Href: m.CommitURL, Text: "View commit " + shortCommit(m.Commit)})
+	}
+
+	return e
+}
+
+// customDetails returns the fields of the metadata which are set.
+func (m CIMetadata) customDetails() map[string]interface{} {
+	details := make(map[string]interface{})
+
+	add := func(key, value string) {
+		if value != "" {
+			details[key] = value
+		}
+	}
+
+	add("ci_provider", m.Provider)
+	add("pipeline", m.Pipeline)
+	add("build_number", m.BuildNumber)
+	add("build_url", m.BuildURL)
+	add("repository", m.Repository)
+	add("commit", m.Commit)
+	add("commit_message", m.CommitMessage)
+	add("branch", m.Branch)
+	add("tag", m.Tag)
+	add("actor", m.Actor)
+	add("environment", m.Environment)
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	return details
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+
+	return commit
+}
+
+// truncate returns s truncated to at most n characters.
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+
+	return s
+}
+
+// CreateChangeEventsWithContext sends the change event to each of the routing
+// keys, such as for a deploy affecting multiple services, overriding the
+// routing key of the change event. It returns the response for each routing
+// key, in the same order, which is nil for the routing keys the change event
+// couldn't be sent to. The errors for those routing keys are joined, and
+// identify each of them by their index.
+//
+// The change event is sent to one routing key at a time, in order, and failing
+// to send it to a routing key doesn't stop it from being sent to the rest. So
+// when an error is returned, the change event may still have been sent to some
+// of the routing keys, which are those with a response.
+func (c *Client) CreateChangeEventsWithContext(ctx context.Context, e ChangeEvent, routingKeys []string) ([]*ChangeEventResponse, error) {
+	responses := make([]*ChangeEventResponse, len(routingKeys))
+
+	var errs []error
+	for i, key := range routingKeys {
+		e.RoutingKey = key
+
+		resp, err := c.CreateChangeEventWithContext(ctx, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send change event to routing key %d: %w", i, err))
+			continue
+		}
+
+		responses[i] = resp
+	}
+
+	return responses, errors.Join(errs...)
+}
+
+// CreateChangeEventsWithContext sends the change event to each of the routing
+// keys. See Client.CreateChangeEventsWithContext.
+func (c *EventsClient) CreateChangeEventsWithContext(ctx context.Context, e ChangeEvent, routingKeys []string) ([]*ChangeEventResponse, error) {
+	return c.client.CreateChangeEventsWithContext(ctx, e, routingKeys)
+}
@@ -64,7 +64,7 @@ func (c *Client) CreateChangeEventWithContext(ctx context.Context, e ChangeEvent
 	}
 
 	resp, err := c.doWithEndpoint(
-		ctx,
+		ctx, "CreateChangeEventWithContext",
 		c.v2EventsAPIEndpoint,
 		http.MethodPost,
 		changeEventPath,
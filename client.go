@@ -14,12 +14,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net"
 	"net/http"
+	"net/url"
 	"path"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -275,16 +274,6 @@ type HTTPClient interface {
 // Keep this unexported so consumers of the package can't make changes to it.
 var defaultHTTPClient HTTPClient = newDefaultHTTPClient()
 
-type retryPolicy struct {
-	MaxDelay   time.Duration
-	MaxRetries int
-}
-
-var defaultRetryPolicy = retryPolicy{
-	MaxDelay:   20 * time.Second,
-	MaxRetries: 0,
-}
-
 // Client wraps http client
 type Client struct {
 	debugFlag    *uint64
@@ -303,10 +292,18 @@ type Client struct {
 	// HTTPClient is the HTTP client used for making requests against the
 	// PagerDuty API. You can use either *http.Client here, or your own
 	// implementation.
-	HTTPClient  HTTPClient
-	retryPolicy retryPolicy
+	HTTPClient    HTTPClient
+	retryStrategy RetryStrategy
+	rateLimiter   *rateLimiter
+	middleware    []Middleware
+	hooks         hookList
 
 	userAgent string
+
+	// eventsAPIErrors configures the client to return an EventsAPIV2Error,
+	// instead of an APIError, for unsuccessful responses. It's set by
+	// NewEventsClient.
+	eventsAPIErrors bool
 }
 
 // NewClient creates an API client using an account/user API token
@@ -320,7 +317,6 @@ func NewClient(authToken string, options ...ClientOptions) *Client {
 		v2EventsAPIEndpoint: v2EventsAPIEndpoint,
 		authType:            apiToken,
 		HTTPClient:          defaultHTTPClient,
-		retryPolicy:         defaultRetryPolicy,
 	}
 
 	for _, opt := range options {
@@ -347,12 +343,25 @@ func WithAPIEndpoint(endpoint string) ClientOptions {
 
 // WithRetryPolicy configures the client with a retry policy. Configuring a
 // retry policy on the client is currently experimental and should be used with care.
+//
+// Rate limited requests are retried, as well as idempotent requests (see
+// IsIdempotentRequest) after network errors and 5xx responses, waiting
+// 2^attempt seconds capped to maxDelaySeconds between attempts. Non-idempotent
+// requests, such as creating or merging incidents, aren't retried after
+// network errors and 5xx responses, as they may have been actioned by the API.
+// Use WithRetryStrategy() for jittered backoff, or to retry them anyway.
 func WithRetryPolicy(maxRetryAttempts int, maxDelaySeconds int) ClientOptions {
+	return WithRetryStrategy(retryPolicy{
+		MaxRetries: maxRetryAttempts,
+		MaxDelay:   time.Duration(maxDelaySeconds) * time.Second,
+	})
+}
+
+// WithRetryStrategy configures the client to retry failed requests using the
+// provided RetryStrategy. By default, the client doesn't retry requests.
+func WithRetryStrategy(strategy RetryStrategy) ClientOptions {
 	return func(c *Client) {
-		c.retryPolicy = retryPolicy{
-			MaxDelay:   time.Duration(maxDelaySeconds) * time.Second,
-			MaxRetries: maxRetryAttempts,
-		}
+		c.retryStrategy = strategy
 	}
 }
 
@@ -371,6 +380,23 @@ func WithV2EventsAPIEndpoint(endpoint string) ClientOptions {
 	}
 }
 
+// WithHTTPClient configures the client to make requests using the provided HTTP
+// client, instead of the default one of this package. This is equivalent to
+// setting the HTTPClient field of the client.
+func WithHTTPClient(client HTTPClient) ClientOptions {
+	return func(c *Client) {
+		c.HTTPClient = client
+	}
+}
+
+// WithUserAgent configures the client to send the provided User-Agent header,
+// instead of the default one identifying this package.
+func WithUserAgent(userAgent string) ClientOptions {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
 // WithOAuth allows for an OAuth token to be passed into the the client
 func WithOAuth() ClientOptions {
 	return func(c *Client) {
@@ -460,7 +486,8 @@ func (c *Client) debugCaptureResponse() bool {
 // and not the one they anticipated.
 //
 // The *http.Request made within the Do() method is not captured by the client,
-// and thus won't be returned by this method.
+// and thus won't be returned by this method. To inspect every request when the
+// *Client is used concurrently, see WithMiddleware() instead.
 func (c *Client) LastAPIRequest() (*http.Request, bool) {
 	v := c.lastRequest.Load()
 	if v == nil {
@@ -519,36 +546,36 @@ func (c *Client) Do(r *http.Request, authRequired bool) (*http.Response, error)
 	return c.HTTPClient.Do(r)
 }
 
-func (c *Client) delete(ctx context.Context, path string) (*http.Response, error) {
-	return c.do(ctx, http.MethodDelete, path, nil, nil)
+func (c *Client) delete(ctx context.Context, op, path string) (*http.Response, error) {
+	return c.do(ctx, op, http.MethodDelete, path, nil, nil)
 }
 
 // deleteWithHeaders is like delete but allows passing custom headers
-func (c *Client) deleteWithHeaders(ctx context.Context, path string, headers map[string]string) (*http.Response, error) {
-	return c.do(ctx, http.MethodDelete, path, nil, headers)
+func (c *Client) deleteWithHeaders(ctx context.Context, op, path string, headers map[string]string) (*http.Response, error) {
+	return c.do(ctx, op, http.MethodDelete, path, nil, headers)
 }
 
-func (c *Client) put(ctx context.Context, path string, payload interface{}, headers map[string]string) (*http.Response, error) {
+func (c *Client) put(ctx context.Context, op, path string, payload interface{}, headers map[string]string) (*http.Response, error) {
 	if payload != nil {
 		data, err := json.Marshal(payload)
 		if err != nil {
 			return nil, err
 		}
-		return c.do(ctx, http.MethodPut, path, bytes.NewBuffer(data), headers)
+		return c.do(ctx, op, http.MethodPut, path, bytes.NewBuffer(data), headers)
 	}
-	return c.do(ctx, http.MethodPut, path, nil, headers)
+	return c.do(ctx, op, http.MethodPut, path, nil, headers)
 }
 
-func (c *Client) post(ctx context.Context, path string, payload interface{}, headers map[string]string) (*http.Response, error) {
+func (c *Client) post(ctx context.Context, op, path string, payload interface{}, headers map[string]string) (*http.Response, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
-	return c.do(ctx, http.MethodPost, path, bytes.NewBuffer(data), headers)
+	return c.do(ctx, op, http.MethodPost, path, bytes.NewBuffer(data), headers)
 }
 
-func (c *Client) get(ctx context.Context, path string, headers map[string]string) (*http.Response, error) {
-	return c.do(ctx, http.MethodGet, path, nil, headers)
+func (c *Client) get(ctx context.Context, op, path string, headers map[string]string) (*http.Response, error) {
+	return c.do(ctx, op, http.MethodGet, path, nil, headers)
 }
 
 const (
@@ -610,34 +637,107 @@ func dupeRequest(r *http.Request) (*http.Request, error) {
 }
 
 // needed where pagerduty use a different endpoint for certain actions (eg: v2 events)
+//
+// The op is the name of the client method making the API call, which is passed
+// to the hooks, and is available to the middleware using OperationFromContext.
 func (c *Client) doWithEndpoint(
 	ctx context.Context,
+	op,
 	endpoint,
 	method,
 	path string,
 	authRequired bool,
 	body io.Reader,
 	headers map[string]string,
+) (*http.Response, error) {
+	ctx = withOperation(ctx, op)
+
+	operation := Operation{
+		Name:     op,
+		Method:   method,
+		Endpoint: endpoint,
+	}
+	operation.Path, _, _ = strings.Cut(path, "?")
+
+	ctx = c.hooks.operationStart(ctx, operation)
+
+	resp, err := c.doAttempts(ctx, operation, authRequired, body, path, headers)
+
+	c.hooks.operationEnd(ctx, operation, resp, err)
+
+	return resp, err
+}
+
+func (c *Client) doAttempts(
+	ctx context.Context,
+	op Operation,
+	authRequired bool,
+	body io.Reader,
+	path string,
+	headers map[string]string,
 ) (*http.Response, error) {
 	var resp *http.Response
 	var respErr error
 
+	// An io.Reader can only be consumed once, so buffer the request body to be
+	// able to send the same payload on each attempt.
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	bucket := c.rateLimitBucket(authRequired)
+
 	// Attempt with optional retries
 	for attempt := 0; ; attempt++ {
-		// Build a new request for each atempt.
-		req, err := http.NewRequestWithContext(ctx, method, endpoint+path, body)
+		// Build a new request for each atempt. Using a *bytes.Reader as the
+		// body also sets the request's GetBody, so that the body can be
+		// replayed if the HTTP client needs to (e.g., on redirects).
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequestWithContext(withAttempt(ctx, attempt), op.Method, op.Endpoint+path, reqBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build request: %w", err)
 		}
 
+		if bucket != nil {
+			wait, err := bucket.wait(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if wait > 0 {
+				c.hooks.rateLimitWait(ctx, op, wait)
+			}
+		}
+
 		resp, respErr = c.doSingleRequest(req, authRequired, headers)
 
+		if bucket != nil && resp != nil {
+			bucket.update(resp)
+		}
+
 		// Handle retry if applicable
-		shouldRetry, delay := c.shouldRetry(resp, respErr, attempt)
+		shouldRetry, delay := c.shouldRetry(req, resp, respErr, attempt)
 		if !shouldRetry {
 			break
 		}
 
+		c.hooks.retry(ctx, op, attempt, delay)
+
+		// discard the response we're not going to return, so the underlying
+		// connection can be reused by the next attempt
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
 		select {
 		case <-time.After(delay):
 			continue
@@ -653,6 +753,10 @@ func (c *Client) doWithEndpoint(
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if c.eventsAPIErrors {
+			return resp, getEventsAPIV2ErrorFromResponse(resp)
+		}
+
 		return resp, c.getErrorFromResponse(resp)
 	}
 
@@ -692,7 +796,7 @@ func (c *Client) doSingleRequest(
 		}
 	}
 
-	resp, err = c.HTTPClient.Do(req)
+	resp, err = c.roundTrip(req)
 	if err != nil {
 		return nil, fmt.Errorf("error calling the API endpoint: %v", err)
 	}
@@ -701,40 +805,20 @@ func (c *Client) doSingleRequest(
 }
 
 // shouldRetry reports whether a request should be retried, and if so, how long to wait before retrying.
-func (c *Client) shouldRetry(resp *http.Response, err error, attempt int) (shouldRetry bool, delay time.Duration) {
-	if attempt >= c.retryPolicy.MaxRetries {
+func (c *Client) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (shouldRetry bool, delay time.Duration) {
+	if c.retryStrategy == nil {
 		return false, 0
 	}
 
-	// For now we only retry on a few known error conditions such as the network errors,
-	// 5xx responses, and rate limiting.
-	if err != nil || resp.StatusCode >= 500 {
-		return true, calculateRetryDelay(attempt, c.retryPolicy)
-	} else if resp.StatusCode == http.StatusTooManyRequests {
-		// The REST API rate limits usually return an indication of how long to wait before retrying
-		resetStr := resp.Header.Get("ratelimit-reset")
-		if delaySeconds, err := strconv.Atoi(resetStr); err == nil {
-			return true, time.Duration(delaySeconds) * time.Second
-		}
-		// otherwise use the default retry delay
-		return true, calculateRetryDelay(attempt, c.retryPolicy)
-	} else {
+	if v, _ := req.Context().Value(noRetryCtxKey{}).(bool); v {
 		return false, 0
 	}
-}
 
-// calculateRetryDelay uses a binary exponential backoff with jitter algorithm
-func calculateRetryDelay(attempt int, retryPolicy retryPolicy) time.Duration {
-	delay := time.Duration(math.Exp2(float64(attempt))) * time.Second
-	if delay > retryPolicy.MaxDelay {
-		delay = retryPolicy.MaxDelay
-	}
-
-	return delay
+	return c.retryStrategy.Retry(req, resp, err, attempt)
 }
 
-func (c *Client) do(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	return c.doWithEndpoint(ctx, c.apiEndpoint, method, path, true, body, headers)
+func (c *Client) do(ctx context.Context, op, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	return c.doWithEndpoint(ctx, op, c.apiEndpoint, method, path, true, body, headers)
 }
 
 func (c *Client) decodeJSON(resp *http.Response, payload interface{}) error {
@@ -800,7 +884,7 @@ func getBasePrefix(basePath string) string {
 // a specific slice. The responseHandler is responsible for closing the response.
 type responseHandler func(response *http.Response) (APIListObject, error)
 
-func (c *Client) pagedGet(ctx context.Context, basePath string, handler responseHandler) error {
+func (c *Client) pagedGet(ctx context.Context, op, basePath string, handler responseHandler) error {
 	// Indicates whether there are still additional pages associated with request.
 	var stillMore bool
 
@@ -810,7 +894,7 @@ func (c *Client) pagedGet(ctx context.Context, basePath string, handler response
 	basePrefix := getBasePrefix(basePath)
 	// While there are more pages, keep adjusting the offset to get all results.
 	for stillMore, nextOffset = true, 0; stillMore; {
-		response, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%soffset=%d", basePrefix, nextOffset), nil, nil)
+		response, err := c.do(ctx, op, http.MethodGet, fmt.Sprintf("%soffset=%d", basePrefix, nextOffset), nil, nil)
 		if err != nil {
 			return err
 		}
@@ -842,7 +926,14 @@ type cursor struct {
 // At a minimum it must extract the page information for the current page.
 type cursorHandler func(r *http.Response) (cursor, error)
 
-func (c *Client) cursorGet(ctx context.Context, basePath string, handler cursorHandler) error {
+func (c *Client) cursorGet(ctx context.Context, op, basePath string, handler cursorHandler) error {
+	return c.cursorGetWithParam(ctx, op, basePath, "cursor", handler)
+}
+
+// cursorGetWithParam is like cursorGet, but allows the name of the query
+// parameter used to pass the cursor to be specified, for endpoints which don't
+// use the "cursor" parameter (e.g., "after").
+func (c *Client) cursorGetWithParam(ctx context.Context, op, basePath, param string, handler cursorHandler) error {
 	var next string
 
 	basePrefix := getBasePrefix(basePath)
@@ -850,13 +941,13 @@ func (c *Client) cursorGet(ctx context.Context, basePath string, handler cursorH
 	for {
 		var cs string
 		if len(next) > 0 {
-			cs = fmt.Sprintf("cursor=%s", next)
+			cs = fmt.Sprintf("%s=%s", param, url.QueryEscape(next))
 		}
 
 		// The next set of results can be obtained by providing the
 		// NextCursor value from the previous request in a cursor
 		// query parameter on the subsequent request.
-		resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s%s", basePrefix, cs), nil, nil)
+		resp, err := c.do(ctx, op, http.MethodGet, fmt.Sprintf("%s%s", basePrefix, cs), nil, nil)
 		if err != nil {
 			return err
 		}
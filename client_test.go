@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -48,7 +49,6 @@ func defaultTestClient(serverURL, authToken string) *Client {
 		debugFlag:           new(uint64),
 		lastRequest:         &atomic.Value{},
 		lastResponse:        &atomic.Value{},
-		retryPolicy:         defaultRetryPolicy,
 	}
 }
 
@@ -720,7 +720,7 @@ func TestClient_RetriesHandleRequestBodies(t *testing.T) {
 		WithRetryPolicy(2, 1),
 	)
 
-	_, err := client.do(context.Background(), "POST", "/test", strings.NewReader("some data\n"), nil)
+	_, err := client.do(context.Background(), "", "POST", "/test", strings.NewReader("some data\n"), nil)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -730,6 +730,79 @@ func TestClient_RetriesHandleRequestBodies(t *testing.T) {
 	}
 }
 
+func TestClient_RetriesReplayRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	type attempt struct {
+		body   string
+		header http.Header
+	}
+
+	var attempts []attempt
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		h := r.Header.Clone()
+		h.Del("Accept-Encoding") // set by the transport, not by the client
+
+		attempts = append(attempts, attempt{body: string(b), header: h})
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(attempts) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error":{"message":"try again"}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"incident":{"id":"1"}}`))
+	})
+
+	// POST requests are only retried after 5xx responses when opted in
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithRetryStrategy(&FullJitterRetryStrategy{MaxRetries: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}),
+	)
+
+	payload := map[string]string{"title": "The server is on fire."}
+	headers := map[string]string{"From": "foo@example.com"}
+
+	_, err := client.post(context.Background(), "", "/test", payload, headers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+
+	want := attempts[0]
+	if want.body != `{"title":"The server is on fire."}` {
+		t.Fatalf("unexpected body on first attempt: %q", want.body)
+	}
+
+	if got := want.header.Get("From"); got != "foo@example.com" {
+		t.Fatalf("From header = %q, want %q", got, "foo@example.com")
+	}
+
+	for i, got := range attempts[1:] {
+		if got.body != want.body {
+			t.Errorf("attempt %d body = %q, want %q", i+2, got.body, want.body)
+		}
+
+		if diff := cmp.Diff(want.header, got.header); diff != "" {
+			t.Errorf("attempt %d headers differ (-want / +got):\n%s", i+2, diff)
+		}
+	}
+}
+
 func TestClient_RetriesOnVariousConditions(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -779,7 +852,7 @@ func TestClient_RetriesOnVariousConditions(t *testing.T) {
 				w.Write([]byte(`{"empty":"object"}`))
 			})
 
-			resp, err := c.do(context.Background(), "GET", "/test", nil, nil)
+			resp, err := c.do(context.Background(), "", "GET", "/test", nil, nil)
 			testErrCheck(t, "client.do()", "response failed with status code", err)
 
 			defer resp.Body.Close()
@@ -808,7 +881,7 @@ func TestClient_UserAgentDefault(t *testing.T) {
 
 	client := defaultTestClient(server.URL, "foo")
 
-	_, err := client.do(context.Background(), "GET", "/foo", nil, nil)
+	_, err := client.do(context.Background(), "", "GET", "/foo", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -834,7 +907,7 @@ func TestClient_UserAgentOverwrite(t *testing.T) {
 		WithTerraformProvider(terraformVersion),
 	)
 
-	_, err := client.do(context.Background(), "GET", "/foo", nil, nil)
+	_, err := client.do(context.Background(), "", "GET", "/foo", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -79,9 +79,21 @@ func (c *AnalyticsShow) Run(args []string) int {
 		teamIds[0] = *teamID
 	}
 
+	createdAtStart, err := pagerduty.ParseTime(*start)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
+	createdAtEnd, err := pagerduty.ParseTime(*end)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
 	analyticsFilter := pagerduty.AnalyticsFilter{
-		CreatedAtStart: *start,
-		CreatedAtEnd:   *end,
+		CreatedAtStart: &createdAtStart,
+		CreatedAtEnd:   &createdAtEnd,
 		Urgency:        *urgency,
 		ServiceIDs:     serviceIds,
 		TeamIDs:        teamIds,
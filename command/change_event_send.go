@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+type ChangeEventSend struct {
+	Meta
+
+	// stdin and stderr are replaced in tests.
+	stdin  io.Reader
+	stderr io.Writer
+}
+
+func ChangeEventSendCommand() (cli.Command, error) {
+	return &ChangeEventSend{stdin: os.Stdin, stderr: os.Stderr}, nil
+}
+
+func (c *ChangeEventSend) Help() string {
+	helpText := `
+	pd change-event send [options] [<FILE>|-] Send a change event
+
+	The change event is built from the options, which override the fields of
+	the change event read from the json file, or from stdin when given "-".
+
+	Options:
+
+	-routing-key Integration key of the service (defaults to $PAGERDUTY_ROUTING_KEY)
+	-summary     Summary of the change
+	-source      Source of the change, such as the host or CI/CD job
+	-timestamp   Time at which the change occurred, in ISO 8601 format
+	-link        Link to attach, as href=text (can be specified multiple times). The
+	             text is optional, unless the href contains "=", such as in its query string
+	-detail      Custom detail to attach, as key=value (can be specified multiple times)
+	-endpoint    Events API endpoint (e.g., https://events.eu.pagerduty.com)
+
+	` + c.Meta.Help()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ChangeEventSend) Synopsis() string {
+	return "Send a change event, such as a deploy"
+}
+
+func (c *ChangeEventSend) Run(args []string) int {
+	var routingKey, summary, source, timestamp, endpoint string
+	var links, details []string
+	flags := c.Meta.FlagSet("change-event send")
+	flags.Usage = func() { fmt.Println(c.Help()) }
+	flags.StringVar(&routingKey, "routing-key", os.Getenv("PAGERDUTY_ROUTING_KEY"), "Integration key of the service")
+	flags.StringVar(&summary, "summary", "", "Summary of the change")
+	flags.StringVar(&source, "source", "", "Source of the change")
+	flags.StringVar(&timestamp, "timestamp", "", "Time at which the change occurred")
+	flags.Var((*ArrayFlags)(&links), "link", "Link to attach, as href=text (can be specified multiple times)")
+	flags.Var((*ArrayFlags)(&details), "detail", "Custom detail to attach, as key=value (can be specified multiple times)")
+	flags.StringVar(&endpoint, "endpoint", "", "Events API endpoint")
+
+	if err := flags.Parse(args); err != nil {
+		log.Error(err)
+		return -1
+	}
+	c.Meta.SetupEvents()
+
+	var e pagerduty.ChangeEvent
+	switch len(flags.Args()) {
+	case 0:
+	case 1:
+		if err := c.decodeChangeEvent(flags.Arg(0), &e); err != nil {
+			log.Errorln("Failed to decode json. Error:", err)
+			return -1
+		}
+	default:
+		log.Error("Please specify at most one input json file")
+		return -1
+	}
+
+	if routingKey != "" {
+		e.RoutingKey = routingKey
+	}
+	if summary != "" {
+		e.Payload.Summary = summary
+	}
+	if source != "" {
+		e.Payload.Source = source
+	}
+	if timestamp != "" {
+		if _, err := pagerduty.ParseTime(timestamp); err != nil {
+			log.Errorf("Invalid timestamp %q, must be in ISO 8601 format", timestamp)
+			return -1
+		}
+		e.Payload.Timestamp = timestamp
+	}
+	for _, link := range links {
+		l, err := parseChangeEventLink(link)
+		if err != nil {
+			log.Error(err)
+			return -1
+		}
+		e.Links = append(e.Links, l)
+	}
+	for _, detail := range details {
+		key, value, ok := strings.Cut(detail, "=")
+		if !ok || key == "" {
+			log.Errorf("Invalid detail %q, must be key=value", detail)
+			return -1
+		}
+		if e.Payload.CustomDetails == nil {
+			e.Payload.CustomDetails = make(map[string]interface{})
+		}
+		e.Payload.CustomDetails[key] = value
+	}
+
+	if e.RoutingKey == "" {
+		log.Error("Please specify the routing key using -routing-key or $PAGERDUTY_ROUTING_KEY")
+		return -1
+	}
+	if e.Payload.Summary == "" {
+		log.Error("Please specify the summary of the change using -summary")
+		return -1
+	}
+
+	var opts []pagerduty.ClientOptions
+	if endpoint != "" {
+		opts = append(opts, pagerduty.WithV2EventsAPIEndpoint(endpoint))
+	}
+	client := pagerduty.NewEventsClient("", opts...)
+
+	log.Debugf("%#v", e)
+	resp, err := client.CreateChangeEventWithContext(context.Background(), e)
+	if err != nil {
+		log.Error(err)
+		var eae pagerduty.EventsAPIV2Error
+		if errors.As(err, &eae) && eae.APIError.Valid {
+			data, _ := json.MarshalIndent(eae.APIError.ErrorObject, "", "  ")
+			fmt.Fprintln(c.stderr, string(data))
+		}
+		return -1
+	}
+	log.Info(resp.Message)
+	return 0
+}
+
+// decodeChangeEvent decodes the change event from the json file, or from stdin
+// when the name is "-".
+func (c *ChangeEventSend) decodeChangeEvent(name string, e *pagerduty.ChangeEvent) error {
+	r := c.stdin
+	if name != "-" {
+		log.Info("Input file is: ", name)
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return json.NewDecoder(r).Decode(e)
+}
+
+// parseChangeEventLink parses a link given as href=text. The text is separated
+// from the href by the last "=", so a link whose href contains "=" must be
+// given a text, which may be empty (e.g., "https://example.com/?build=2="),
+// and the text is optional otherwise.
+func parseChangeEventLink(s string) (pagerduty.ChangeEventLink, error) {
+	href, text := s, ""
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		href, text = s[:i], s[i+1:]
+	}
+	u, err := url.Parse(href)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return pagerduty.ChangeEventLink{}, fmt.Errorf("invalid link %q, must be href=text with an absolute URL as href", s)
+	}
+	return pagerduty.ChangeEventLink{Href: href, Text: text}, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/PagerDuty/go-pagerduty/pagerdutytest"
+)
+
+const testRoutingKey = "R0123456789ABCDEF0123456789ABCDE"
+
+func TestChangeEventSend(t *testing.T) {
+	receiver := pagerdutytest.NewEventsReceiver()
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	var stderr bytes.Buffer
+	c := &ChangeEventSend{
+		stdin:  strings.NewReader(`{"payload": {"summary": "Deployed api", "source": "ci"}, "links": [{"href": "https://ci.example.com/1"}]}`),
+		stderr: &stderr,
+	}
+
+	code := c.Run([]string{
+		"-endpoint", server.URL,
+		"-routing-key", testRoutingKey,
+		"-summary", "Deployed api v1.2.3",
+		"-link", "https://example.com/?build=2&view=log=Build log",
+		"-detail", "version=v1.2.3",
+		"-",
+	})
+	if code != 0 {
+		t.Fatalf("Run() = %d, stderr: %s", code, stderr.String())
+	}
+
+	events := receiver.ChangeEvents()
+	if len(events) != 1 {
+		t.Fatalf("ChangeEvents() = %+v, want one change event", events)
+	}
+
+	e := events[0]
+	if e.RoutingKey != testRoutingKey || e.Payload.Summary != "Deployed api v1.2.3" || e.Payload.Source != "ci" {
+		t.Errorf("change event = %+v", e)
+	}
+
+	wantLinks := []pagerduty.ChangeEventLink{
+		{Href: "https://ci.example.com/1"},
+		{Href: "https://example.com/?build=2&view=log", Text: "Build log"},
+	}
+	if len(e.Links) != 2 || e.Links[0] != wantLinks[0] || e.Links[1] != wantLinks[1] {
+		t.Errorf("links = %+v, want %+v", e.Links, wantLinks)
+	}
+
+	if e.Payload.CustomDetails["version"] != "v1.2.3" {
+		t.Errorf("custom details = %+v", e.Payload.CustomDetails)
+	}
+
+	// links must be URLs
+	code = c.Run([]string{"-endpoint", server.URL, "-routing-key", testRoutingKey, "-summary", "Deployed api", "-link", "Build=https://example.com"})
+	if code == 0 {
+		t.Error("Run() = 0, want an error for an invalid link")
+	}
+
+	// the error body is printed when the change event is rejected
+	code = c.Run([]string{"-endpoint", server.URL, "-routing-key", "short", "-summary", "Deployed api"})
+	if code == 0 {
+		t.Fatal("Run() = 0, want an error for an invalid routing key")
+	}
+
+	if !strings.Contains(stderr.String(), "Length of 'routing_key' is incorrect") {
+		t.Errorf("stderr = %q, want the error body", stderr.String())
+	}
+}
+
+func TestParseChangeEventLink(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    pagerduty.ChangeEventLink
+		wantErr bool
+	}{
+		{in: "https://example.com/builds/2", want: pagerduty.ChangeEventLink{Href: "https://example.com/builds/2"}},
+		{in: "https://example.com/builds/2=Build #2", want: pagerduty.ChangeEventLink{Href: "https://example.com/builds/2", Text: "Build #2"}},
+		{in: "https://example.com/?a=b=Build #2", want: pagerduty.ChangeEventLink{Href: "https://example.com/?a=b", Text: "Build #2"}},
+		{in: "https://example.com/?a=b=", want: pagerduty.ChangeEventLink{Href: "https://example.com/?a=b"}},
+		{in: "Build", wantErr: true},
+		{in: "/builds/2=Build", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseChangeEventLink(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseChangeEventLink(%q) error = %v, want error %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseChangeEventLink(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -21,6 +21,8 @@ func loadCommands() map[string]cli.CommandFactory {
 		"addon delete":  AddonDeleteCommand,
 		"addon update":  AddonUpdateCommand,
 
+		"change-event send": ChangeEventSendCommand,
+
 		"escalation-policy list":   EscalationPolicyListCommand,
 		"escalation-policy create": EscalationPolicyCreateCommand,
 		"escalation-policy delete": EscalationPolicyDeleteCommand,
@@ -53,16 +55,19 @@ func loadCommands() map[string]cli.CommandFactory {
 
 		"notification list": NotificationListCommand,
 
-		"oncall list": OncallListCommand,
+		"oncall export": OncallExportCommand,
+		"oncall list":   OncallListCommand,
 
 		"schedule list":    ScheduleListCommand,
 		"schedule create":  ScheduleCreateCommand,
+		"schedule audit":   ScheduleAuditCommand,
 		"schedule preview": SchedulePreviewCommand,
 		"schedule delete":  ScheduleDeleteCommand,
 		"schedule show":    ScheduleShowCommand,
 		"schedule update":  ScheduleUpdateCommand,
 
 		"schedule override list":   ScheduleOverrideListCommand,
+		"schedule override plan":   ScheduleOverridePlanCommand,
 		"schedule override create": ScheduleOverrideCreateCommand,
 		"schedule override delete": ScheduleOverrideDeleteCommand,
 
@@ -42,8 +42,8 @@ func (m *Meta) FlagSet(n string) *flag.FlagSet {
 	return f
 }
 
-func (m *Meta) Client() *pagerduty.Client {
-	return pagerduty.NewClient(m.Authtoken)
+func (m *Meta) Client(opts ...pagerduty.ClientOptions) *pagerduty.Client {
+	return pagerduty.NewClient(m.Authtoken, opts...)
 }
 
 func (m *Meta) Help() string {
@@ -71,6 +71,15 @@ func (m *Meta) Setup() error {
 	return m.validate()
 }
 
+// SetupEvents sets up commands using the Events API, which authenticates
+// events using their routing key rather than an authentication token.
+func (m *Meta) SetupEvents() {
+	m.setupLogging()
+	if err := m.loadConfig(); err != nil {
+		log.Debug(err)
+	}
+}
+
 func (m *Meta) setupLogging() {
 	log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
 	switch m.Loglevel {
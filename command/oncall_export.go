@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+type OncallExport struct {
+	Meta
+
+	// stdout is replaced in tests.
+	stdout io.Writer
+}
+
+func OncallExportCommand() (cli.Command, error) {
+	return &OncallExport{stdout: os.Stdout}, nil
+}
+
+func (c *OncallExport) Help() string {
+	helpText := `
+	pd oncall export [options] Export on-call shifts as calendars
+
+	Writes an iCalendar (RFC 5545) calendar of the on-call shifts per user,
+	schedule, or escalation policy, which can be imported or subscribed to by
+	calendar applications. Shifts keep the same UID across exports, so that
+	importing an export again updates the existing events.
+
+	Options:
+
+	-format                ics (the only supported format)
+	-group                 Calendar per user, schedule, or escalation_policy (defaults to user)
+	-output-dir            Write each calendar to <ID>.ics in the directory, instead of stdout
+	-user-id               Only export for user ID (can be specified multiple times)
+	-schedule-id           Only export for schedule ID (can be specified multiple times)
+	-escalation-policy-id  Only export for escalation policy ID (can be specified multiple times)
+	-since                 Start of the time range to export
+	-until                 End of the time range to export
+	-endpoint              API endpoint (e.g., https://api.eu.pagerduty.com)
+
+	` + c.Meta.Help()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OncallExport) Synopsis() string {
+	return "Export the on-call shifts during a given time range as calendars"
+}
+
+func (c *OncallExport) Run(args []string) int {
+	var userIDs, scheduleIDs, escalationPolicyIDs []string
+	var format, group, outputDir, since, until, endpoint string
+
+	flags := c.Meta.FlagSet("oncall export")
+	flags.Usage = func() { fmt.Println(c.Help()) }
+	flags.StringVar(&format, "format", "ics", "Output format: ics")
+	flags.StringVar(&group, "group", string(pagerduty.CalendarGroupUser), "Calendar per user, schedule, or escalation_policy")
+	flags.StringVar(&outputDir, "output-dir", "", "Write each calendar to <ID>.ics in the directory")
+	flags.Var((*ArrayFlags)(&userIDs), "user-id", "Only export for user ID (can be specified multiple times)")
+	flags.Var((*ArrayFlags)(&scheduleIDs), "schedule-id", "Only export for schedule ID (can be specified multiple times)")
+	flags.Var((*ArrayFlags)(&escalationPolicyIDs), "escalation-policy-id", "Only export for escalation policy ID (can be specified multiple times)")
+	flags.StringVar(&since, "since", "", "Start of the time range to export")
+	flags.StringVar(&until, "until", "", "End of the time range to export")
+	flags.StringVar(&endpoint, "endpoint", "", "API endpoint")
+
+	if err := flags.Parse(args); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if err := c.Meta.Setup(); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if format != "ics" {
+		log.Errorf("Invalid format %q, must be ics", format)
+		return -1
+	}
+
+	var opts []pagerduty.ClientOptions
+	if endpoint != "" {
+		opts = append(opts, pagerduty.WithAPIEndpoint(endpoint))
+	}
+	client := c.Meta.Client(opts...)
+
+	o := pagerduty.ListOnCallOptions{
+		UserIDs:             userIDs,
+		ScheduleIDs:         scheduleIDs,
+		EscalationPolicyIDs: escalationPolicyIDs,
+		Since:               since,
+		Until:               until,
+	}
+	var oncalls []pagerduty.OnCall
+	for oc, err := range client.OnCalls(context.Background(), o) {
+		if err != nil {
+			log.Error(err)
+			return -1
+		}
+		oncalls = append(oncalls, oc)
+	}
+
+	calendars, err := pagerduty.NewOnCallCalendars(oncalls, pagerduty.CalendarGroup(group))
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
+	for _, cal := range calendars {
+		if err := c.writeCalendar(cal, outputDir); err != nil {
+			log.Error(err)
+			return -1
+		}
+	}
+	return 0
+}
+
+// writeCalendar writes the calendar to <ID>.ics in the directory, or to stdout
+// if there is no directory.
+func (c *OncallExport) writeCalendar(cal *pagerduty.Calendar, dir string) error {
+	if dir == "" {
+		_, err := cal.WriteTo(c.stdout)
+		return err
+	}
+
+	path := filepath.Join(dir, filepath.Base(cal.ID)+".ics")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := cal.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	log.Info("Wrote ", path)
+	return f.Close()
+}
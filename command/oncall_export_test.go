@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/PagerDuty/go-pagerduty/pagerdutytest"
+)
+
+func TestOncallExport(t *testing.T) {
+	fake := pagerdutytest.NewServer()
+	defer fake.Close()
+
+	// consecutive shifts of the schedule
+	for i, u := range []pagerduty.APIObject{{ID: "PU1", Summary: "Alice"}, {ID: "PU2", Summary: "Bob"}} {
+		fake.AddOnCall(pagerduty.OnCall{
+			User:             pagerduty.User{APIObject: u},
+			Schedule:         pagerduty.Schedule{APIObject: pagerduty.APIObject{ID: "PS1", Summary: "Primary"}},
+			EscalationPolicy: pagerduty.EscalationPolicy{APIObject: pagerduty.APIObject{ID: "PEP1", Summary: "Default"}},
+			EscalationLevel:  1,
+			Start:            pagerduty.NewTime(time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC)),
+			End:              pagerduty.NewTime(time.Date(2026, 1, 2+i, 0, 0, 0, 0, time.UTC)),
+		})
+	}
+
+	var stdout bytes.Buffer
+	c := &OncallExport{stdout: &stdout}
+
+	if code := c.Run([]string{"-authtoken", "token", "-endpoint", fake.URL, "-user-id", "PU2"}); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	ics := stdout.String()
+	if n := strings.Count(ics, "BEGIN:VCALENDAR"); n != 1 {
+		t.Errorf("output has %d calendars, want 1", n)
+	}
+	for _, s := range []string{
+		"X-WR-CALNAME:Bob\r\n",
+		"DTSTART:20260102T000000Z\r\nDTEND:20260103T000000Z\r\nSUMMARY:On call: Default (level 1)\r\n",
+	} {
+		if !strings.Contains(ics, s) {
+			t.Errorf("output = %q, should contain %q", ics, s)
+		}
+	}
+
+	dir := t.TempDir()
+	if code := c.Run([]string{"-authtoken", "token", "-endpoint", fake.URL, "-group", "schedule", "-output-dir", dir}); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "PS1.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"SUMMARY:On call: Alice\r\n", "SUMMARY:On call: Bob\r\n"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("PS1.ics = %q, should contain %q", data, s)
+		}
+	}
+
+	if code := c.Run([]string{"-authtoken", "token", "-endpoint", fake.URL, "-format", "csv"}); code != -1 {
+		t.Errorf("Run() = %d, want -1", code)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+type ScheduleAudit struct {
+	Meta
+
+	// stdout is replaced in tests.
+	stdout io.Writer
+}
+
+func ScheduleAuditCommand() (cli.Command, error) {
+	return &ScheduleAudit{stdout: os.Stdout}, nil
+}
+
+func (c *ScheduleAudit) Help() string {
+	helpText := `
+	pd schedule audit [options] -schedule-id <ID> [-schedule-id <ID> ...]
+
+	Reports the coverage issues of the schedules: the time during which nobody
+	is on call, users on call for two schedules at once, users on call while
+	covering another schedule using an override, and escalation policy rules
+	with nobody on call. Exits with status 1 if any issue is found.
+
+	Options:
+
+	-schedule-id  Schedule ID to audit (can be specified multiple times)
+	-since        Start of the time range to audit (defaults to now)
+	-until        End of the time range to audit (defaults to 14 days after the start)
+	-endpoint     API endpoint (e.g., https://api.eu.pagerduty.com)
+
+	` + c.Meta.Help()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ScheduleAudit) Synopsis() string {
+	return "Report gaps and conflicts in on-call schedules"
+}
+
+func (c *ScheduleAudit) Run(args []string) int {
+	var scheduleIDs []string
+	var since, until, endpoint string
+	flags := c.Meta.FlagSet("schedule audit")
+	flags.Usage = func() { fmt.Println(c.Help()) }
+	flags.Var((*ArrayFlags)(&scheduleIDs), "schedule-id", "Schedule ID to audit (can be specified multiple times)")
+	flags.StringVar(&since, "since", "", "Start of the time range to audit")
+	flags.StringVar(&until, "until", "", "End of the time range to audit")
+	flags.StringVar(&endpoint, "endpoint", "", "API endpoint")
+
+	if err := flags.Parse(args); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if err := c.Meta.Setup(); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if len(scheduleIDs) == 0 {
+		log.Error("Please specify at least one schedule ID")
+		return -1
+	}
+
+	start := time.Now().UTC().Truncate(time.Minute)
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			log.Errorf("Invalid -since: %v", err)
+			return -1
+		}
+		start = t
+	}
+	end := start.AddDate(0, 0, 14)
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			log.Errorf("Invalid -until: %v", err)
+			return -1
+		}
+		end = t
+	}
+
+	var opts []pagerduty.ClientOptions
+	if endpoint != "" {
+		opts = append(opts, pagerduty.WithAPIEndpoint(endpoint))
+	}
+	client := c.Meta.Client(opts...)
+
+	audit, err := client.AuditSchedulesWithContext(context.Background(), scheduleIDs, start, end)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
+	if err := writeScheduleAudit(c.stdout, audit); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if !audit.Empty() {
+		return 1
+	}
+	return 0
+}
+
+// writeScheduleAudit writes the issues found by the audit as a table, or a
+// single line if there are none.
+func writeScheduleAudit(w io.Writer, a *pagerduty.ScheduleAudit) error {
+	if a.Empty() {
+		_, err := fmt.Fprintln(w, "No issues found")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ISSUE\tSTART\tEND\tDETAILS")
+	row := func(issue string, start, end time.Time, format string, a ...interface{}) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", issue, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), fmt.Sprintf(format, a...))
+	}
+
+	for _, g := range a.Gaps {
+		row("gap", g.Start, g.End, "nobody on call for %s", objectName(g.Schedule))
+	}
+	for _, d := range a.DoubleBookings {
+		names := make([]string, 0, len(d.Schedules))
+		for _, s := range d.Schedules {
+			names = append(names, objectName(s))
+		}
+		row("double booking", d.Start, d.End, "%s on call for %s", userName(d.User), strings.Join(names, " and "))
+	}
+	for _, o := range a.OverrideConflicts {
+		row("override conflict", o.Start, o.End, "%s on call for %s while overriding %s", userName(o.User), objectName(o.Schedule), objectName(o.OverrideSchedule))
+	}
+	for _, g := range a.EscalationPolicyGaps {
+		row("escalation gap", g.Start, g.End, "nobody on call for level %d of %s", g.Level, objectName(g.EscalationPolicy))
+	}
+
+	return tw.Flush()
+}
+
+// objectName returns the summary of the reference, followed by its ID, or
+// only its ID if it has no summary.
+func objectName(o pagerduty.APIObject) string {
+	if o.Summary != "" {
+		return fmt.Sprintf("%s (%s)", o.Summary, o.ID)
+	}
+	return o.ID
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/PagerDuty/go-pagerduty/pagerdutytest"
+)
+
+func TestScheduleAudit(t *testing.T) {
+	fake := pagerdutytest.NewServer()
+	defer fake.Close()
+
+	s := fake.AddSchedule(pagerduty.Schedule{
+		Name: "Primary",
+		FinalSchedule: pagerduty.ScheduleLayer{RenderedScheduleEntries: []pagerduty.RenderedScheduleEntry{
+			{
+				Start: pagerduty.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+				End:   pagerduty.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)),
+				User:  pagerduty.APIObject{ID: "PU1", Summary: "Alice"},
+			},
+		}},
+	})
+
+	args := []string{
+		"-authtoken", "token",
+		"-endpoint", fake.URL,
+		"-schedule-id", s.ID,
+		"-since", "2026-01-01T00:00:00Z",
+	}
+
+	var stdout bytes.Buffer
+	c := &ScheduleAudit{stdout: &stdout}
+
+	if code := c.Run(append(args, "-until", "2026-01-02T00:00:00Z")); code != 1 {
+		t.Fatalf("Run() = %d, want 1", code)
+	}
+
+	want := `ISSUE  START                 END                   DETAILS
+gap    2026-01-01T12:00:00Z  2026-01-02T00:00:00Z  nobody on call for Primary (` + s.ID + `)
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	if code := c.Run(append(args, "-until", "2026-01-01T12:00:00Z")); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+
+	if got, want := stdout.String(), "No issues found\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
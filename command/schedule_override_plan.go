@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+type ScheduleOverridePlan struct {
+	Meta
+
+	// stdout is replaced in tests.
+	stdout io.Writer
+}
+
+func ScheduleOverridePlanCommand() (cli.Command, error) {
+	return &ScheduleOverridePlan{stdout: os.Stdout}, nil
+}
+
+func (c *ScheduleOverridePlan) Help() string {
+	helpText := `
+	pd schedule override plan [options] Plan overrides replacing a user
+
+	Computes the overrides needed to replace a user in the schedules during a
+	time range, such as for a vacation, and prints them as a diff of who is on
+	call. Unless -apply is given, nothing is changed. When applying, if creating
+	an override fails, the overrides already created are deleted.
+
+	Options:
+
+	-user-id        ID of the user to replace
+	-schedule-id    Schedule ID to replace the user in (can be specified multiple times)
+	-since          Start of the time range to replace the user during
+	-until          End of the time range to replace the user during
+	-strategy       substitute, next_in_rotation, or round_robin (defaults to substitute)
+	-substitute-id  ID of the user replacing the user, with the substitute strategy
+	-apply          Create the overrides
+	-endpoint       API endpoint (e.g., https://api.eu.pagerduty.com)
+
+	` + c.Meta.Help()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ScheduleOverridePlan) Synopsis() string {
+	return "Plan, and optionally create, the overrides replacing a user during a time range"
+}
+
+func (c *ScheduleOverridePlan) Run(args []string) int {
+	var scheduleIDs []string
+	var userID, since, until, strategy, substituteID, endpoint string
+	var apply bool
+	flags := c.Meta.FlagSet("schedule override plan")
+	flags.Usage = func() { fmt.Println(c.Help()) }
+	flags.StringVar(&userID, "user-id", "", "ID of the user to replace")
+	flags.Var((*ArrayFlags)(&scheduleIDs), "schedule-id", "Schedule ID to replace the user in (can be specified multiple times)")
+	flags.StringVar(&since, "since", "", "Start of the time range to replace the user during")
+	flags.StringVar(&until, "until", "", "End of the time range to replace the user during")
+	flags.StringVar(&strategy, "strategy", string(pagerduty.OverrideStrategySubstitute), "substitute, next_in_rotation, or round_robin")
+	flags.StringVar(&substituteID, "substitute-id", "", "ID of the user replacing the user")
+	flags.BoolVar(&apply, "apply", false, "Create the overrides")
+	flags.StringVar(&endpoint, "endpoint", "", "API endpoint")
+
+	if err := flags.Parse(args); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if err := c.Meta.Setup(); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if len(scheduleIDs) == 0 {
+		log.Error("Please specify at least one schedule ID")
+		return -1
+	}
+
+	start, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		log.Errorf("Invalid -since: %v", err)
+		return -1
+	}
+	end, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		log.Errorf("Invalid -until: %v", err)
+		return -1
+	}
+
+	var opts []pagerduty.ClientOptions
+	if endpoint != "" {
+		opts = append(opts, pagerduty.WithAPIEndpoint(endpoint))
+	}
+	client := c.Meta.Client(opts...)
+
+	o := pagerduty.PlanOverridesOptions{
+		UserID:     userID,
+		Since:      start,
+		Until:      end,
+		Strategy:   pagerduty.OverrideStrategy(strategy),
+		Substitute: pagerduty.APIObject{ID: substituteID},
+	}
+	planned, err := client.PlanOverridesWithContext(context.Background(), scheduleIDs, o)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
+	if err := writeOverridePlan(c.stdout, planned); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if !apply || len(planned) == 0 {
+		return 0
+	}
+
+	applied, err := client.ApplyOverridesWithContext(context.Background(), planned)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+	fmt.Fprintf(c.stdout, "\nCreated %d overrides\n", len(applied))
+	return 0
+}
+
+// writeOverridePlan writes the planned overrides as a diff of who is on call,
+// grouped by schedule.
+func writeOverridePlan(w io.Writer, planned []pagerduty.PlannedOverride) error {
+	if len(planned) == 0 {
+		_, err := fmt.Fprintln(w, "No overrides needed")
+		return err
+	}
+
+	var schedule string
+	for _, p := range planned {
+		if p.Schedule.ID != schedule {
+			if schedule != "" {
+				fmt.Fprintln(w)
+			}
+			schedule = p.Schedule.ID
+			fmt.Fprintln(w, objectName(p.Schedule))
+		}
+		fmt.Fprintf(w, "- %s  %s  %s\n", p.Override.Start, p.Override.End, userName(p.ReplacedUser))
+		fmt.Fprintf(w, "+ %s  %s  %s\n", p.Override.Start, p.Override.End, userName(p.Override.User))
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d overrides to create\n", len(planned))
+	return err
+}
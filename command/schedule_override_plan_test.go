@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/PagerDuty/go-pagerduty/pagerdutytest"
+)
+
+func TestScheduleOverridePlan(t *testing.T) {
+	fake := pagerdutytest.NewServer()
+	defer fake.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 3)
+
+	rendered, err := pagerduty.RenderSchedule(pagerduty.Schedule{
+		Name: "Primary",
+		ScheduleLayers: []pagerduty.ScheduleLayer{{
+			Start:                     "2026-01-01T00:00:00Z",
+			RotationVirtualStart:      "2026-01-01T00:00:00Z",
+			RotationTurnLengthSeconds: 86400,
+			Users: []pagerduty.UserReference{
+				{User: pagerduty.APIObject{ID: "PU1", Summary: "Alice"}},
+				{User: pagerduty.APIObject{ID: "PU2", Summary: "Bob"}},
+			},
+		}},
+	}, pagerduty.RenderScheduleOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := fake.AddSchedule(*rendered)
+
+	args := []string{
+		"-authtoken", "token",
+		"-endpoint", fake.URL,
+		"-schedule-id", s.ID,
+		"-user-id", "PU1",
+		"-since", "2026-01-01T00:00:00Z",
+		"-until", "2026-01-04T00:00:00Z",
+		"-strategy", "next_in_rotation",
+	}
+
+	var stdout bytes.Buffer
+	c := &ScheduleOverridePlan{stdout: &stdout}
+
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	want := `Primary (` + s.ID + `)
+- 2026-01-01T00:00:00Z  2026-01-02T00:00:00Z  Alice
++ 2026-01-01T00:00:00Z  2026-01-02T00:00:00Z  Bob
+- 2026-01-03T00:00:00Z  2026-01-04T00:00:00Z  Alice
++ 2026-01-03T00:00:00Z  2026-01-04T00:00:00Z  Bob
+
+2 overrides to create
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if overrides := fake.Overrides(s.ID); len(overrides) != 0 {
+		t.Errorf("overrides = %+v, want none for a dry run", overrides)
+	}
+
+	stdout.Reset()
+	if code := c.Run(append(args, "-apply")); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	overrides := fake.Overrides(s.ID)
+	if len(overrides) != 2 || overrides[0].User.ID != "PU2" || overrides[1].Start != "2026-01-03T00:00:00Z" {
+		t.Errorf("overrides = %+v, want two overrides for Bob", overrides)
+	}
+}
@@ -1,20 +1,46 @@
 package main
 
 import (
-	"github.com/mitchellh/cli"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"text/tabwriter"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
 )
 
 type SchedulePreview struct {
+	Meta
+
+	// stdout is replaced in tests.
+	stdout io.Writer
 }
 
 func SchedulePreviewCommand() (cli.Command, error) {
-	return &SchedulePreview{}, nil
+	return &SchedulePreview{stdout: os.Stdout}, nil
 }
 
 func (c *SchedulePreview) Help() string {
 	helpText := `
-	`
+	pd schedule preview [options] <FILE> Preview a schedule from json file
+
+	Prints the final on-call timeline of the schedule, as it would be if it was
+	created or updated, without saving it.
+
+	Options:
+
+	-since     Start of the time range to preview (defaults to now)
+	-until     End of the time range to preview
+	-overflow  Don't truncate the on-call entries to the time range
+	-format    Output format: table or ics (defaults to table)
+	-endpoint  API endpoint (e.g., https://api.eu.pagerduty.com)
+
+	` + c.Meta.Help()
 	return strings.TrimSpace(helpText)
 }
 
@@ -23,5 +49,99 @@ func (c *SchedulePreview) Synopsis() string {
 }
 
 func (c *SchedulePreview) Run(args []string) int {
+	var since, until, format, endpoint string
+	var overflow bool
+	flags := c.Meta.FlagSet("schedule preview")
+	flags.Usage = func() { fmt.Println(c.Help()) }
+	flags.StringVar(&since, "since", "", "Start of the time range to preview")
+	flags.StringVar(&until, "until", "", "End of the time range to preview")
+	flags.BoolVar(&overflow, "overflow", false, "Don't truncate the on-call entries to the time range")
+	flags.StringVar(&format, "format", "table", "Output format: table or ics")
+	flags.StringVar(&endpoint, "endpoint", "", "API endpoint")
+
+	if err := flags.Parse(args); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if err := c.Meta.Setup(); err != nil {
+		log.Error(err)
+		return -1
+	}
+	if format != "table" && format != "ics" {
+		log.Errorf("Invalid format %q, must be table or ics", format)
+		return -1
+	}
+	if len(flags.Args()) != 1 {
+		log.Error("Please specify input json file")
+		return -1
+	}
+
+	log.Info("Input file is:", flags.Arg(0))
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+	defer f.Close()
+	var s pagerduty.Schedule
+	if err := json.NewDecoder(f).Decode(&s); err != nil {
+		log.Errorln("Failed to decode json. Error:", err)
+		return -1
+	}
+	log.Debugf("%#v", s)
+
+	var opts []pagerduty.ClientOptions
+	if endpoint != "" {
+		opts = append(opts, pagerduty.WithAPIEndpoint(endpoint))
+	}
+	client := c.Meta.Client(opts...)
+
+	o := pagerduty.PreviewScheduleOptions{
+		Since:    since,
+		Until:    until,
+		Overflow: overflow,
+	}
+	preview, err := client.PreviewScheduleWithContext(context.Background(), s, o)
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
+
+	if format == "ics" {
+		var cal *pagerduty.Calendar
+		if cal, err = pagerduty.NewScheduleCalendar(preview); err == nil {
+			_, err = cal.WriteTo(c.stdout)
+		}
+	} else {
+		err = writeScheduleTable(c.stdout, preview.FinalSchedule)
+	}
+	if err != nil {
+		log.Error(err)
+		return -1
+	}
 	return 0
 }
+
+// writeScheduleTable writes the on-call entries of the layer as a table,
+// followed by its coverage.
+func writeScheduleTable(w io.Writer, l pagerduty.ScheduleLayer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "START\tEND\tUSER")
+	for _, e := range l.RenderedScheduleEntries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Start, e.End, userName(e.User))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nCoverage: %g%%\n", l.RenderedCoveragePercentage)
+	return err
+}
+
+// userName returns the name of the user reference, or its ID if it has no
+// summary.
+func userName(u pagerduty.APIObject) string {
+	if u.Summary != "" {
+		return u.Summary
+	}
+	return u.ID
+}
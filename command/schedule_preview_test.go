@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty/pagerdutytest"
+)
+
+const testScheduleJSON = `{
+	"name": "Primary",
+	"time_zone": "UTC",
+	"schedule_layers": [{
+		"start": "2026-01-01T00:00:00Z",
+		"rotation_virtual_start": "2026-01-01T00:00:00Z",
+		"rotation_turn_length_seconds": 86400,
+		"users": [{"user": {"id": "PU1", "summary": "Alice"}}, {"user": {"id": "PU2", "summary": "Bob"}}]
+	}]
+}`
+
+func TestSchedulePreview(t *testing.T) {
+	fake := pagerdutytest.NewServer()
+	defer fake.Close()
+
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := os.WriteFile(path, []byte(testScheduleJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{
+		"-authtoken", "token",
+		"-endpoint", fake.URL,
+		"-since", "2026-01-01T00:00:00Z",
+		"-until", "2026-01-03T00:00:00Z",
+	}
+
+	var stdout bytes.Buffer
+	c := &SchedulePreview{stdout: &stdout}
+
+	if code := c.Run(append(args, path)); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	want := `START                 END                   USER
+2026-01-01T00:00:00Z  2026-01-02T00:00:00Z  Alice
+2026-01-02T00:00:00Z  2026-01-03T00:00:00Z  Bob
+
+Coverage: 100%
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	if code := c.Run(append(args, "-format", "ics", path)); code != 0 {
+		t.Fatalf("Run() = %d", code)
+	}
+
+	ics := stdout.String()
+	for _, s := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Primary\r\n",
+		"DTSTART:20260101T000000Z\r\nDTEND:20260102T000000Z\r\nSUMMARY:On call: Alice\r\n",
+		"DTSTART:20260102T000000Z\r\nDTEND:20260103T000000Z\r\nSUMMARY:On call: Bob\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, s) {
+			t.Errorf("output = %q, should contain %q", ics, s)
+		}
+	}
+}
@@ -251,7 +251,7 @@ func (c *Client) ListServiceEnablementsWithContext(ctx context.Context, serviceI
 		return nil, handleEnablementError(err, "listing", "service", serviceID)
 	}
 
-	resp, err := c.get(ctx, path, nil)
+	resp, err := c.get(ctx, "ListServiceEnablementsWithContext", path, nil)
 	enablements, warnings, err := getEnablementsFromResponseWithWarnings(c, resp, err, "service", serviceID)
 	if err != nil {
 		return nil, err
@@ -274,7 +274,7 @@ func (c *Client) ListEventOrchestrationEnablementsWithContext(ctx context.Contex
 		return nil, handleEnablementError(err, "listing", "event_orchestration", orchestrationID)
 	}
 
-	resp, err := c.get(ctx, path, nil)
+	resp, err := c.get(ctx, "ListEventOrchestrationEnablementsWithContext", path, nil)
 	enablements, warnings, err := getEnablementsFromResponseWithWarnings(c, resp, err, "event_orchestration", orchestrationID)
 	if err != nil {
 		return nil, err
@@ -312,7 +312,7 @@ func (c *Client) UpdateServiceEnablementWithContext(ctx context.Context, service
 		return nil, handleEnablementError(err, "updating", "service", serviceID)
 	}
 
-	resp, err := c.put(ctx, path, req, nil)
+	resp, err := c.put(ctx, "UpdateServiceEnablementWithContext", path, req, nil)
 	enablement, warnings, err := getEnablementFromResponseWithWarnings(c, resp, err, "service", serviceID, feature)
 	if err != nil {
 		return nil, err
@@ -350,7 +350,7 @@ func (c *Client) UpdateEventOrchestrationEnablementWithContext(ctx context.Conte
 		return nil, handleEnablementError(err, "updating", "event_orchestration", orchestrationID)
 	}
 
-	resp, err := c.put(ctx, path, req, nil)
+	resp, err := c.put(ctx, "UpdateEventOrchestrationEnablementWithContext", path, req, nil)
 	enablement, warnings, err := getEnablementFromResponseWithWarnings(c, resp, err, "event_orchestration", orchestrationID, feature)
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -91,7 +92,7 @@ func (c *Client) ListEscalationPoliciesWithContext(ctx context.Context, o ListEs
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, escPath+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListEscalationPoliciesWithContext", escPath+"?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +105,14 @@ func (c *Client) ListEscalationPoliciesWithContext(ctx context.Context, o ListEs
 	return &result, nil
 }
 
+// EscalationPolicies returns an iterator over all of the existing escalation
+// policies, automatically fetching additional pages as needed.
+func (c *Client) EscalationPolicies(ctx context.Context, o ListEscalationPoliciesOptions) iter.Seq2[EscalationPolicy, error] {
+	return offsetSeq(ctx, c, "EscalationPolicies", escPath, o, func(r *ListEscalationPoliciesResponse) ([]EscalationPolicy, APIListObject) {
+		return r.EscalationPolicies, r.APIListObject
+	})
+}
+
 // CreateEscalationPolicy creates a new escalation policy.
 //
 // Deprecated: Use CreateEscalationPolicyWithContext instead.
@@ -117,7 +126,7 @@ func (c *Client) CreateEscalationPolicyWithContext(ctx context.Context, e Escala
 		"escalation_policy": e,
 	}
 
-	resp, err := c.post(ctx, escPath, d, nil)
+	resp, err := c.post(ctx, "CreateEscalationPolicyWithContext", escPath, d, nil)
 	return getEscalationPolicyFromResponse(c, resp, err)
 }
 
@@ -130,7 +139,7 @@ func (c *Client) DeleteEscalationPolicy(id string) error {
 
 // DeleteEscalationPolicyWithContext deletes an existing escalation policy and rules.
 func (c *Client) DeleteEscalationPolicyWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, escPath+"/"+id)
+	_, err := c.delete(ctx, "DeleteEscalationPolicyWithContext", escPath+"/"+id)
 	return err
 }
 
@@ -155,7 +164,7 @@ func (c *Client) GetEscalationPolicyWithContext(ctx context.Context, id string,
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, escPath+"/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetEscalationPolicyWithContext", escPath+"/"+id+"?"+v.Encode(), nil)
 	return getEscalationPolicyFromResponse(c, resp, err)
 }
 
@@ -172,7 +181,7 @@ func (c *Client) UpdateEscalationPolicyWithContext(ctx context.Context, id strin
 		"escalation_policy": e,
 	}
 
-	resp, err := c.put(ctx, escPath+"/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateEscalationPolicyWithContext", escPath+"/"+id, d, nil)
 	return getEscalationPolicyFromResponse(c, resp, err)
 }
 
@@ -191,7 +200,7 @@ func (c *Client) CreateEscalationRuleWithContext(ctx context.Context, escID stri
 		"escalation_rule": e,
 	}
 
-	resp, err := c.post(ctx, escPath+"/"+escID+"/escalation_rules", d, nil)
+	resp, err := c.post(ctx, "CreateEscalationRuleWithContext", escPath+"/"+escID+"/escalation_rules", d, nil)
 	return getEscalationRuleFromResponse(c, resp, err)
 }
 
@@ -209,7 +218,7 @@ func (c *Client) GetEscalationRuleWithContext(ctx context.Context, escID string,
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, escPath+"/"+escID+"/escalation_rules/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetEscalationRuleWithContext", escPath+"/"+escID+"/escalation_rules/"+id+"?"+v.Encode(), nil)
 	return getEscalationRuleFromResponse(c, resp, err)
 }
 
@@ -222,7 +231,7 @@ func (c *Client) DeleteEscalationRule(escID string, id string) error {
 
 // DeleteEscalationRuleWithContext deletes an existing escalation rule.
 func (c *Client) DeleteEscalationRuleWithContext(ctx context.Context, escID string, id string) error {
-	_, err := c.delete(ctx, escPath+"/"+escID+"/escalation_rules/"+id)
+	_, err := c.delete(ctx, "DeleteEscalationRuleWithContext", escPath+"/"+escID+"/escalation_rules/"+id)
 	return err
 }
 
@@ -239,7 +248,7 @@ func (c *Client) UpdateEscalationRuleWithContext(ctx context.Context, escID stri
 		"escalation_rule": e,
 	}
 
-	resp, err := c.put(ctx, escPath+"/"+escID+"/escalation_rules/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateEscalationRuleWithContext", escPath+"/"+escID+"/escalation_rules/"+id, d, nil)
 	return getEscalationRuleFromResponse(c, resp, err)
 }
 
@@ -253,7 +262,7 @@ func (c *Client) ListEscalationRules(escID string) (*ListEscalationRulesResponse
 
 // ListEscalationRulesWithContext lists all of the escalation rules for an existing escalation policy.
 func (c *Client) ListEscalationRulesWithContext(ctx context.Context, escID string) (*ListEscalationRulesResponse, error) {
-	resp, err := c.get(ctx, escPath+"/"+escID+"/escalation_rules", nil)
+	resp, err := c.get(ctx, "ListEscalationRulesWithContext", escPath+"/"+escID+"/escalation_rules", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,8 @@
 package pagerduty
 
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
-)
+import "context"
 
-const eventEndPoint = "https://events.pagerduty.com/generic/2010-04-15/create_event.json"
+const eventPath = "/generic/2010-04-15/create_event.json"
 
 // Event stores data for problem reporting, acknowledgement, and resolution.
 type Event struct {
@@ -31,9 +26,10 @@ type EventResponse struct {
 
 // CreateEvent sends PagerDuty an event to trigger, acknowledge, or resolve a
 // problem. If you need to provide a custom HTTP client, please use
-// CreateEventWithHTTPClient.
+// CreateEventWithHTTPClient, or an EventsClient to also configure its endpoint
+// or retries.
 func CreateEvent(e Event) (*EventResponse, error) {
-	return CreateEventWithHTTPClient(e, defaultHTTPClient)
+	return defaultEventsClient.CreateEventWithContext(context.Background(), e)
 }
 
 // CreateEventWithHTTPClient sends PagerDuty an event to trigger, acknowledge,
@@ -41,25 +37,5 @@ func CreateEvent(e Event) (*EventResponse, error) {
 // default one used by this package doesn't fit your needs. If you don't need a
 // custom HTTP client, please use CreateEvent instead.
 func CreateEventWithHTTPClient(e Event, client HTTPClient) (*EventResponse, error) {
-	data, err := json.Marshal(e)
-	if err != nil {
-		return nil, err
-	}
-	req, _ := http.NewRequest("POST", eventEndPoint, bytes.NewBuffer(data))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to action request: %w", err)
-	}
-
-	defer func() { _ = resp.Body.Close() }() // explicitly discard error
-
-	if resp.StatusCode != http.StatusOK {
-		return &EventResponse{HTTPStatus: resp.StatusCode}, fmt.Errorf("HTTP Status Code: %d", resp.StatusCode)
-	}
-	var eventResponse EventResponse
-	if err := json.NewDecoder(resp.Body).Decode(&eventResponse); err != nil {
-		return nil, err
-	}
-	return &eventResponse, nil
+	return NewEventsClient("", WithHTTPClient(client)).CreateEventWithContext(context.Background(), e)
 }
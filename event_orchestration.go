@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -21,9 +22,9 @@ type Orchestration struct {
 	Team         *APIReference               `json:"team,omitempty"`
 	Integrations []*OrchestrationIntegration `json:"integrations,omitempty"`
 	Routes       uint                        `json:"routes,omitempty"`
-	CreatedAt    string                      `json:"created_at,omitempty"`
+	CreatedAt    Time                        `json:"created_at,omitempty"`
 	CreatedBy    *APIReference               `json:"created_by,omitempty"`
-	UpdatedAt    string                      `json:"updated_at,omitempty"`
+	UpdatedAt    Time                        `json:"updated_at,omitempty"`
 	UpdatedBy    *APIReference               `json:"updated_by,omitempty"`
 	Version      string                      `json:"version,omitempty"`
 }
@@ -76,7 +77,7 @@ func (c *Client) ListOrchestrationsWithContext(ctx context.Context, o ListOrches
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, eoPath+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListOrchestrationsWithContext", eoPath+"?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -89,19 +90,27 @@ func (c *Client) ListOrchestrationsWithContext(ctx context.Context, o ListOrches
 	return &result, nil
 }
 
+// Orchestrations returns an iterator over all the existing event orchestrations,
+// automatically fetching additional pages as needed.
+func (c *Client) Orchestrations(ctx context.Context, o ListOrchestrationsOptions) iter.Seq2[Orchestration, error] {
+	return offsetSeq(ctx, c, "Orchestrations", eoPath, o, func(r *ListOrchestrationsResponse) ([]Orchestration, APIListObject) {
+		return r.Orchestrations, r.APIListObject
+	})
+}
+
 // CreateOrchestrationWithContext creates a new event orchestration.
 func (c *Client) CreateOrchestrationWithContext(ctx context.Context, e Orchestration) (*Orchestration, error) {
 	d := map[string]Orchestration{
 		"orchestration": e,
 	}
 
-	resp, err := c.post(ctx, eoPath, d, nil)
+	resp, err := c.post(ctx, "CreateOrchestrationWithContext", eoPath, d, nil)
 	return getOrchestrationFromResponse(c, resp, err)
 }
 
 // DeleteOrchestrationWithContext deletes an existing event orchestration.
 func (c *Client) DeleteOrchestrationWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, eoPath+"/"+id)
+	_, err := c.delete(ctx, "DeleteOrchestrationWithContext", eoPath+"/"+id)
 	return err
 }
 
@@ -116,7 +125,7 @@ func (c *Client) GetOrchestrationWithContext(ctx context.Context, id string, o *
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, eoPath+"/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetOrchestrationWithContext", eoPath+"/"+id+"?"+v.Encode(), nil)
 	return getOrchestrationFromResponse(c, resp, err)
 }
 
@@ -126,7 +135,7 @@ func (c *Client) UpdateOrchestrationWithContext(ctx context.Context, id string,
 		"orchestration": e,
 	}
 
-	resp, err := c.put(ctx, eoPath+"/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateOrchestrationWithContext", eoPath+"/"+id, d, nil)
 	return getOrchestrationFromResponse(c, resp, err)
 }
 
@@ -201,7 +210,7 @@ func (c *Client) GetOrchestrationRouterWithContext(ctx context.Context, id strin
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, eoPath+"/"+id+"/router"+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetOrchestrationRouterWithContext", eoPath+"/"+id+"/router"+"?"+v.Encode(), nil)
 	return getOrchestrationRouterFromResponse(c, resp, err)
 }
 
@@ -211,7 +220,7 @@ func (c *Client) UpdateOrchestrationRouterWithContext(ctx context.Context, id st
 		"orchestration_path": e,
 	}
 
-	resp, err := c.put(ctx, eoPath+"/"+id+"/router", d, nil)
+	resp, err := c.put(ctx, "UpdateOrchestrationRouterWithContext", eoPath+"/"+id+"/router", d, nil)
 	return getOrchestrationRouterFromResponse(c, resp, err)
 }
 
@@ -300,7 +309,7 @@ func (c *Client) GetServiceOrchestrationWithContext(ctx context.Context, id stri
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, eoPath+"/services/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetServiceOrchestrationWithContext", eoPath+"/services/"+id+"?"+v.Encode(), nil)
 	return getServiceOrchestrationFromResponse(c, resp, err)
 }
 
@@ -310,19 +319,19 @@ func (c *Client) UpdateServiceOrchestrationWithContext(ctx context.Context, id s
 		"orchestration_path": e,
 	}
 
-	resp, err := c.put(ctx, eoPath+"/services/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateServiceOrchestrationWithContext", eoPath+"/services/"+id, d, nil)
 	return getServiceOrchestrationFromResponse(c, resp, err)
 }
 
 // GetServiceOrchestrationActiveWithContext gets a service orchestration's active status.
 func (c *Client) GetServiceOrchestrationActiveWithContext(ctx context.Context, id string) (*ServiceOrchestrationActive, error) {
-	resp, err := c.get(ctx, eoPath+"/services/"+id+"/active", nil)
+	resp, err := c.get(ctx, "GetServiceOrchestrationActiveWithContext", eoPath+"/services/"+id+"/active", nil)
 	return getServiceOrchestrationActiveFromResponse(c, resp, err)
 }
 
 // UpdateServiceOrchestrationActiveWithContext updates a service orchestration's active status.
 func (c *Client) UpdateServiceOrchestrationActiveWithContext(ctx context.Context, id string, e ServiceOrchestrationActive) (*ServiceOrchestrationActive, error) {
-	resp, err := c.put(ctx, eoPath+"/services/"+id+"/active", e, nil)
+	resp, err := c.put(ctx, "UpdateServiceOrchestrationActiveWithContext", eoPath+"/services/"+id+"/active", e, nil)
 	return getServiceOrchestrationActiveFromResponse(c, resp, err)
 }
 
@@ -413,7 +422,7 @@ func (c *Client) GetOrchestrationUnroutedWithContext(ctx context.Context, id str
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, eoPath+"/"+id+"/unrouted"+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetOrchestrationUnroutedWithContext", eoPath+"/"+id+"/unrouted"+"?"+v.Encode(), nil)
 	return getOrchestrationUnroutedFromResponse(c, resp, err)
 }
 
@@ -423,7 +432,7 @@ func (c *Client) UpdateOrchestrationUnroutedWithContext(ctx context.Context, id
 		"orchestration_path": e,
 	}
 
-	resp, err := c.put(ctx, eoPath+"/"+id+"/unrouted", d, nil)
+	resp, err := c.put(ctx, "UpdateOrchestrationUnroutedWithContext", eoPath+"/"+id+"/unrouted", d, nil)
 	return getOrchestrationUnroutedFromResponse(c, resp, err)
 }
 
@@ -0,0 +1,544 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrEventSenderClosed is returned when sending events using an EventSender
+// which has been closed.
+var ErrEventSenderClosed = errors.New("event sender is closed")
+
+// ErrEventSpoolLocked is returned by NewEventSender when the spool directory
+// is already used by another EventSender, in this process or another one.
+var ErrEventSpoolLocked = errors.New("event spool is used by another event sender")
+
+const (
+	defaultEventConcurrency = 4
+	defaultEventQueueSize   = 1000
+	defaultEventBaseDelay   = time.Second
+	defaultEventMaxDelay    = time.Minute
+
+	// eventSpoolLockFile is the name of the file locking the spool directory.
+	eventSpoolLockFile = "lock"
+)
+
+// EventDelivery is the outcome of delivering an event queued using an
+// EventSender, passed to the callback configured using
+// WithEventDeliveryCallback.
+type EventDelivery struct {
+	// Event is the event which was delivered, for events queued using Send.
+	Event *V2Event
+
+	// ChangeEvent is the change event which was delivered, for events queued
+	// using SendChange.
+	ChangeEvent *ChangeEvent
+
+	// Response is the response of the Events API, for events queued using
+	// Send which were delivered successfully.
+	Response *V2EventResponse
+
+	// ChangeResponse is the response of the Events API, for events queued
+	// using SendChange which were delivered successfully.
+	ChangeResponse *ChangeEventResponse
+
+	// Attempts is the number of attempts made to deliver the event.
+	Attempts int
+
+	// Err is the error of the last attempt, when the event was dropped
+	// without being delivered: either because the Events API rejected it
+	// (e.g., it was invalid), or because it ran out of attempts.
+	Err error
+}
+
+// EventSender delivers Events API V2 events and change events asynchronously,
+// so that sending events doesn't block, and events aren't lost when the Events
+// API is unavailable. Queued events are delivered by a bounded number of
+// workers, and retried with exponential backoff until they're delivered, or
+// rejected by the Events API. Each event is sent on its own, as the Events API
+// doesn't accept batches of events; use WithEventConcurrency to send more of
+// them at once. The retry strategy of the client isn't used, as the
+// EventSender retries events itself.
+//
+// When configured with a spool directory using WithEventSpool, events are
+// written to the spool before being queued, and only removed from it once
+// they've been delivered or rejected. The events left in the spool when the
+// process exits are delivered by the next EventSender using the same spool.
+// The spool directory is locked while it's used, so that its events aren't
+// delivered twice by EventSenders sharing it.
+//
+// An EventSender is safe for concurrent use.
+type EventSender struct {
+	client *Client
+
+	spoolDir    string
+	spool       *eventSpool
+	concurrency int
+	queueSize   int
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	onDelivery  func(EventDelivery)
+
+	queue  chan *spooledEvent
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	closed  bool
+	pending int
+	idle    chan struct{}
+}
+
+// EventSenderOption configures an EventSender.
+type EventSenderOption func(*EventSender)
+
+// WithEventSpool configures the EventSender to persist undelivered events to
+// files in the directory, which is created if it doesn't exist. The directory
+// can't be used by more than one EventSender at a time.
+func WithEventSpool(dir string) EventSenderOption {
+	return func(s *EventSender) {
+		s.spoolDir = dir
+	}
+}
+
+// WithEventConcurrency sets the maximum number of events delivered
+// concurrently. The default is 4.
+func WithEventConcurrency(n int) EventSenderOption {
+	return func(s *EventSender) {
+		s.concurrency = n
+	}
+}
+
+// WithEventQueueSize sets the maximum number of events waiting to be
+// delivered, after which sending events blocks. The default is 1000.
+func WithEventQueueSize(n int) EventSenderOption {
+	return func(s *EventSender) {
+		s.queueSize = n
+	}
+}
+
+// WithEventBackoff sets the delay before retrying to deliver an event, which
+// starts at base and doubles after each attempt, up to max. The defaults are
+// 1 second and 1 minute.
+func WithEventBackoff(base, max time.Duration) EventSenderOption {
+	return func(s *EventSender) {
+		s.baseDelay = base
+		s.maxDelay = max
+	}
+}
+
+// WithEventMaxAttempts sets the maximum number of attempts made to deliver
+// each event, after which it's dropped. The default of 0 retries events until
+// they're delivered, or the EventSender is closed.
+func WithEventMaxAttempts(n int) EventSenderOption {
+	return func(s *EventSender) {
+		s.maxAttempts = n
+	}
+}
+
+// WithEventDeliveryCallback sets a function called with the outcome of each
+// event once it's delivered, or dropped. It's called concurrently by the
+// workers delivering events, so must be safe for concurrent use.
+func WithEventDeliveryCallback(fn func(EventDelivery)) EventSenderOption {
+	return func(s *EventSender) {
+		s.onDelivery = fn
+	}
+}
+
+// NewEventSender returns an EventSender delivering events using the client.
+// When a spool directory is configured, the events found in it are queued to
+// be delivered, and ErrEventSpoolLocked is returned if another EventSender is
+// using it.
+func NewEventSender(client *Client, opts ...EventSenderOption) (*EventSender, error) {
+	s := &EventSender{
+		client:      client,
+		concurrency: defaultEventConcurrency,
+		queueSize:   defaultEventQueueSize,
+		baseDelay:   defaultEventBaseDelay,
+		maxDelay:    defaultEventMaxDelay,
+		idle:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.concurrency < 1 {
+		s.concurrency = 1
+	}
+
+	if s.queueSize < 1 {
+		s.queueSize = 1
+	}
+
+	var spooled []*spooledEvent
+	if s.spoolDir != "" {
+		var err error
+		if s.spool, spooled, err = openEventSpool(s.spoolDir); err != nil {
+			return nil, err
+		}
+	}
+
+	s.queue = make(chan *spooledEvent, s.queueSize)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < s.concurrency; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	if len(spooled) > 0 {
+		s.mu.Lock()
+		s.pending += len(spooled)
+		s.mu.Unlock()
+
+		// queue the spooled events in the background, as there may be more
+		// of them than fit in the queue
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+
+			for _, e := range spooled {
+				select {
+				case s.queue <- e:
+				case <-s.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return s, nil
+}
+
+// Send queues the event to be delivered. When a spool is configured, the
+// event has been written to it when Send returns. Send blocks while the queue
+// is full, until ctx is done.
+func (s *EventSender) Send(ctx context.Context, e *V2Event) error {
+	return s.enqueue(ctx, &spooledEvent{Event: e})
+}
+
+// SendChange queues the change event to be delivered, like Send.
+func (s *EventSender) SendChange(ctx context.Context, e ChangeEvent) error {
+	return s.enqueue(ctx, &spooledEvent{ChangeEvent: &e})
+}
+
+func (s *EventSender) enqueue(ctx context.Context, e *spooledEvent) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrEventSenderClosed
+	}
+
+	s.pending++
+	s.mu.Unlock()
+
+	if s.spool != nil {
+		if err := s.spool.write(e); err != nil {
+			s.done()
+			return err
+		}
+	}
+
+	select {
+	case s.queue <- e:
+		return nil
+
+	case <-ctx.Done():
+		s.discard(e)
+		return ctx.Err()
+
+	case <-s.ctx.Done():
+		s.discard(e)
+		return ErrEventSenderClosed
+	}
+}
+
+// discard removes an event which couldn't be queued.
+func (s *EventSender) discard(e *spooledEvent) {
+	if s.spool != nil {
+		s.spool.remove(e)
+	}
+
+	s.done()
+}
+
+// done marks a pending event as delivered or dropped.
+func (s *EventSender) done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending--
+	if s.pending == 0 {
+		close(s.idle)
+		s.idle = make(chan struct{})
+	}
+}
+
+// Flush blocks until all the queued events have been delivered or dropped,
+// or until ctx is done.
+func (s *EventSender) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if s.pending == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+
+	idle := s.idle
+	s.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new events, and waits for the queued events to be
+// delivered until ctx is done. The events which are still undelivered after
+// that are left in the spool, if one is configured, and an error is returned.
+// The lock on the spool is released once Close returns.
+func (s *EventSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.closed = true
+	s.mu.Unlock()
+
+	err := s.Flush(ctx)
+
+	s.cancel()
+	s.wg.Wait()
+
+	if s.spool != nil {
+		s.spool.close()
+	}
+
+	if err != nil {
+		s.mu.Lock()
+		pending := s.pending
+		s.mu.Unlock()
+
+		return fmt.Errorf("%d events were not delivered: %w", pending, err)
+	}
+
+	return nil
+}
+
+func (s *EventSender) worker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case e := <-s.queue:
+			if !s.deliver(e) {
+				return
+			}
+
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver delivers the event, retrying it until it's delivered or dropped. It
+// returns false if the EventSender was closed before then.
+func (s *EventSender) deliver(e *spooledEvent) bool {
+	d := EventDelivery{Event: e.Event, ChangeEvent: e.ChangeEvent}
+
+	// the event is retried below, so retrying each attempt in the client
+	// would multiply the attempts made, and delay closing the EventSender
+	ctx := withoutRetries(s.ctx)
+
+	for {
+		d.Attempts++
+
+		var err error
+		if e.Event != nil {
+			d.Response, err = s.client.ManageEventWithContext(ctx, e.Event)
+		} else {
+			d.ChangeResponse, err = s.client.CreateChangeEventWithContext(ctx, *e.ChangeEvent)
+		}
+
+		if err == nil || isPermanentEventError(err) {
+			d.Err = err
+			break
+		}
+
+		if s.ctx.Err() != nil {
+			// the send failed because the EventSender was closed, so the
+			// event is left in the spool, to be delivered later
+			return false
+		}
+
+		if s.maxAttempts > 0 && d.Attempts >= s.maxAttempts {
+			d.Err = err
+			break
+		}
+
+		select {
+		case <-time.After(randomDelay(0, exponentialDelay(s.baseDelay, s.maxDelay, d.Attempts-1))):
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+
+	if s.spool != nil {
+		s.spool.remove(e)
+	}
+
+	if s.onDelivery != nil {
+		s.onDelivery(d)
+	}
+
+	s.done()
+
+	return true
+}
+
+// isPermanentEventError returns whether the error is a response from the
+// Events API which won't succeed when retried, such as an invalid event.
+func isPermanentEventError(err error) bool {
+	var aerr APIError
+	return errors.As(err, &aerr) && aerr.StatusCode >= 400 && !aerr.Temporary()
+}
+
+// spooledEvent is an event queued by an EventSender, as written to the spool.
+type spooledEvent struct {
+	Event       *V2Event     `json:"event,omitempty"`
+	ChangeEvent *ChangeEvent `json:"change_event,omitempty"`
+
+	file string
+}
+
+// eventSpool persists the events of an EventSender to a directory, one file
+// per event, named so that they sort in the order they were sent.
+type eventSpool struct {
+	dir  string
+	lock *os.File
+	seq  atomic.Uint64
+}
+
+func openEventSpool(dir string) (*eventSpool, []*spooledEvent, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create event spool: %w", err)
+	}
+
+	lock, err := lockEventSpool(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, err := readEventSpool(dir)
+	if err != nil {
+		unlockEventSpool(lock)
+		return nil, nil, err
+	}
+
+	return &eventSpool{dir: dir, lock: lock}, events, nil
+}
+
+// readEventSpool returns the events found in the spool directory, in the order
+// they were sent.
+func readEventSpool(dir string) ([]*spooledEvent, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event spool: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	events := make([]*spooledEvent, 0, len(names))
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled event: %w", err)
+		}
+
+		var e spooledEvent
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("failed to decode spooled event %s: %w", file, err)
+		}
+
+		e.file = file
+		events = append(events, &e)
+	}
+
+	return events, nil
+}
+
+// write persists the event to a new file, which is written to a temporary
+// file first, so that partially written events are never read.
+func (s *eventSpool) write(e *spooledEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	name := fmt.Sprintf("%020d-%010d", time.Now().UnixNano(), s.seq.Add(1))
+
+	tmp, err := os.CreateTemp(s.dir, name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+
+	file := filepath.Join(s.dir, name+".json")
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+
+	e.file = file
+
+	return nil
+}
+
+func (s *eventSpool) remove(e *spooledEvent) {
+	if e.file != "" {
+		_ = os.Remove(e.file)
+	}
+}
+
+// close releases the lock on the spool directory.
+func (s *eventSpool) close() {
+	unlockEventSpool(s.lock)
+}
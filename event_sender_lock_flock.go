@@ -0,0 +1,38 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockEventSpool takes an exclusive lock on the spool directory, so that the
+// events in it aren't delivered by several EventSenders at once. The lock is
+// released when the returned file is closed, or when the process exits, so it
+// isn't left behind by a process which crashed.
+func lockEventSpool(dir string) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, eventSpoolLockFile), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock event spool: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w: %s", ErrEventSpoolLocked, dir)
+		}
+
+		return nil, fmt.Errorf("failed to lock event spool: %w", err)
+	}
+
+	return f, nil
+}
+
+func unlockEventSpool(f *os.File) {
+	_ = f.Close()
+}
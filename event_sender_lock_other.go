@@ -0,0 +1,32 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockEventSpool takes an exclusive lock on the spool directory, so that the
+// events in it aren't delivered by several EventSenders at once. The lock file
+// is created exclusively, and removed when the lock is released, so it must be
+// removed by hand if the process holding it crashed.
+func lockEventSpool(dir string) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, eventSpoolLockFile), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%w: %s", ErrEventSpoolLocked, dir)
+		}
+
+		return nil, fmt.Errorf("failed to lock event spool: %w", err)
+	}
+
+	return f, nil
+}
+
+func unlockEventSpool(f *os.File) {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
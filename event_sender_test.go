@@ -0,0 +1,316 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventSender_Deliver(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		var e V2Event
+		_ = json.NewDecoder(r.Body).Decode(&e)
+
+		switch {
+		case e.Action == "invalid":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": "invalid event", "message": "Event object is invalid"}`))
+
+		// fail the first attempt of each event
+		case atomic.AddInt32(&calls, 1)%2 == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+		default:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status": "success", "dedup_key": "` + e.DedupKey + `", "message": "Event processed"}`))
+		}
+	})
+
+	mux.HandleFunc("/v2/change/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "message": "Change event processed"}`))
+	})
+
+	var mu sync.Mutex
+	deliveries := make(map[string]EventDelivery)
+
+	sender, err := NewEventSender(defaultTestClient(server.URL, "foo"),
+		WithEventConcurrency(1),
+		WithEventBackoff(time.Millisecond, 5*time.Millisecond),
+		WithEventDeliveryCallback(func(d EventDelivery) {
+			key := "change"
+			if d.Event != nil {
+				key = d.Event.DedupKey
+			}
+
+			mu.Lock()
+			deliveries[key] = d
+			mu.Unlock()
+		}),
+	)
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	ctx := context.Background()
+
+	testErrCheck(t, "Send()", "", sender.Send(ctx, &V2Event{RoutingKey: "abc", Action: "trigger", DedupKey: "one"}))
+	testErrCheck(t, "Send()", "", sender.Send(ctx, &V2Event{RoutingKey: "abc", Action: "invalid", DedupKey: "two"}))
+	testErrCheck(t, "SendChange()", "", sender.SendChange(ctx, ChangeEvent{RoutingKey: "abc"}))
+
+	testErrCheck(t, "Flush()", "", sender.Flush(ctx))
+
+	mu.Lock()
+	one, two, change := deliveries["one"], deliveries["two"], deliveries["change"]
+	mu.Unlock()
+
+	testErrCheck(t, "deliveries[one].Err", "", one.Err)
+	testEqual(t, 2, one.Attempts)
+	testEqual(t, &V2EventResponse{Status: "success", DedupKey: "one", Message: "Event processed"}, one.Response)
+
+	// invalid events aren't retried
+	testEqual(t, 1, two.Attempts)
+
+	var aerr APIError
+	if !errors.As(two.Err, &aerr) || aerr.StatusCode != http.StatusBadRequest {
+		t.Errorf("deliveries[two].Err = %v, want bad request", two.Err)
+	}
+
+	testErrCheck(t, "deliveries[change].Err", "", change.Err)
+	testEqual(t, "Change event processed", change.ChangeResponse.Message)
+
+	testErrCheck(t, "Close()", "", sender.Close(ctx))
+
+	if err := sender.Send(ctx, &V2Event{}); !errors.Is(err, ErrEventSenderClosed) {
+		t.Errorf("Send() after Close() error = %v, want ErrEventSenderClosed", err)
+	}
+}
+
+func TestEventSender_Spool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	dir := t.TempDir()
+
+	sender, err := NewEventSender(defaultTestClient(server.URL, "foo"),
+		WithEventSpool(dir),
+		WithEventConcurrency(1),
+		WithEventBackoff(time.Millisecond, time.Millisecond),
+	)
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	for _, key := range []string{"a", "b", "c"} {
+		testErrCheck(t, "Send()", "", sender.Send(context.Background(), &V2Event{RoutingKey: "abc", Action: "trigger", DedupKey: key}))
+	}
+
+	// the Events API is unavailable, so the events are left in the spool
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	testErrCheck(t, "Close()", "3 events were not delivered", sender.Close(ctx))
+
+	testEqual(t, 3, len(testSpooledEvents(t, dir)))
+
+	// a new sender delivers the spooled events, in order, once the Events API
+	// is available
+	var mu sync.Mutex
+	var delivered []string
+
+	available := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e V2Event
+		_ = json.NewDecoder(r.Body).Decode(&e)
+
+		mu.Lock()
+		delivered = append(delivered, e.DedupKey)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success"}`))
+	}))
+	defer available.Close()
+
+	sender, err = NewEventSender(defaultTestClient(available.URL, "foo"), WithEventSpool(dir), WithEventConcurrency(1))
+	testErrCheck(t, "NewEventSender()", "", err)
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+
+	mu.Lock()
+	testEqual(t, []string{"a", "b", "c"}, delivered)
+	mu.Unlock()
+
+	testEqual(t, 0, len(testSpooledEvents(t, dir)))
+}
+
+// testSpooledEvents returns the names of the event files in the spool
+// directory.
+func testSpooledEvents(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	testErrCheck(t, "os.ReadDir()", "", err)
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != eventSpoolLockFile {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names
+}
+
+func TestEventSender_SpoolLock(t *testing.T) {
+	dir := t.TempDir()
+	client := defaultTestClient("https://events.example.com", "foo")
+
+	sender, err := NewEventSender(client, WithEventSpool(dir))
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	// the spool can't be used by two senders at once
+	_, err = NewEventSender(client, WithEventSpool(dir))
+	if !errors.Is(err, ErrEventSpoolLocked) {
+		t.Fatalf("NewEventSender() error = %v, want ErrEventSpoolLocked", err)
+	}
+
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+
+	// the lock is released once the sender is closed
+	sender, err = NewEventSender(client, WithEventSpool(dir))
+	testErrCheck(t, "NewEventSender()", "", err)
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+}
+
+func TestEventSender_NoClientRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+	client.retryStrategy = &FullJitterRetryStrategy{MaxRetries: 5, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+	done := make(chan EventDelivery, 1)
+
+	sender, err := NewEventSender(client,
+		WithEventMaxAttempts(2),
+		WithEventBackoff(time.Millisecond, time.Millisecond),
+		WithEventDeliveryCallback(func(d EventDelivery) { done <- d }),
+	)
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	testErrCheck(t, "Send()", "", sender.Send(context.Background(), &V2Event{RoutingKey: "abc"}))
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+
+	// each attempt of the sender is a single request, as the retry strategy
+	// of the client isn't used
+	d := <-done
+	testEqual(t, 2, d.Attempts)
+	testEqual(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestEventSender_MaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	done := make(chan EventDelivery, 1)
+
+	sender, err := NewEventSender(defaultTestClient(server.URL, "foo"),
+		WithEventMaxAttempts(3),
+		WithEventBackoff(time.Millisecond, time.Millisecond),
+		WithEventDeliveryCallback(func(d EventDelivery) { done <- d }),
+	)
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	testErrCheck(t, "Send()", "", sender.Send(context.Background(), &V2Event{RoutingKey: "abc"}))
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+
+	d := <-done
+	testEqual(t, 3, d.Attempts)
+	testEqual(t, int32(3), atomic.LoadInt32(&calls))
+
+	if d.Err == nil {
+		t.Error("expected the delivery to have an error")
+	}
+}
+
+// closingHTTPClient closes the EventSender while the event is being sent, and
+// then responds as the Events API would to a successful send.
+type closingHTTPClient struct {
+	sender **EventSender
+}
+
+func (c closingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	(*c.sender).cancel()
+
+	return &http.Response{
+		StatusCode: http.StatusAccepted,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"status": "success", "message": "Event processed"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestEventSender_CloseDuringSend(t *testing.T) {
+	var sender *EventSender
+
+	client := defaultTestClient("https://events.example.com", "foo")
+	client.HTTPClient = closingHTTPClient{sender: &sender}
+
+	dir := t.TempDir()
+	done := make(chan EventDelivery, 1)
+
+	sender, err := NewEventSender(client,
+		WithEventSpool(dir),
+		WithEventDeliveryCallback(func(d EventDelivery) { done <- d }),
+	)
+	testErrCheck(t, "NewEventSender()", "", err)
+
+	testErrCheck(t, "Send()", "", sender.Send(context.Background(), &V2Event{RoutingKey: "abc", Action: "trigger"}))
+
+	// the event was delivered even though the sender was closed, so it isn't
+	// left in the spool to be delivered again
+	var d EventDelivery
+	select {
+	case d = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivered event wasn't reported")
+	}
+
+	testErrCheck(t, "d.Err", "", d.Err)
+	testEqual(t, 1, d.Attempts)
+
+	testErrCheck(t, "Close()", "", sender.Close(context.Background()))
+
+	testEqual(t, 0, len(testSpooledEvents(t, dir)))
+}
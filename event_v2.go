@@ -5,34 +5,93 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 )
 
+// V2Action is the action of an Events API V2 event.
+type V2Action string
+
+// The actions of Events API V2 events.
+const (
+	// V2ActionTrigger triggers an alert, creating an incident when it's not
+	// deduplicated into an existing one.
+	V2ActionTrigger V2Action = "trigger"
+
+	// V2ActionAcknowledge acknowledges the alert with the event's dedup key.
+	V2ActionAcknowledge V2Action = "acknowledge"
+
+	// V2ActionResolve resolves the alert with the event's dedup key.
+	V2ActionResolve V2Action = "resolve"
+)
+
+// V2Severity is the perceived severity of the status an Events API V2 event is
+// describing.
+type V2Severity string
+
+// The severities of Events API V2 events.
+const (
+	V2SeverityCritical V2Severity = "critical"
+	V2SeverityError    V2Severity = "error"
+	V2SeverityWarning  V2Severity = "warning"
+	V2SeverityInfo     V2Severity = "info"
+)
+
 // V2Event includes the incident/alert details
 type V2Event struct {
-	RoutingKey string        `json:"routing_key"`
-	Action     string        `json:"event_action"`
-	DedupKey   string        `json:"dedup_key,omitempty"`
-	Images     []interface{} `json:"images,omitempty"`
-	Links      []interface{} `json:"links,omitempty"`
-	Client     string        `json:"client,omitempty"`
-	ClientURL  string        `json:"client_url,omitempty"`
-	Payload    *V2Payload    `json:"payload,omitempty"`
+	RoutingKey string `json:"routing_key"`
+
+	// Action is one of V2ActionTrigger, V2ActionAcknowledge, or
+	// V2ActionResolve.
+	Action   V2Action `json:"event_action"`
+	DedupKey string   `json:"dedup_key,omitempty"`
+
+	// Images and Links are the images and links attached to the event.
+	Images []V2Image `json:"images,omitempty"`
+	Links  []V2Link  `json:"links,omitempty"`
+
+	Client    string     `json:"client,omitempty"`
+	ClientURL string     `json:"client_url,omitempty"`
+	Payload   *V2Payload `json:"payload,omitempty"`
 }
 
 // V2Payload represents the individual event details for an event
 type V2Payload struct {
-	Summary   string      `json:"summary"`
-	Source    string      `json:"source"`
-	Severity  string      `json:"severity"`
-	Timestamp string      `json:"timestamp,omitempty"`
+	Summary string `json:"summary"`
+	Source  string `json:"source"`
+
+	// Severity is one of V2SeverityCritical, V2SeverityError,
+	// V2SeverityWarning, or V2SeverityInfo.
+	Severity V2Severity `json:"severity"`
+
+	// Timestamp is the time at which the event was detected or generated, in
+	// ISO 8601 format, such as formatted by NewTime.
+	Timestamp string `json:"timestamp,omitempty"`
+
 	Component string      `json:"component,omitempty"`
 	Group     string      `json:"group,omitempty"`
 	Class     string      `json:"class,omitempty"`
 	Details   interface{} `json:"custom_details,omitempty"`
 }
 
+// V2Image is an image attached to an Events API V2 event.
+type V2Image struct {
+	// Src is the URL of the image, which must be served over HTTPS.
+	Src string `json:"src"`
+
+	// Href is an optional URL the image links to.
+	Href string `json:"href,omitempty"`
+
+	// Alt is an optional alternative text for the image.
+	Alt string `json:"alt,omitempty"`
+}
+
+// V2Link is a link attached to an Events API V2 event.
+type V2Link struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
 // V2EventResponse is the json response body for an event
 type V2EventResponse struct {
 	Status   string   `json:"status,omitempty"`
@@ -41,8 +100,6 @@ type V2EventResponse struct {
 	Errors   []string `json:"errors,omitempty"`
 }
 
-const v2eventEndPoint = "https://events.pagerduty.com/v2/enqueue"
-
 // ManageEvent handles the trigger, acknowledge, and resolve methods for an
 // event.
 //
@@ -170,56 +227,38 @@ type EventsAPIV2ErrorObject struct {
 	Errors []string `json:"errors,omitempty"`
 }
 
-// ManageEventWithContext handles the trigger, acknowledge, and resolve methods for an event.
+// ManageEventWithContext handles the trigger, acknowledge, and resolve methods
+// for an event. To configure the HTTP client, endpoint, or retries used to send
+// the event, use an EventsClient instead.
 func ManageEventWithContext(ctx context.Context, e V2Event) (*V2EventResponse, error) {
-	data, err := json.Marshal(e)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v2eventEndPoint, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
+	return defaultEventsClient.ManageEventWithContext(ctx, &e)
+}
 
-	req.Header.Set("User-Agent", "go-pagerduty/"+Version)
-	req.Header.Set("Content-Type", "application/json")
+// getEventsAPIV2ErrorFromResponse returns the error for an unsuccessful Events
+// API response, and closes its body.
+func getEventsAPIV2ErrorFromResponse(resp *http.Response) EventsAPIV2Error {
+	defer func() { _ = resp.Body.Close() }() // explicitly discard error
 
-	// TODO(theckman): switch to a package-local default client
-	resp, err := http.DefaultClient.Do(req)
+	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return EventsAPIV2Error{
+			StatusCode: resp.StatusCode,
+			message:    fmt.Sprintf("HTTP response with status code: %d: error: %s", resp.StatusCode, err),
+		}
 	}
 
-	defer func() { _ = resp.Body.Close() }() // explicitly discard error
-	if resp.StatusCode != http.StatusAccepted {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return nil, EventsAPIV2Error{
-				StatusCode: resp.StatusCode,
-				message:    fmt.Sprintf("HTTP response with status code: %d: error: %s", resp.StatusCode, err),
-			}
+	// now try to decode the response body into the error object.
+	var eae EventsAPIV2Error
+	if err := json.Unmarshal(b, &eae); err != nil {
+		return EventsAPIV2Error{
+			StatusCode: resp.StatusCode,
+			message:    fmt.Sprintf("HTTP response with status code: %d, JSON unmarshal object body failed: %s, body: %s", resp.StatusCode, err, string(b)),
 		}
-		// now try to decode the response body into the error object.
-		var eae EventsAPIV2Error
-		err = json.Unmarshal(b, &eae)
-		if err != nil {
-			eae = EventsAPIV2Error{
-				StatusCode: resp.StatusCode,
-				message:    fmt.Sprintf("HTTP response with status code: %d, JSON unmarshal object body failed: %s, body: %s", resp.StatusCode, err, string(b)),
-			}
-			return nil, eae
-		}
-
-		eae.StatusCode = resp.StatusCode
-		return nil, eae
 	}
 
-	var eventResponse V2EventResponse
-	if err := json.NewDecoder(resp.Body).Decode(&eventResponse); err != nil {
-		return nil, err
-	}
-	return &eventResponse, nil
+	eae.StatusCode = resp.StatusCode
+
+	return eae
 }
 
 // ManageEvent handles the trigger, acknowledge, and resolve methods for an
@@ -237,7 +276,7 @@ func (c *Client) ManageEventWithContext(ctx context.Context, e *V2Event) (*V2Eve
 		return nil, err
 	}
 
-	resp, err := c.doWithEndpoint(ctx, c.v2EventsAPIEndpoint, http.MethodPost, "/v2/enqueue", false, bytes.NewBuffer(data), nil)
+	resp, err := c.doWithEndpoint(ctx, "ManageEventWithContext", c.v2EventsAPIEndpoint, http.MethodPost, "/v2/enqueue", false, bytes.NewBuffer(data), nil)
 	if err != nil {
 		return nil, err
 	}
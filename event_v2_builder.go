@@ -0,0 +1,248 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The constraints of Events API V2 events, as documented at
+// https://developer.pagerduty.com/docs/events-api-v2/trigger-events/.
+const (
+	v2MaxSummaryLength  = 1024
+	v2MaxDedupKeyLength = 255
+	v2MaxEventSize      = 512 * 1024
+)
+
+// V2EventValidationError is returned by V2Event.Validate when an event doesn't
+// satisfy the constraints of the Events API V2, and so would be rejected by it.
+type V2EventValidationError struct {
+	// Errors describes each of the constraints the event doesn't satisfy.
+	Errors []string
+}
+
+// Error satisfies the error interface.
+func (e V2EventValidationError) Error() string {
+	return "invalid event: " + strings.Join(e.Errors, "; ")
+}
+
+// Validate checks that the event satisfies the documented constraints of the
+// Events API V2, so that invalid events can be caught before sending them. It
+// returns a V2EventValidationError describing each of the constraints the
+// event doesn't satisfy.
+func (e *V2Event) Validate() error {
+	var errs []string
+
+	if e.RoutingKey == "" {
+		errs = append(errs, "routing_key is required")
+	}
+
+	if utf8.RuneCountInString(e.DedupKey) > v2MaxDedupKeyLength {
+		errs = append(errs, fmt.Sprintf("dedup_key must be at most %d characters long", v2MaxDedupKeyLength))
+	}
+
+	switch e.Action {
+	case V2ActionTrigger:
+		errs = append(errs, e.Payload.validate()...)
+
+	case V2ActionAcknowledge, V2ActionResolve:
+		if e.DedupKey == "" {
+			errs = append(errs, fmt.Sprintf("dedup_key is required to %s an alert", e.Action))
+		}
+
+	default:
+		errs = append(errs, fmt.Sprintf("event_action %q is invalid, must be one of %q, %q or %q", e.Action, V2ActionTrigger, V2ActionAcknowledge, V2ActionResolve))
+	}
+
+	for i, image := range e.Images {
+		if image.Src == "" {
+			errs = append(errs, fmt.Sprintf("images[%d].src is required", i))
+		}
+	}
+
+	for i, link := range e.Links {
+		if link.Href == "" {
+			errs = append(errs, fmt.Sprintf("links[%d].href is required", i))
+		}
+	}
+
+	if data, err := json.Marshal(e); err != nil {
+		errs = append(errs, fmt.Sprintf("event can't be encoded: %s", err))
+	} else if len(data) > v2MaxEventSize {
+		errs = append(errs, fmt.Sprintf("event is %d bytes, which is over the limit of %d bytes", len(data), v2MaxEventSize))
+	}
+
+	if len(errs) > 0 {
+		return V2EventValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// validate returns the constraints of trigger events the payload doesn't
+// satisfy.
+func (p *V2Payload) validate() []string {
+	if p == nil {
+		return []string{"payload is required to trigger an alert"}
+	}
+
+	var errs []string
+
+	if p.Summary == "" {
+		errs = append(errs, "payload.summary is required")
+	} else if utf8.RuneCountInString(p.Summary) > v2MaxSummaryLength {
+		errs = append(errs, fmt.Sprintf("payload.summary must be at most %d characters long", v2MaxSummaryLength))
+	}
+
+	if p.Source == "" {
+		errs = append(errs, "payload.source is required")
+	}
+
+	switch p.Severity {
+	case V2SeverityCritical, V2SeverityError, V2SeverityWarning, V2SeverityInfo:
+	default:
+		errs = append(errs, fmt.Sprintf("payload.severity %q is invalid, must be one of %q, %q, %q or %q",
+			p.Severity, V2SeverityCritical, V2SeverityError, V2SeverityWarning, V2SeverityInfo))
+	}
+
+	if _, err := ParseTime(p.Timestamp); err != nil {
+		errs = append(errs, fmt.Sprintf("payload.timestamp %q is not a valid ISO 8601 timestamp", p.Timestamp))
+	}
+
+	return errs
+}
+
+// V2EventBuilder builds Events API V2 events. It's created using one of
+// NewTriggerEvent, NewAcknowledgeEvent, or NewResolveEvent, and the event is
+// returned by its Build method, once validated:
+//
+//	event, err := pagerduty.NewTriggerEvent(routingKey, "Disk is full on db1", "db1", pagerduty.V2SeverityCritical).
+//		WithDedupKey("db1-disk").
+//		WithComponent("postgres").
+//		WithLink("https://grafana.example.com/d/db1", "Dashboard").
+//		Build()
+type V2EventBuilder struct {
+	event V2Event
+}
+
+// NewTriggerEvent returns a builder for an event triggering an alert.
+func NewTriggerEvent(routingKey, summary, source string, severity V2Severity) *V2EventBuilder {
+	return &V2EventBuilder{
+		event: V2Event{
+			RoutingKey: routingKey,
+			Action:     V2ActionTrigger,
+			Payload: &V2Payload{
+				Summary:  summary,
+				Source:   source,
+				Severity: severity,
+			},
+		},
+	}
+}
+
+// NewAcknowledgeEvent returns a builder for an event acknowledging the alert
+// with the dedup key.
+func NewAcknowledgeEvent(routingKey, dedupKey string) *V2EventBuilder {
+	return &V2EventBuilder{
+		event: V2Event{RoutingKey: routingKey, Action: V2ActionAcknowledge, DedupKey: dedupKey},
+	}
+}
+
+// NewResolveEvent returns a builder for an event resolving the alert with the
+// dedup key.
+func NewResolveEvent(routingKey, dedupKey string) *V2EventBuilder {
+	return &V2EventBuilder{
+		event: V2Event{RoutingKey: routingKey, Action: V2ActionResolve, DedupKey: dedupKey},
+	}
+}
+
+// payload returns the payload of the event, creating it if needed, for the
+// payload fields set on acknowledge and resolve events.
+func (b *V2EventBuilder) payload() *V2Payload {
+	if b.event.Payload == nil {
+		b.event.Payload = &V2Payload{}
+	}
+
+	return b.event.Payload
+}
+
+// WithDedupKey sets the dedup key of the event, which identifies the alert it
+// applies to.
+func (b *V2EventBuilder) WithDedupKey(key string) *V2EventBuilder {
+	b.event.DedupKey = key
+	return b
+}
+
+// WithTimestamp sets the time at which the emitting tool detected or generated
+// the event.
+func (b *V2EventBuilder) WithTimestamp(t time.Time) *V2EventBuilder {
+	b.payload().Timestamp = NewTime(t).String()
+	return b
+}
+
+// WithComponent sets the component of the source machine responsible for the
+// event.
+func (b *V2EventBuilder) WithComponent(component string) *V2EventBuilder {
+	b.payload().Component = component
+	return b
+}
+
+// WithGroup sets the logical grouping of components of a service.
+func (b *V2EventBuilder) WithGroup(group string) *V2EventBuilder {
+	b.payload().Group = group
+	return b
+}
+
+// WithClass sets the class or type of the event.
+func (b *V2EventBuilder) WithClass(class string) *V2EventBuilder {
+	b.payload().Class = class
+	return b
+}
+
+// WithCustomDetails sets additional details about the event.
+func (b *V2EventBuilder) WithCustomDetails(details interface{}) *V2EventBuilder {
+	b.payload().Details = details
+	return b
+}
+
+// WithImage attaches an image to the event. The href and alt are optional.
+func (b *V2EventBuilder) WithImage(src, href, alt string) *V2EventBuilder {
+	b.event.Images = append(b.event.Images, V2Image{Src: src, Href: href, Alt: alt})
+	return b
+}
+
+// WithLink attaches a link to the event. The text is optional.
+func (b *V2EventBuilder) WithLink(href, text string) *V2EventBuilder {
+	b.event.Links = append(b.event.Links, V2Link{Href: href, Text: text})
+	return b
+}
+
+// WithClient sets the name and URL of the monitoring client sending the
+// event.
+func (b *V2EventBuilder) WithClient(name, url string) *V2EventBuilder {
+	b.event.Client = name
+	b.event.ClientURL = url
+	return b
+}
+
+// Build validates and returns the event. The returned event is a copy, so the
+// builder can be reused to build similar events.
+func (b *V2EventBuilder) Build() (*V2Event, error) {
+	e := b.event
+
+	if e.Payload != nil {
+		p := *e.Payload
+		e.Payload = &p
+	}
+
+	e.Images = append([]V2Image(nil), e.Images...)
+	e.Links = append([]V2Link(nil), e.Links...)
+
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
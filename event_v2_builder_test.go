@@ -0,0 +1,182 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRoutingKey = "R0123456789ABCDEF0123456789ABCDE"
+
+func TestV2Event_Validate(t *testing.T) {
+	trigger := func() *V2Event {
+		return &V2Event{
+			RoutingKey: testRoutingKey,
+			Action:     V2ActionTrigger,
+			Payload:    &V2Payload{Summary: "disk full", Source: "db1", Severity: V2SeverityCritical},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *V2Event)
+		errs   []string
+	}{
+		{
+			name:   "valid_trigger",
+			modify: func(e *V2Event) {},
+		},
+		{
+			name: "valid_resolve",
+			modify: func(e *V2Event) {
+				e.Action = V2ActionResolve
+				e.DedupKey = "abc"
+				e.Payload = nil
+			},
+		},
+		{
+			name:   "short_routing_key",
+			modify: func(e *V2Event) { e.RoutingKey = "abc" },
+		},
+		{
+			name:   "missing_routing_key",
+			modify: func(e *V2Event) { e.RoutingKey = "" },
+			errs:   []string{"routing_key is required"},
+		},
+		{
+			name:   "action_typo",
+			modify: func(e *V2Event) { e.Action = "acknowlege" },
+			errs:   []string{`event_action "acknowlege" is invalid`},
+		},
+		{
+			name:   "severity_typo",
+			modify: func(e *V2Event) { e.Payload.Severity = "warn" },
+			errs:   []string{`payload.severity "warn" is invalid`},
+		},
+		{
+			name:   "missing_payload",
+			modify: func(e *V2Event) { e.Payload = nil },
+			errs:   []string{"payload is required to trigger an alert"},
+		},
+		{
+			name: "missing_summary_and_source",
+			modify: func(e *V2Event) {
+				e.Payload.Summary = ""
+				e.Payload.Source = ""
+			},
+			errs: []string{"payload.summary is required", "payload.source is required"},
+		},
+		{
+			name:   "summary_too_long",
+			modify: func(e *V2Event) { e.Payload.Summary = strings.Repeat("é", 1025) },
+			errs:   []string{"payload.summary must be at most 1024 characters long"},
+		},
+		{
+			name:   "invalid_timestamp",
+			modify: func(e *V2Event) { e.Payload.Timestamp = "yesterday" },
+			errs:   []string{`payload.timestamp "yesterday" is not a valid ISO 8601 timestamp`},
+		},
+		{
+			name: "missing_dedup_key",
+			modify: func(e *V2Event) {
+				e.Action = V2ActionAcknowledge
+				e.Payload = nil
+			},
+			errs: []string{"dedup_key is required to acknowledge an alert"},
+		},
+		{
+			name:   "dedup_key_too_long",
+			modify: func(e *V2Event) { e.DedupKey = strings.Repeat("a", 256) },
+			errs:   []string{"dedup_key must be at most 255 characters long"},
+		},
+		{
+			name: "images_and_links",
+			modify: func(e *V2Event) {
+				e.Images = []V2Image{{Href: "https://example.com"}}
+				e.Links = []V2Link{{Text: "example"}}
+			},
+			errs: []string{"images[0].src is required", "links[0].href is required"},
+		},
+		{
+			name:   "too_large",
+			modify: func(e *V2Event) { e.Payload.Details = strings.Repeat("a", 512*1024) },
+			errs:   []string{"which is over the limit of 524288 bytes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := trigger()
+			tt.modify(e)
+
+			err := e.Validate()
+			if len(tt.errs) == 0 {
+				testErrCheck(t, "Validate()", "", err)
+				return
+			}
+
+			var verr V2EventValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want a V2EventValidationError", err)
+			}
+
+			if len(verr.Errors) != len(tt.errs) {
+				t.Fatalf("Validate() errors = %q, want %d errors", verr.Errors, len(tt.errs))
+			}
+
+			for i, want := range tt.errs {
+				if !strings.Contains(verr.Errors[i], want) {
+					t.Errorf("Validate() errors[%d] = %q, should contain %q", i, verr.Errors[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestV2EventBuilder(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	b := NewTriggerEvent(testRoutingKey, "Disk is full on db1", "db1", V2SeverityCritical).
+		WithDedupKey("db1-disk").
+		WithTimestamp(ts).
+		WithComponent("postgres").
+		WithGroup("databases").
+		WithClass("disk").
+		WithCustomDetails(map[string]string{"free": "0%"}).
+		WithImage("https://example.com/graph.png", "", "Disk usage").
+		WithLink("https://grafana.example.com/d/db1", "Dashboard").
+		WithClient("Prometheus", "https://prometheus.example.com")
+
+	e, err := b.Build()
+	testErrCheck(t, "Build()", "", err)
+
+	data, err := json.Marshal(e)
+	testErrCheck(t, "json.Marshal()", "", err)
+
+	want := `{"routing_key":"R0123456789ABCDEF0123456789ABCDE","event_action":"trigger","dedup_key":"db1-disk",` +
+		`"images":[{"src":"https://example.com/graph.png","alt":"Disk usage"}],` +
+		`"links":[{"href":"https://grafana.example.com/d/db1","text":"Dashboard"}],` +
+		`"client":"Prometheus","client_url":"https://prometheus.example.com",` +
+		`"payload":{"summary":"Disk is full on db1","source":"db1","severity":"critical","timestamp":"2024-03-01T10:00:00Z",` +
+		`"component":"postgres","group":"databases","class":"disk","custom_details":{"free":"0%"}}}`
+
+	testEqual(t, want, string(data))
+
+	// the built event doesn't share state with the builder
+	b.WithComponent("mysql").WithLink("https://example.com", "")
+	testEqual(t, "postgres", e.Payload.Component)
+	testEqual(t, 1, len(e.Links))
+
+	if _, err := NewTriggerEvent(testRoutingKey, "", "db1", "warn").Build(); err == nil {
+		t.Error("Build() expected an error for an invalid event")
+	}
+
+	resolve, err := NewResolveEvent(testRoutingKey, "db1-disk").Build()
+	testErrCheck(t, "Build()", "", err)
+	testEqual(t, &V2Event{RoutingKey: testRoutingKey, Action: V2ActionResolve, DedupKey: "db1-disk"}, resolve)
+
+	_, err = NewAcknowledgeEvent(testRoutingKey, "").Build()
+	testErrCheck(t, "Build()", "dedup_key is required to acknowledge an alert", err)
+}
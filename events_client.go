@@ -0,0 +1,95 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// EventsClient sends events to the PagerDuty Events API. Unlike Client, it
+// doesn't require a REST API token, as events are authenticated by their
+// routing key.
+//
+// It accepts the same ClientOptions as NewClient, such as WithHTTPClient,
+// WithV2EventsAPIEndpoint (e.g., for the EU service region, a proxy, or a local
+// fake), WithRetryStrategy, WithUserAgent, WithMiddleware, and WithHooks.
+// Options only relevant to the REST API have no effect.
+//
+// Unsuccessful responses are returned as an EventsAPIV2Error.
+type EventsClient struct {
+	client     *Client
+	routingKey string
+}
+
+// defaultEventsClient is used by the package-level functions sending events.
+var defaultEventsClient = NewEventsClient("")
+
+// NewEventsClient creates an Events API client. The routing key is used for the
+// events which don't have their own, and may be empty if all of them do.
+func NewEventsClient(routingKey string, options ...ClientOptions) *EventsClient {
+	client := NewClient("", options...)
+	client.eventsAPIErrors = true
+
+	return &EventsClient{
+		client:     client,
+		routingKey: routingKey,
+	}
+}
+
+// ManageEventWithContext sends an Events API V2 event, to trigger,
+// acknowledge, or resolve an alert.
+func (c *EventsClient) ManageEventWithContext(ctx context.Context, e *V2Event) (*V2EventResponse, error) {
+	event := *e
+	if event.RoutingKey == "" {
+		event.RoutingKey = c.routingKey
+	}
+
+	return c.client.ManageEventWithContext(ctx, &event)
+}
+
+// CreateChangeEventWithContext sends a change event.
+func (c *EventsClient) CreateChangeEventWithContext(ctx context.Context, e ChangeEvent) (*ChangeEventResponse, error) {
+	if e.RoutingKey == "" {
+		e.RoutingKey = c.routingKey
+	}
+
+	return c.client.CreateChangeEventWithContext(ctx, e)
+}
+
+// CreateEventWithContext sends an event to the legacy Events API V1, to
+// trigger, acknowledge, or resolve a problem. The routing key of the client is
+// used as the service key of the events which don't have one.
+//
+// If the API responded, the returned EventResponse has its HTTPStatus set, even
+// when an error is returned.
+func (c *EventsClient) CreateEventWithContext(ctx context.Context, e Event) (*EventResponse, error) {
+	if e.ServiceKey == "" {
+		e.ServiceKey = c.routingKey
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.doWithEndpoint(ctx, "CreateEventWithContext", c.client.v2EventsAPIEndpoint, http.MethodPost, eventPath, false, bytes.NewBuffer(data), nil)
+	if err != nil {
+		var eae EventsAPIV2Error
+		if errors.As(err, &eae) {
+			return &EventResponse{HTTPStatus: eae.StatusCode}, err
+		}
+
+		return nil, err
+	}
+
+	var eventResponse EventResponse
+	if err := c.client.decodeJSON(resp, &eventResponse); err != nil {
+		return nil, err
+	}
+
+	eventResponse.HTTPStatus = resp.StatusCode
+
+	return &eventResponse, nil
+}
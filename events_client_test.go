@@ -0,0 +1,178 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventsClient_ManageEventWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testEqual(t, "test-agent", r.Header.Get("User-Agent"))
+		testEqual(t, "", r.Header.Get("Authorization"))
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var e V2Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		testEqual(t, "default-key", e.RoutingKey)
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "dedup_key": "abc", "message": "Event processed"}`))
+	})
+
+	client := NewEventsClient("default-key",
+		WithV2EventsAPIEndpoint(server.URL),
+		WithUserAgent("test-agent"),
+		WithRetryStrategy(&FullJitterRetryStrategy{MaxRetries: 1, BaseDelay: time.Millisecond, RetryNonIdempotent: true}),
+	)
+
+	event := &V2Event{Action: V2ActionResolve, DedupKey: "abc"}
+
+	res, err := client.ManageEventWithContext(context.Background(), event)
+	testErrCheck(t, "ManageEventWithContext()", "", err)
+
+	testEqual(t, &V2EventResponse{Status: "success", DedupKey: "abc", Message: "Event processed"}, res)
+	testEqual(t, int32(2), atomic.LoadInt32(&calls))
+	testEqual(t, "", event.RoutingKey)
+}
+
+func TestEventsClient_ManageEventWithContext_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": "invalid event", "message": "Event object is invalid", "errors": ["'event_action' is missing or blank"]}`))
+	})
+
+	client := NewEventsClient("", WithV2EventsAPIEndpoint(server.URL))
+
+	_, err := client.ManageEventWithContext(context.Background(), &V2Event{RoutingKey: "abc"})
+
+	var eae EventsAPIV2Error
+	if !errors.As(err, &eae) {
+		t.Fatalf("ManageEventWithContext() error = %v, want an EventsAPIV2Error", err)
+	}
+
+	testEqual(t, true, eae.BadRequest())
+	testEqual(t, []string{"'event_action' is missing or blank"}, eae.APIError.ErrorObject.Errors)
+}
+
+func TestEventsClient_CreateChangeEventWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/change/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		var e ChangeEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		testEqual(t, "default-key", e.RoutingKey)
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "message": "Change event processed"}`))
+	})
+
+	client := NewEventsClient("default-key", WithV2EventsAPIEndpoint(server.URL))
+
+	res, err := client.CreateChangeEventWithContext(context.Background(), ChangeEvent{Payload: ChangeEventPayload{Summary: "deploy"}})
+	testErrCheck(t, "CreateChangeEventWithContext()", "", err)
+
+	testEqual(t, &ChangeEventResponse{Status: "success", Message: "Change event processed"}, res)
+}
+
+func TestEventsClient_CreateEventWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/generic/2010-04-15/create_event.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		var e Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+
+		if e.ServiceKey == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": "invalid event", "message": "Event object is invalid", "errors": ["Service key is the wrong length"]}`))
+			return
+		}
+
+		testEqual(t, "default-key", e.ServiceKey)
+		_, _ = w.Write([]byte(`{"status": "success", "message": "Event processed", "incident_key": "abc"}`))
+	})
+
+	var operation string
+	client := NewEventsClient("default-key",
+		WithV2EventsAPIEndpoint(server.URL),
+		WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				operation, _ = OperationFromContext(req.Context())
+				return next(req)
+			}
+		}),
+	)
+
+	res, err := client.CreateEventWithContext(context.Background(), Event{Type: "trigger", Description: "disk full"})
+	testErrCheck(t, "CreateEventWithContext()", "", err)
+
+	testEqual(t, &EventResponse{Status: "success", Message: "Event processed", IncidentKey: "abc", HTTPStatus: http.StatusOK}, res)
+	testEqual(t, "CreateEventWithContext", operation)
+
+	res, err = NewEventsClient("", WithV2EventsAPIEndpoint(server.URL)).CreateEventWithContext(context.Background(), Event{Type: "trigger"})
+	testErrCheck(t, "CreateEventWithContext()", "Service key is the wrong length", err)
+	testEqual(t, &EventResponse{HTTPStatus: http.StatusBadRequest}, res)
+}
+
+// redirectHTTPClient sends the requests it's given to another host.
+type redirectHTTPClient struct {
+	host *url.URL
+}
+
+func (c redirectHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = c.host.Scheme
+	req.URL.Host = c.host.Host
+	req.Host = ""
+
+	return http.DefaultClient.Do(req)
+}
+
+func TestCreateEventWithHTTPClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/generic/2010-04-15/create_event.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "success", "message": "Event processed", "incident_key": "abc"}`))
+	})
+
+	host, err := url.Parse(server.URL)
+	testErrCheck(t, "url.Parse()", "", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := NewEventsClient("key", WithHTTPClient(redirectHTTPClient{host: host})).CreateEventWithContext(ctx, Event{Type: "trigger"})
+	testErrCheck(t, "CreateEventWithContext()", "", err)
+	testEqual(t, "abc", res.IncidentKey)
+
+	res, err = CreateEventWithHTTPClient(Event{ServiceKey: "key", Type: "trigger"}, redirectHTTPClient{host: host})
+	testErrCheck(t, "CreateEventWithHTTPClient()", "", err)
+	testEqual(t, "abc", res.IncidentKey)
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"net/http"
 
@@ -13,27 +13,26 @@ const (
 )
 
 func main() {
-	http.HandleFunc("/webhook", handler)
-	log.Fatal(http.ListenAndServe(":8080", nil))
-}
+	h := webhookv3.NewHandler(secret)
 
-func handler(w http.ResponseWriter, r *http.Request) {
-	err := webhookv3.VerifySignature(r, secret)
-	if err != nil {
-		switch err {
-		case webhookv3.ErrNoValidSignatures:
-			w.WriteHeader(http.StatusUnauthorized)
+	h.OnIncidentTriggered(func(_ context.Context, e *webhookv3.IncidentEvent) error {
+		if e.Data == nil {
+			return nil
+		}
 
-		case webhookv3.ErrMalformedBody, webhookv3.ErrMalformedHeader:
-			w.WriteHeader(http.StatusBadRequest)
+		log.Printf("incident %d triggered: %s", e.Data.Number, e.Data.Title)
+		return nil
+	})
 
-		default:
-			w.WriteHeader(http.StatusInternalServerError)
+	h.OnPing(func(_ context.Context, e *webhookv3.PingEvent) error {
+		if e.Data == nil {
+			return nil
 		}
 
-		fmt.Fprintf(w, "%v", err)
-		return
-	}
+		log.Printf("received ping: %s", e.Data.Message)
+		return nil
+	})
 
-	fmt.Fprintf(w, "received signed webhook")
+	http.Handle("/webhook", h)
+	log.Fatal(http.ListenAndServe(":8080", nil))
 }
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -67,7 +68,7 @@ func (c *Client) ListExtensionsWithContext(ctx context.Context, o ListExtensionO
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/extensions?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListExtensionsWithContext", "/extensions?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +81,14 @@ func (c *Client) ListExtensionsWithContext(ctx context.Context, o ListExtensionO
 	return &result, nil
 }
 
+// Extensions returns an iterator over all of the extensions matching the
+// options, automatically fetching additional pages as needed.
+func (c *Client) Extensions(ctx context.Context, o ListExtensionOptions) iter.Seq2[Extension, error] {
+	return offsetSeq(ctx, c, "Extensions", "/extensions", o, func(r *ListExtensionResponse) ([]Extension, APIListObject) {
+		return r.Extensions, r.APIListObject
+	})
+}
+
 // CreateExtension creates a single extension.
 //
 // Deprecated: Use CreateExtensionWithContext instead.
@@ -89,7 +98,7 @@ func (c *Client) CreateExtension(e *Extension) (*Extension, error) {
 
 // CreateExtensionWithContext creates a single extension.
 func (c *Client) CreateExtensionWithContext(ctx context.Context, e *Extension) (*Extension, error) {
-	resp, err := c.post(ctx, "/extensions", e, nil)
+	resp, err := c.post(ctx, "CreateExtensionWithContext", "/extensions", e, nil)
 	return getExtensionFromResponse(c, resp, err)
 }
 
@@ -102,7 +111,7 @@ func (c *Client) DeleteExtension(id string) error {
 
 // DeleteExtensionWithContext deletes an extension by its ID.
 func (c *Client) DeleteExtensionWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/extensions/"+id)
+	_, err := c.delete(ctx, "DeleteExtensionWithContext", "/extensions/"+id)
 	return err
 }
 
@@ -115,7 +124,7 @@ func (c *Client) GetExtension(id string) (*Extension, error) {
 
 // GetExtensionWithContext gets an extension by its ID.
 func (c *Client) GetExtensionWithContext(ctx context.Context, id string) (*Extension, error) {
-	resp, err := c.get(ctx, "/extensions/"+id, nil)
+	resp, err := c.get(ctx, "GetExtensionWithContext", "/extensions/"+id, nil)
 	return getExtensionFromResponse(c, resp, err)
 }
 
@@ -128,13 +137,13 @@ func (c *Client) UpdateExtension(id string, e *Extension) (*Extension, error) {
 
 // UpdateExtensionWithContext updates an extension by its ID.
 func (c *Client) UpdateExtensionWithContext(ctx context.Context, id string, e *Extension) (*Extension, error) {
-	resp, err := c.put(ctx, "/extensions/"+id, e, nil)
+	resp, err := c.put(ctx, "UpdateExtensionWithContext", "/extensions/"+id, e, nil)
 	return getExtensionFromResponse(c, resp, err)
 }
 
 // EnableExtension enables a temporarily disabled extension by its ID.
 func (c *Client) EnableExtension(ctx context.Context, id string) (*Extension, error) {
-	resp, err := c.post(ctx, "/extensions/"+id+"/enable", nil, nil)
+	resp, err := c.post(ctx, "EnableExtension", "/extensions/"+id+"/enable", nil, nil)
 	return getExtensionFromResponse(c, resp, err)
 }
 
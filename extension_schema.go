@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -68,7 +69,7 @@ func (c *Client) ListExtensionSchemasWithContext(ctx context.Context, o ListExte
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/extension_schemas?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListExtensionSchemasWithContext", "/extension_schemas?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +82,14 @@ func (c *Client) ListExtensionSchemasWithContext(ctx context.Context, o ListExte
 	return &result, nil
 }
 
+// ExtensionSchemas returns an iterator over all of the extension schemas,
+// automatically fetching additional pages as needed.
+func (c *Client) ExtensionSchemas(ctx context.Context, o ListExtensionSchemaOptions) iter.Seq2[ExtensionSchema, error] {
+	return offsetSeq(ctx, c, "ExtensionSchemas", "/extension_schemas", o, func(r *ListExtensionSchemaResponse) ([]ExtensionSchema, APIListObject) {
+		return r.ExtensionSchemas, r.APIListObject
+	})
+}
+
 // GetExtensionSchema gets a single extension schema.
 //
 // Deprecated: Use GetExtensionSchemaWithContext instead.
@@ -90,7 +99,7 @@ func (c *Client) GetExtensionSchema(id string) (*ExtensionSchema, error) {
 
 // GetExtensionSchemaWithContext gets a single extension schema.
 func (c *Client) GetExtensionSchemaWithContext(ctx context.Context, id string) (*ExtensionSchema, error) {
-	resp, err := c.get(ctx, "/extension_schemas/"+id, nil)
+	resp, err := c.get(ctx, "GetExtensionSchemaWithContext", "/extension_schemas/"+id, nil)
 	return getExtensionSchemaFromResponse(c, resp, err)
 }
 
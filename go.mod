@@ -1,6 +1,6 @@
 module github.com/PagerDuty/go-pagerduty
 
-go 1.19
+go 1.23
 
 require (
 	github.com/google/go-cmp v0.6.0
@@ -0,0 +1,110 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Operation describes a logical API call made by the client, which may be made
+// of multiple HTTP requests when it's retried.
+type Operation struct {
+	// Name is the name of the client method which made the API call, such as
+	// "ListIncidentsWithContext". Methods without a context report the name of
+	// their WithContext counterpart.
+	Name string
+
+	// Method is the HTTP method of the API call.
+	Method string
+
+	// Endpoint is the base URL of the API being called, such as
+	// "https://api.pagerduty.com".
+	Endpoint string
+
+	// Path is the path of the API call, without its query string.
+	Path string
+}
+
+// Hooks are functions called by the client at the different stages of each
+// API call, allowing consumers to instrument the client (e.g., for tracing or
+// metrics). Any of the functions may be nil.
+//
+// The hooks are called for API calls made by the client methods, but not for
+// requests sent using the Do() method. To observe each individual HTTP request,
+// use WithMiddleware() instead.
+type Hooks struct {
+	// OperationStart is called when an API call starts. The context it returns
+	// is used for the rest of the API call, including its HTTP requests, and
+	// is passed to the other hooks.
+	OperationStart func(ctx context.Context, op Operation) context.Context
+
+	// OperationEnd is called when an API call completes, with the response of
+	// its last attempt if there was one, and the error returned by the call.
+	OperationEnd func(ctx context.Context, op Operation, resp *http.Response, err error)
+
+	// Retry is called when an attempt of an API call failed, and is going to
+	// be retried after the delay. The attempt number starts at 0.
+	Retry func(ctx context.Context, op Operation, attempt int, delay time.Duration)
+
+	// RateLimitWait is called when an attempt of an API call was delayed by
+	// the rate limiter configured using WithRateLimiter().
+	RateLimitWait func(ctx context.Context, op Operation, delay time.Duration)
+}
+
+// WithHooks adds hooks to the client. When multiple hooks are added, they are
+// called in the order they were added.
+func WithHooks(hooks Hooks) ClientOptions {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+type hookList []Hooks
+
+func (hl hookList) operationStart(ctx context.Context, op Operation) context.Context {
+	for _, h := range hl {
+		if h.OperationStart != nil {
+			ctx = h.OperationStart(ctx, op)
+		}
+	}
+
+	return ctx
+}
+
+func (hl hookList) operationEnd(ctx context.Context, op Operation, resp *http.Response, err error) {
+	for _, h := range hl {
+		if h.OperationEnd != nil {
+			h.OperationEnd(ctx, op, resp, err)
+		}
+	}
+}
+
+func (hl hookList) retry(ctx context.Context, op Operation, attempt int, delay time.Duration) {
+	for _, h := range hl {
+		if h.Retry != nil {
+			h.Retry(ctx, op, attempt, delay)
+		}
+	}
+}
+
+func (hl hookList) rateLimitWait(ctx context.Context, op Operation, delay time.Duration) {
+	for _, h := range hl {
+		if h.RateLimitWait != nil {
+			h.RateLimitWait(ctx, op, delay)
+		}
+	}
+}
+
+type attemptCtxKey struct{}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptCtxKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt number, starting at 0, of the HTTP
+// request made by the client with the provided context. This allows
+// Middleware to tell retries apart from the first attempt of an API call.
+func AttemptFromContext(ctx context.Context) (int, bool) {
+	attempt, ok := ctx.Value(attemptCtxKey{}).(int)
+	return attempt, ok
+}
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -77,13 +78,13 @@ type Incident struct {
 	IncidentNumber       uint                 `json:"incident_number,omitempty"`
 	Title                string               `json:"title,omitempty"`
 	Description          string               `json:"description,omitempty"`
-	CreatedAt            string               `json:"created_at,omitempty"`
+	CreatedAt            Time                 `json:"created_at,omitempty"`
 	PendingActions       []PendingAction      `json:"pending_actions,omitempty"`
 	IncidentKey          string               `json:"incident_key,omitempty"`
 	Service              APIObject            `json:"service,omitempty"`
 	Assignments          []Assignment         `json:"assignments,omitempty"`
 	Acknowledgements     []Acknowledgement    `json:"acknowledgements,omitempty"`
-	LastStatusChangeAt   string               `json:"last_status_change_at,omitempty"`
+	LastStatusChangeAt   Time                 `json:"last_status_change_at,omitempty"`
 	LastStatusChangeBy   APIObject            `json:"last_status_change_by,omitempty"`
 	FirstTriggerLogEntry FirstTriggerLogEntry `json:"first_trigger_log_entry,omitempty"`
 	EscalationPolicy     APIObject            `json:"escalation_policy,omitempty"`
@@ -100,8 +101,8 @@ type Incident struct {
 	Occurrence           *Occurrence          `json:"occurrence,omitempty"`
 	IncidentResponders   []IncidentResponders `json:"incidents_responders,omitempty"`
 	ResponderRequests    []ResponderRequest   `json:"responder_requests,omitempty"`
-	ResolvedAt           string               `json:"resolved_at,omitempty"`
-	UpdatedAt            string               `json:"updated_at,omitempty"`
+	ResolvedAt           Time                 `json:"resolved_at,omitempty"`
+	UpdatedAt            Time                 `json:"updated_at,omitempty"`
 }
 
 // ListIncidentsResponse is the response structure when calling the ListIncident API endpoint.
@@ -172,7 +173,7 @@ func (c *Client) ListIncidentsWithContext(ctx context.Context, o ListIncidentsOp
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListIncidentsWithContext", "/incidents?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +186,14 @@ func (c *Client) ListIncidentsWithContext(ctx context.Context, o ListIncidentsOp
 	return &result, nil
 }
 
+// Incidents returns an iterator over all of the incidents matching the
+// options, automatically fetching additional pages as needed.
+func (c *Client) Incidents(ctx context.Context, o ListIncidentsOptions) iter.Seq2[Incident, error] {
+	return offsetSeq(ctx, c, "Incidents", "/incidents", o, func(r *ListIncidentsResponse) ([]Incident, APIListObject) {
+		return r.Incidents, r.APIListObject
+	})
+}
+
 // createIncidentResponse is returned from the API when creating a response.
 type createIncidentResponse struct {
 	Incident Incident `json:"incident"`
@@ -261,7 +270,7 @@ func (c *Client) CreateIncidentWithContext(ctx context.Context, from string, o *
 		"incident": o,
 	}
 
-	resp, err := c.post(ctx, "/incidents", d, h)
+	resp, err := c.post(ctx, "CreateIncidentWithContext", "/incidents", d, h)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +307,7 @@ func (c *Client) ManageIncidentsWithContext(ctx context.Context, from string, in
 		"From": from,
 	}
 
-	resp, err := c.put(ctx, "/incidents", d, h)
+	resp, err := c.put(ctx, "ManageIncidentsWithContext", "/incidents", d, h)
 	if err != nil {
 		return nil, err
 	}
@@ -328,7 +337,8 @@ func (c *Client) MergeIncidentsWithContext(ctx context.Context, from, id string,
 		"From": from,
 	}
 
-	resp, err := c.put(ctx, "/incidents/"+id+"/merge", d, h)
+	// merging is not idempotent, so make sure it's not retried by default
+	resp, err := c.put(withNonIdempotent(ctx), "MergeIncidentsWithContext", "/incidents/"+id+"/merge", d, h)
 	if err != nil {
 		return nil, err
 	}
@@ -350,7 +360,7 @@ func (c *Client) GetIncident(id string) (*Incident, error) {
 
 // GetIncidentWithContext shows detailed information about an incident.
 func (c *Client) GetIncidentWithContext(ctx context.Context, id string) (*Incident, error) {
-	resp, err := c.get(ctx, "/incidents/"+id, nil)
+	resp, err := c.get(ctx, "GetIncidentWithContext", "/incidents/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -390,7 +400,7 @@ func (c *Client) ListIncidentNotes(id string) ([]IncidentNote, error) {
 
 // ListIncidentNotesWithContext lists existing notes for the specified incident.
 func (c *Client) ListIncidentNotesWithContext(ctx context.Context, id string) ([]IncidentNote, error) {
-	resp, err := c.get(ctx, "/incidents/"+id+"/notes", nil)
+	resp, err := c.get(ctx, "ListIncidentNotesWithContext", "/incidents/"+id+"/notes", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -486,7 +496,7 @@ func (c *Client) ListIncidentAlertsWithContext(ctx context.Context, id string, o
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents/"+id+"/alerts?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListIncidentAlertsWithContext", "/incidents/"+id+"/alerts?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -499,6 +509,14 @@ func (c *Client) ListIncidentAlertsWithContext(ctx context.Context, id string, o
 	return &result, err
 }
 
+// IncidentAlerts returns an iterator over all of the alerts for the specified
+// incident, automatically fetching additional pages as needed.
+func (c *Client) IncidentAlerts(ctx context.Context, id string, o ListIncidentAlertsOptions) iter.Seq2[IncidentAlert, error] {
+	return offsetSeq(ctx, c, "IncidentAlerts", "/incidents/"+id+"/alerts", o, func(r *ListAlertsResponse) ([]IncidentAlert, APIListObject) {
+		return r.Alerts, r.APIListObject
+	})
+}
+
 // CreateIncidentNoteWithResponse creates a new note for the specified incident.
 //
 // Deprecated: Use CreateIncidentNoteWithContext instead.
@@ -516,7 +534,7 @@ func (c *Client) CreateIncidentNoteWithContext(ctx context.Context, id string, n
 		"From": note.User.Summary,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/notes", d, h)
+	resp, err := c.post(ctx, "CreateIncidentNoteWithContext", "/incidents/"+id+"/notes", d, h)
 	if err != nil {
 		return nil, err
 	}
@@ -537,7 +555,7 @@ func (c *Client) CreateIncidentNote(id string, note IncidentNote) error {
 	headers := make(map[string]string)
 	headers["From"] = note.User.Summary
 	data["note"] = note
-	_, err := c.post(context.Background(), "/incidents/"+id+"/notes", data, headers)
+	_, err := c.post(context.Background(), "CreateIncidentNote", "/incidents/"+id+"/notes", data, headers)
 	return err
 }
 
@@ -563,7 +581,7 @@ func (c *Client) SnoozeIncidentWithContext(ctx context.Context, id string, o Sno
 		"duration": o.Duration,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/snooze", body, headers)
+	resp, err := c.post(ctx, "SnoozeIncidentWithContext", "/incidents/"+id+"/snooze", body, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -585,7 +603,7 @@ func (c *Client) SnoozeIncident(id string, o SnoozeIncidentOptions) error {
 	data := make(map[string]uint)
 	data["duration"] = o.Duration
 
-	_, err := c.post(context.Background(), "/incidents/"+id+"/snooze", data, headers)
+	_, err := c.post(context.Background(), "SnoozeIncident", "/incidents/"+id+"/snooze", data, headers)
 	return err
 }
 
@@ -638,7 +656,7 @@ func (c *Client) ListIncidentLogEntriesWithContext(ctx context.Context, id strin
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents/"+id+"/log_entries?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListIncidentLogEntriesWithContext", "/incidents/"+id+"/log_entries?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -651,6 +669,14 @@ func (c *Client) ListIncidentLogEntriesWithContext(ctx context.Context, id strin
 	return &result, nil
 }
 
+// IncidentLogEntries returns an iterator over all of the log entries for the
+// specified incident, automatically fetching additional pages as needed.
+func (c *Client) IncidentLogEntries(ctx context.Context, id string, o ListIncidentLogEntriesOptions) iter.Seq2[LogEntry, error] {
+	return offsetSeq(ctx, c, "IncidentLogEntries", "/incidents/"+id+"/log_entries", o, func(r *ListIncidentLogEntriesResponse) ([]LogEntry, APIListObject) {
+		return r.LogEntries, r.APIListObject
+	})
+}
+
 // IncidentResponders contains details about responders to an incident.
 type IncidentResponders struct {
 	State       string    `json:"state"`
@@ -709,7 +735,7 @@ func (c *Client) ResponderRequestWithContext(ctx context.Context, id string, o R
 		"From": o.From,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/responder_requests", o, h)
+	resp, err := c.post(ctx, "ResponderRequestWithContext", "/incidents/"+id+"/responder_requests", o, h)
 	if err != nil {
 		return nil, err
 	}
@@ -731,7 +757,7 @@ func (c *Client) GetIncidentAlert(incidentID, alertID string) (*IncidentAlertRes
 
 // GetIncidentAlertWithContext gets the alert that triggered the incident.
 func (c *Client) GetIncidentAlertWithContext(ctx context.Context, incidentID, alertID string) (*IncidentAlertResponse, error) {
-	resp, err := c.get(ctx, "/incidents/"+incidentID+"/alerts/"+alertID, nil)
+	resp, err := c.get(ctx, "GetIncidentAlertWithContext", "/incidents/"+incidentID+"/alerts/"+alertID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -750,7 +776,7 @@ func (c *Client) ManageIncidentAlerts(ctx context.Context, incidentID, from stri
 		"From": from,
 	}
 
-	resp, err := c.put(ctx, "/incidents/"+incidentID+"/alerts/", alerts, h)
+	resp, err := c.put(ctx, "ManageIncidentAlerts", "/incidents/"+incidentID+"/alerts/", alerts, h)
 	if err != nil {
 		return nil, err
 	}
@@ -781,7 +807,7 @@ func (c *Client) CreateIncidentStatusUpdate(ctx context.Context, id string, from
 		"From": from,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/status_updates", d, h)
+	resp, err := c.post(ctx, "CreateIncidentStatusUpdate", "/incidents/"+id+"/status_updates", d, h)
 	if err != nil {
 		return IncidentStatusUpdate{}, err
 	}
@@ -838,7 +864,7 @@ type RemoveIncidentNotificationSubscribersResponse struct {
 
 // ListIncidentNotificationSubscribersWithContext lists notification subscribers for the specified incident.
 func (c *Client) ListIncidentNotificationSubscribersWithContext(ctx context.Context, id string) (*ListIncidentNotificationSubscribersResponse, error) {
-	resp, err := c.get(ctx, "/incidents/"+id+"/status_updates/subscribers", nil)
+	resp, err := c.get(ctx, "ListIncidentNotificationSubscribersWithContext", "/incidents/"+id+"/status_updates/subscribers", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -851,13 +877,22 @@ func (c *Client) ListIncidentNotificationSubscribersWithContext(ctx context.Cont
 	return &result, nil
 }
 
+// IncidentNotificationSubscribers returns an iterator over all of the
+// notification subscribers for the specified incident, automatically fetching
+// additional pages as needed.
+func (c *Client) IncidentNotificationSubscribers(ctx context.Context, id string) iter.Seq2[IncidentNotificationSubscriptionWithContext, error] {
+	return offsetSeq(ctx, c, "IncidentNotificationSubscribers", "/incidents/"+id+"/status_updates/subscribers", nil, func(r *ListIncidentNotificationSubscribersResponse) ([]IncidentNotificationSubscriptionWithContext, APIListObject) {
+		return r.Subscribers, r.APIListObject
+	})
+}
+
 // AddIncidentNotificationSubscribersWithContext adds notification subscribers for the specified incident.
 func (c *Client) AddIncidentNotificationSubscribersWithContext(ctx context.Context, id string, subscribers []IncidentNotificationSubscriber) (*AddIncidentNotificationSubscribersResponse, error) {
 	d := map[string][]IncidentNotificationSubscriber{
 		"subscribers": subscribers,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/status_updates/subscribers", d, nil)
+	resp, err := c.post(ctx, "AddIncidentNotificationSubscribersWithContext", "/incidents/"+id+"/status_updates/subscribers", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -876,7 +911,7 @@ func (c *Client) RemoveIncidentNotificationSubscribersWithContext(ctx context.Co
 		"subscribers": subscribers,
 	}
 
-	resp, err := c.post(ctx, "/incidents/"+id+"/status_updates/unsubscribe", d, nil)
+	resp, err := c.post(ctx, "RemoveIncidentNotificationSubscribersWithContext", "/incidents/"+id+"/status_updates/unsubscribe", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +39,7 @@ func (c *Client) ListIncidentTypes(ctx context.Context, o ListIncidentTypesOptio
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents/types?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListIncidentTypes", "/incidents/types?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (c *Client) CreateIncidentType(ctx context.Context, o CreateIncidentTypeOpt
 		"incident_type": o,
 	}
 
-	resp, err := c.post(ctx, "/incidents/types", d, nil)
+	resp, err := c.post(ctx, "CreateIncidentType", "/incidents/types", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +81,7 @@ type GetIncidentTypeOptions struct{}
 
 // GetIncidentType retrieves a specific incident type by ID or name.
 func (c *Client) GetIncidentType(ctx context.Context, idOrName string, o GetIncidentTypeOptions) (*IncidentType, error) {
-	resp, err := c.get(ctx, "/incidents/types/"+idOrName, nil)
+	resp, err := c.get(ctx, "GetIncidentType", "/incidents/types/"+idOrName, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +105,7 @@ func (c *Client) UpdateIncidentType(ctx context.Context, idOrName string, o Upda
 		"incident_type": o,
 	}
 
-	resp, err := c.put(ctx, "/incidents/types/"+idOrName, d, nil)
+	resp, err := c.put(ctx, "UpdateIncidentType", "/incidents/types/"+idOrName, d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +167,7 @@ func (c *Client) ListIncidentTypeFields(ctx context.Context, typeIDOrName string
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListIncidentTypeFields", "/incidents/types/"+typeIDOrName+"/custom_fields?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +200,7 @@ func (c *Client) CreateIncidentTypeField(ctx context.Context, typeIDOrName strin
 		"field": o,
 	}
 
-	resp, err := c.post(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields", d, nil)
+	resp, err := c.post(ctx, "CreateIncidentTypeField", "/incidents/types/"+typeIDOrName+"/custom_fields", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -223,7 +223,7 @@ func (c *Client) GetIncidentTypeField(ctx context.Context, typeIDOrName string,
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetIncidentTypeField", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -248,7 +248,7 @@ func (c *Client) UpdateIncidentTypeField(ctx context.Context, typeIDOrName, fiel
 		"field": o,
 	}
 
-	resp, err := c.put(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID, d, nil)
+	resp, err := c.put(ctx, "UpdateIncidentTypeField", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID, d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +261,7 @@ func (c *Client) UpdateIncidentTypeField(ctx context.Context, typeIDOrName, fiel
 
 // DeleteIncidentTypeField removes a custom field from an incident type.
 func (c *Client) DeleteIncidentTypeField(ctx context.Context, typeIDOrName, fieldID string) error {
-	_, err := c.delete(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID)
+	_, err := c.delete(ctx, "DeleteIncidentTypeField", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID)
 	return err
 }
 
@@ -275,7 +275,7 @@ type ListIncidentTypeFieldOptionsResponse struct {
 
 // ListIncidentTypeFieldOptions retrieves all options for a specific custom field.
 func (c *Client) ListIncidentTypeFieldOptions(ctx context.Context, typeIDOrName, fieldID string, o ListIncidentTypeFieldOptionsOptions) (*ListIncidentTypeFieldOptionsResponse, error) {
-	resp, err := c.get(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options", nil)
+	resp, err := c.get(ctx, "ListIncidentTypeFieldOptions", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -301,7 +301,7 @@ func (c *Client) CreateIncidentTypeFieldOption(ctx context.Context, typeIDOrName
 		"field_option": o,
 	}
 
-	resp, err := c.post(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options", d, nil)
+	resp, err := c.post(ctx, "CreateIncidentTypeFieldOption", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +317,7 @@ type GetIncidentTypeFieldOptionOptions struct{}
 
 // GetIncidentTypeFieldOption retrieves a specific option for a custom field.
 func (c *Client) GetIncidentTypeFieldOption(ctx context.Context, typeIDOrName, fieldID, fieldOptionID string, o GetIncidentTypeFieldOptionOptions) (*IncidentTypeFieldOption, error) {
-	resp, err := c.get(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+fieldOptionID, nil)
+	resp, err := c.get(ctx, "GetIncidentTypeFieldOption", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+fieldOptionID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +340,7 @@ func (c *Client) UpdateIncidentTypeFieldOption(ctx context.Context, typeIDOrName
 		"field_option": o,
 	}
 
-	resp, err := c.put(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+o.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateIncidentTypeFieldOption", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+o.ID, d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -353,6 +353,6 @@ func (c *Client) UpdateIncidentTypeFieldOption(ctx context.Context, typeIDOrName
 
 // DeleteIncidentTypeFieldOption removes an option from a custom field.
 func (c *Client) DeleteIncidentTypeFieldOption(ctx context.Context, typeIDOrName, fieldID, fieldOptionID string) error {
-	_, err := c.delete(ctx, "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+fieldOptionID)
+	_, err := c.delete(ctx, "DeleteIncidentTypeFieldOption", "/incidents/types/"+typeIDOrName+"/custom_fields/"+fieldID+"/field_options/"+fieldOptionID)
 	return err
 }
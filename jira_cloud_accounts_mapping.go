@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"context"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -146,7 +147,7 @@ func (c *Client) ListJiraCloudAccountsMappings(ctx context.Context, o ListJiraCl
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/integration-jira-cloud/accounts_mappings?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListJiraCloudAccountsMappings", "/integration-jira-cloud/accounts_mappings?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -159,9 +160,17 @@ func (c *Client) ListJiraCloudAccountsMappings(ctx context.Context, o ListJiraCl
 	return &result, nil
 }
 
+// JiraCloudAccountsMappings returns an iterator over all of the existing account
+// mappings, automatically fetching additional pages as needed.
+func (c *Client) JiraCloudAccountsMappings(ctx context.Context, o ListJiraCloudAccountsMappingsOptions) iter.Seq2[JiraCloudAccountsMapping, error] {
+	return offsetSeq(ctx, c, "JiraCloudAccountsMappings", "/integration-jira-cloud/accounts_mappings", o, func(r *ListJiraCloudAccountsMappingsResponse) ([]JiraCloudAccountsMapping, APIListObject) {
+		return r.AccountsMappings, r.APIListObject
+	})
+}
+
 // GetJiraCloudAccountsMapping lists existing account mappings
 func (c *Client) GetJiraCloudAccountsMapping(ctx context.Context, id string) (*JiraCloudAccountsMapping, error) {
-	resp, err := c.get(ctx, "/integration-jira-cloud/accounts_mappings/"+id, nil)
+	resp, err := c.get(ctx, "GetJiraCloudAccountsMapping", "/integration-jira-cloud/accounts_mappings/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +206,7 @@ func (c *Client) ListJiraCloudAccountsMappingRules(ctx context.Context, id strin
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/integration-jira-cloud/accounts_mappings/"+id+"/rules?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListJiraCloudAccountsMappingRules", "/integration-jira-cloud/accounts_mappings/"+id+"/rules?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -210,9 +219,17 @@ func (c *Client) ListJiraCloudAccountsMappingRules(ctx context.Context, id strin
 	return &result, nil
 }
 
+// JiraCloudAccountsMappingRules returns an iterator over all of the rules for a
+// specific account mapping, automatically fetching additional pages as needed.
+func (c *Client) JiraCloudAccountsMappingRules(ctx context.Context, id string, o ListJiraCloudAccountsMappingRulesOptions) iter.Seq2[JiraCloudAccountsMappingRule, error] {
+	return offsetSeq(ctx, c, "JiraCloudAccountsMappingRules", "/integration-jira-cloud/accounts_mappings/"+id+"/rules", o, func(r *ListJiraCloudAccountsMappingRulesResponse) ([]JiraCloudAccountsMappingRule, APIListObject) {
+		return r.Rules, r.APIListObject
+	})
+}
+
 // CreateJiraCloudAccountsMappingRule creates a new rule in Jira Cloud's integration
 func (c *Client) CreateJiraCloudAccountsMappingRule(ctx context.Context, id string, rule JiraCloudAccountsMappingRule) (*JiraCloudAccountsMappingRule, error) {
-	resp, err := c.post(ctx, "/integration-jira-cloud/accounts_mappings/"+id+"/rules", rule, nil)
+	resp, err := c.post(ctx, "CreateJiraCloudAccountsMappingRule", "/integration-jira-cloud/accounts_mappings/"+id+"/rules", rule, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +244,7 @@ func (c *Client) CreateJiraCloudAccountsMappingRule(ctx context.Context, id stri
 
 // GetJiraCloudAccountsMappingRule gets detailed information about an existing rule
 func (c *Client) GetJiraCloudAccountsMappingRule(ctx context.Context, id, ruleID string) (*JiraCloudAccountsMappingRule, error) {
-	resp, err := c.get(ctx, "/integration-jira-cloud/accounts_mappings/"+id+"/rules/"+ruleID, nil)
+	resp, err := c.get(ctx, "GetJiraCloudAccountsMappingRule", "/integration-jira-cloud/accounts_mappings/"+id+"/rules/"+ruleID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +259,7 @@ func (c *Client) GetJiraCloudAccountsMappingRule(ctx context.Context, id, ruleID
 
 // DeleteJiraCloudAccountsMappingRule deletes an existing rule in Jira Cloud's integration
 func (c *Client) DeleteJiraCloudAccountsMappingRule(ctx context.Context, id, ruleID string) error {
-	_, err := c.delete(ctx, "/integration-jira-cloud/accounts_mappings/"+id+"/rules/"+ruleID)
+	_, err := c.delete(ctx, "DeleteJiraCloudAccountsMappingRule", "/integration-jira-cloud/accounts_mappings/"+id+"/rules/"+ruleID)
 	return err
 }
 
@@ -290,7 +307,7 @@ func (c *Client) UpdateJiraCloudAccountsMappingRule(ctx context.Context, account
 		Name:    rule.Name,
 	}
 
-	resp, err := c.put(ctx, "/integration-jira-cloud/accounts_mappings/"+accountMappingID+"/rules/"+rule.ID, o, nil)
+	resp, err := c.put(ctx, "UpdateJiraCloudAccountsMappingRule", "/integration-jira-cloud/accounts_mappings/"+accountMappingID+"/rules/"+rule.ID, o, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"context"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -48,7 +49,7 @@ type ListLicenseAllocationsOptions struct {
 
 func (c *Client) ListLicensesWithContext(ctx context.Context) (*ListLicensesResponse, error) {
 
-	resp, err := c.get(ctx, "/licenses", nil)
+	resp, err := c.get(ctx, "ListLicensesWithContext", "/licenses", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +70,7 @@ func (c *Client) ListLicenseAllocationsWithContext(ctx context.Context, o ListLi
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/license_allocations?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListLicenseAllocationsWithContext", "/license_allocations?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,3 +83,11 @@ func (c *Client) ListLicenseAllocationsWithContext(ctx context.Context, o ListLi
 
 	return &result, nil
 }
+
+// LicenseAllocations returns an iterator over all of the license allocations,
+// automatically fetching additional pages as needed.
+func (c *Client) LicenseAllocations(ctx context.Context, o ListLicenseAllocationsOptions) iter.Seq2[LicenseAllocation, error] {
+	return offsetSeq(ctx, c, "LicenseAllocations", "/license_allocations", o, func(r *ListLicenseAllocationsResponse) ([]LicenseAllocation, APIListObject) {
+		return r.LicenseAllocations, r.APIListObject
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -47,6 +49,12 @@ type LogEntry struct {
 	User     APIObject `json:"user"`
 }
 
+// CreatedAtTime parses CreatedAt, returning the zero time.Time if it's unset.
+func (l LogEntry) CreatedAtTime() (time.Time, error) {
+	t, err := ParseTime(l.CreatedAt)
+	return t.Time, err
+}
+
 // ListLogEntryResponse is the response data when calling the ListLogEntry API endpoint.
 type ListLogEntryResponse struct {
 	APIListObject
@@ -97,7 +105,7 @@ func (c *Client) ListLogEntriesWithContext(ctx context.Context, o ListLogEntries
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/log_entries?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListLogEntriesWithContext", "/log_entries?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +118,14 @@ func (c *Client) ListLogEntriesWithContext(ctx context.Context, o ListLogEntries
 	return &result, err
 }
 
+// LogEntries returns an iterator over all of the log entries matching the
+// options, automatically fetching additional pages as needed.
+func (c *Client) LogEntries(ctx context.Context, o ListLogEntriesOptions) iter.Seq2[LogEntry, error] {
+	return offsetSeq(ctx, c, "LogEntries", "/log_entries", o, func(r *ListLogEntryResponse) ([]LogEntry, APIListObject) {
+		return r.LogEntries, r.APIListObject
+	})
+}
+
 // GetLogEntryOptions is the data structure used when calling the GetLogEntry API endpoint.
 type GetLogEntryOptions struct {
 	TimeZone string   `url:"time_zone,omitempty"`
@@ -130,7 +146,7 @@ func (c *Client) GetLogEntryWithContext(ctx context.Context, id string, o GetLog
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/log_entries/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetLogEntryWithContext", "/log_entries/"+id+"?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
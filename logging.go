@@ -0,0 +1,207 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// redacted replaces the value of sensitive data in log records.
+const redacted = "REDACTED"
+
+// redactedPayloadFields are the JSON fields of request payloads whose values
+// are never logged, such as the routing key of Events API payloads.
+var redactedPayloadFields = map[string]struct{}{
+	"routing_key":     {},
+	"routing_keys":    {},
+	"service_key":     {},
+	"integration_key": {},
+}
+
+// WithLogger configures the client to emit structured log records to logger
+// for each HTTP request attempt it makes, including the operation, method,
+// path, attempt number, response status, and the x-request-id returned by the
+// API. Retries and rate limiter waits are also logged, along with their delay.
+//
+// Attempts which fail, or result in a 429 or 5xx status, are logged at the Warn
+// level, and the rest at the Debug level. When the Debug level is enabled, the
+// request headers and payload are also logged, with the Authorization header
+// and Events API routing keys redacted. If logger is nil, slog.Default() is
+// used.
+func WithLogger(logger *slog.Logger) ClientOptions {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	l := &clientLogger{logger: logger}
+
+	return func(c *Client) {
+		WithMiddleware(l.middleware)(c)
+		WithHooks(Hooks{
+			Retry:         l.retry,
+			RateLimitWait: l.rateLimitWait,
+		})(c)
+	}
+}
+
+type clientLogger struct {
+	logger *slog.Logger
+}
+
+func (l *clientLogger) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+
+		// capture the payload before sending the request, as its body is
+		// consumed by the HTTP client
+		var payload []byte
+		debug := l.logger.Enabled(ctx, slog.LevelDebug)
+		if debug && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				payload, _ = io.ReadAll(body)
+				_ = body.Close()
+			}
+		}
+
+		start := time.Now()
+		resp, err := next(req)
+		elapsed := time.Since(start)
+
+		attempt, _ := AttemptFromContext(ctx)
+		op, _ := OperationFromContext(ctx)
+
+		attrs := []slog.Attr{
+			slog.String("operation", op),
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("attempt", attempt),
+			slog.Duration("duration", elapsed),
+		}
+
+		level := slog.LevelDebug
+
+		if resp != nil {
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.String("request_id", resp.Header.Get("X-Request-Id")),
+			)
+
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				level = slog.LevelWarn
+			}
+		}
+
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+			level = slog.LevelWarn
+		}
+
+		if debug {
+			attrs = append(attrs,
+				slog.Any("headers", logHeaders(req.Header)),
+				slog.Any("payload", logPayload(payload)),
+			)
+		}
+
+		l.logger.LogAttrs(ctx, level, "pagerduty API request", attrs...)
+
+		return resp, err
+	}
+}
+
+func (l *clientLogger) retry(ctx context.Context, op Operation, attempt int, delay time.Duration) {
+	l.logger.LogAttrs(ctx, slog.LevelInfo, "retrying pagerduty API request",
+		slog.String("operation", op.Name),
+		slog.String("method", op.Method),
+		slog.String("path", op.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+	)
+}
+
+func (l *clientLogger) rateLimitWait(ctx context.Context, op Operation, delay time.Duration) {
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "waited for pagerduty rate limiter",
+		slog.String("operation", op.Name),
+		slog.String("method", op.Method),
+		slog.String("path", op.Path),
+		slog.Duration("delay", delay),
+	)
+}
+
+// logHeaders is a slog.LogValuer which logs request headers, with the values
+// of sensitive headers redacted.
+type logHeaders http.Header
+
+func (h logHeaders) LogValue() slog.Value {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attrs := make([]slog.Attr, 0, len(h))
+	for _, name := range names {
+		values := h[name]
+		v := slog.AnyValue(values)
+		if http.CanonicalHeaderKey(name) == "Authorization" {
+			v = slog.StringValue(redacted)
+		} else if len(values) == 1 {
+			v = slog.StringValue(values[0])
+		}
+
+		attrs = append(attrs, slog.Attr{Key: name, Value: v})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// logPayload is a slog.LogValuer which logs request payloads, with the values
+// of sensitive JSON fields redacted. Payloads which aren't valid JSON are
+// logged as-is.
+type logPayload []byte
+
+func (p logPayload) LogValue() slog.Value {
+	if len(p) == 0 {
+		return slog.StringValue("")
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(p, &v); err != nil {
+		return slog.StringValue(string(p))
+	}
+
+	data, err := json.Marshal(redactPayload(v))
+	if err != nil {
+		return slog.StringValue(redacted)
+	}
+
+	return slog.StringValue(string(data))
+}
+
+// redactPayload walks a decoded JSON value, and redacts the values of the
+// fields in redactedPayloadFields.
+func redactPayload(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range vv {
+			if _, ok := redactedPayloadFields[k]; ok {
+				vv[k] = redacted
+				continue
+			}
+
+			vv[k] = redactPayload(fv)
+		}
+
+	case []interface{}:
+		for i, ev := range vv {
+			vv[i] = redactPayload(ev)
+		}
+	}
+
+	return v
+}
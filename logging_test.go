@@ -0,0 +1,155 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type retryOnceStrategy struct{}
+
+func (retryOnceStrategy) Retry(_ *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	return attempt == 0 && (err != nil || resp.StatusCode >= 500), time.Millisecond
+}
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+
+	mux.HandleFunc("/incidents/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		w.Header().Set("X-Request-Id", "req-1")
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"incident": {"id": "1"}}`))
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithRetryStrategy(retryOnceStrategy{}),
+		WithLogger(logger),
+	)
+
+	if _, err := client.GetIncidentWithContext(context.Background(), "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the successful attempt is logged at the Debug level, so is omitted
+	records := decodeLogRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2: %s", len(records), buf.String())
+	}
+
+	attempt, retry := records[0], records[1]
+
+	testEqual(t, "WARN", attempt["level"])
+	testEqual(t, "GetIncidentWithContext", attempt["operation"])
+	testEqual(t, "GET", attempt["method"])
+	testEqual(t, "/incidents/1", attempt["path"])
+	testEqual(t, float64(0), attempt["attempt"])
+	testEqual(t, float64(http.StatusBadGateway), attempt["status"])
+	testEqual(t, "req-1", attempt["request_id"])
+
+	if _, ok := attempt["headers"]; ok {
+		t.Error("headers logged while Debug level is disabled")
+	}
+
+	testEqual(t, "INFO", retry["level"])
+	testEqual(t, "retrying pagerduty API request", retry["msg"])
+	testEqual(t, float64(0), retry["attempt"])
+	testEqual(t, float64(time.Millisecond), retry["delay"])
+}
+
+func TestClient_WithLogger_Redaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "dedup_key": "abc"}`))
+	})
+
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user": {"id": "1"}}`))
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := NewClient("secret-token",
+		WithAPIEndpoint(server.URL),
+		WithV2EventsAPIEndpoint(server.URL),
+		WithLogger(logger),
+	)
+
+	event := &V2Event{
+		RoutingKey: "secret-routing-key",
+		Action:     "trigger",
+		Payload:    &V2Payload{Summary: "disk full", Source: "db1", Severity: "critical"},
+	}
+
+	if _, err := client.ManageEventWithContext(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetUserWithContext(context.Background(), "1", GetUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"secret-token", "secret-routing-key"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains %q: %s", secret, out)
+		}
+	}
+
+	records := decodeLogRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2: %s", len(records), out)
+	}
+
+	testEqual(t, "DEBUG", records[0]["level"])
+	testEqual(t, float64(http.StatusAccepted), records[0]["status"])
+
+	payload, _ := records[0]["payload"].(string)
+	if !strings.Contains(payload, `"routing_key":"REDACTED"`) || !strings.Contains(payload, "disk full") {
+		t.Errorf("payload = %s, want redacted routing key", payload)
+	}
+
+	headers, _ := records[1]["headers"].(map[string]interface{})
+	testEqual(t, "REDACTED", headers["Authorization"])
+	testEqual(t, "application/json", headers["Content-Type"])
+}
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -72,7 +73,7 @@ func (c *Client) ListMaintenanceWindowsWithContext(ctx context.Context, o ListMa
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/maintenance_windows?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListMaintenanceWindowsWithContext", "/maintenance_windows?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +86,14 @@ func (c *Client) ListMaintenanceWindowsWithContext(ctx context.Context, o ListMa
 	return &result, nil
 }
 
+// MaintenanceWindows returns an iterator over all of the maintenance windows
+// matching the options, automatically fetching additional pages as needed.
+func (c *Client) MaintenanceWindows(ctx context.Context, o ListMaintenanceWindowsOptions) iter.Seq2[MaintenanceWindow, error] {
+	return offsetSeq(ctx, c, "MaintenanceWindows", "/maintenance_windows", o, func(r *ListMaintenanceWindowsResponse) ([]MaintenanceWindow, APIListObject) {
+		return r.MaintenanceWindows, r.APIListObject
+	})
+}
+
 // CreateMaintenanceWindow creates a new maintenance window for the specified
 // services.
 //
@@ -108,7 +117,7 @@ func (c *Client) CreateMaintenanceWindowWithContext(ctx context.Context, from st
 		}
 	}
 
-	resp, err := c.post(ctx, "/maintenance_windows", d, h)
+	resp, err := c.post(ctx, "CreateMaintenanceWindowWithContext", "/maintenance_windows", d, h)
 	return getMaintenanceWindowFromResponse(c, resp, err)
 }
 
@@ -130,7 +139,7 @@ func (c *Client) DeleteMaintenanceWindow(id string) error {
 // DeleteMaintenanceWindowWithContext deletes an existing maintenance window if it's in the
 // future, or ends it if it's currently on-going.
 func (c *Client) DeleteMaintenanceWindowWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/maintenance_windows/"+id)
+	_, err := c.delete(ctx, "DeleteMaintenanceWindowWithContext", "/maintenance_windows/"+id)
 	return err
 }
 
@@ -153,7 +162,7 @@ func (c *Client) GetMaintenanceWindowWithContext(ctx context.Context, id string,
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/maintenance_windows/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetMaintenanceWindowWithContext", "/maintenance_windows/"+id+"?"+v.Encode(), nil)
 	return getMaintenanceWindowFromResponse(c, resp, err)
 }
 
@@ -166,7 +175,7 @@ func (c *Client) UpdateMaintenanceWindow(m MaintenanceWindow) (*MaintenanceWindo
 
 // UpdateMaintenanceWindowWithContext updates an existing maintenance window.
 func (c *Client) UpdateMaintenanceWindowWithContext(ctx context.Context, m MaintenanceWindow) (*MaintenanceWindow, error) {
-	resp, err := c.put(ctx, "/maintenance_windows/"+m.ID, m, nil)
+	resp, err := c.put(ctx, "UpdateMaintenanceWindowWithContext", "/maintenance_windows/"+m.ID, m, nil)
 	return getMaintenanceWindowFromResponse(c, resp, err)
 }
 
@@ -0,0 +1,58 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends a single HTTP request to the PagerDuty API, and returns
+// its response.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the RoundTripFunc used by the client to send each HTTP
+// request, allowing consumers to inspect or modify requests and responses
+// (e.g., for logging, auditing, injecting headers, or collecting metrics).
+//
+// The request passed to the middleware is fully prepared, including its
+// authentication headers, and is sent once per attempt when the client retries
+// requests. The name of the client method which issued the request is
+// available using OperationFromContext(req.Context()).
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middleware to the client. Middleware is applied in the
+// order it is added, with the first one being the outermost, and so being the
+// first one to see the request and the last one to see the response.
+func WithMiddleware(middleware ...Middleware) ClientOptions {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// roundTrip sends the request using the HTTPClient, through the middleware.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	rt := RoundTripFunc(c.HTTPClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+
+	return rt(req)
+}
+
+type operationCtxKey struct{}
+
+// OperationFromContext returns the name of the client method, such as
+// "ListIncidentsWithContext", which issued the request with the provided
+// context. The bool is false if the context doesn't come from a request made by
+// the client.
+func OperationFromContext(ctx context.Context) (string, bool) {
+	op, ok := ctx.Value(operationCtxKey{}).(string)
+	return op, ok
+}
+
+// withOperation returns a context annotated with the name of the client method
+// being called. It replaces the annotation of the context, if any, so that a
+// context reused from an earlier request, such as one passed to a hook, isn't
+// reported using the name of the earlier method.
+func withOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationCtxKey{}, op)
+}
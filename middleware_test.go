@@ -0,0 +1,277 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/incidents", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		if got := r.Header.Get("X-Team"); got != "sre" {
+			t.Errorf("X-Team header = %q, want %q", got, "sre")
+		}
+
+		_, _ = w.Write([]byte(`{"incidents": [{"id": "1"}]}`))
+	})
+
+	var mu sync.Mutex
+	var calls []string
+
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				op, _ := OperationFromContext(req.Context())
+
+				mu.Lock()
+				calls = append(calls, name+" "+op+" "+req.Header.Get("Authorization"))
+				mu.Unlock()
+
+				return next(req)
+			}
+		}
+	}
+
+	injectHeader := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Team", "sre")
+			return next(req)
+		}
+	}
+
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithMiddleware(record("first"), record("second")),
+		WithMiddleware(injectHeader),
+	)
+
+	ctx := context.Background()
+
+	if _, err := client.ListIncidentsWithContext(ctx, ListIncidentsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ListIncidents(ListIncidentsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range client.Incidents(ctx, ListIncidentsOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"first ListIncidentsWithContext Token token=foo",
+		"second ListIncidentsWithContext Token token=foo",
+		"first ListIncidentsWithContext Token token=foo",
+		"second ListIncidentsWithContext Token token=foo",
+		"first Incidents Token token=foo",
+		"second Incidents Token token=foo",
+	}
+
+	testEqual(t, want, calls)
+}
+
+func TestClient_MiddlewareRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user": {"id": "1"}}`))
+	})
+
+	var attempts int
+	chaos := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, errors.New("injected failure")
+			}
+
+			return next(req)
+		}
+	}
+
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithRetryStrategy(&FullJitterRetryStrategy{MaxRetries: 1, BaseDelay: time.Millisecond}),
+		WithMiddleware(chaos),
+	)
+
+	user, err := client.GetUserWithContext(context.Background(), "1", GetUserOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.ID != "1" {
+		t.Fatalf("user.ID = %q, want %q", user.ID, "1")
+	}
+
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestOperationFromContext(t *testing.T) {
+	if _, ok := OperationFromContext(context.Background()); ok {
+		t.Fatal("OperationFromContext() ok = true for a context without an operation")
+	}
+
+	ctx := withOperation(context.Background(), "GetUserWithContext")
+
+	op, ok := OperationFromContext(ctx)
+	if !ok || op != "GetUserWithContext" {
+		t.Fatalf("OperationFromContext() = %q, %t; want %q, true", op, ok, "GetUserWithContext")
+	}
+
+	// the operation of each request replaces the annotation
+	op, ok = OperationFromContext(withOperation(ctx, "ListUsersWithContext"))
+	if !ok || op != "ListUsersWithContext" {
+		t.Fatalf("OperationFromContext() = %q, %t; want %q, true", op, ok, "ListUsersWithContext")
+	}
+}
+
+func TestClient_OperationPerRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user": {"id": "1"}}`))
+	})
+
+	mux.HandleFunc("/oncalls", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"oncalls": [{"start": "2024-03-01T10:00:00Z"}], "more": false}`))
+	})
+
+	var mu sync.Mutex
+	var ops []string
+	var reqCtx context.Context
+
+	record := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			op, _ := OperationFromContext(req.Context())
+
+			mu.Lock()
+			ops = append(ops, op)
+			reqCtx = req.Context()
+			mu.Unlock()
+
+			return next(req)
+		}
+	}
+
+	client := defaultTestClient(server.URL, "foo")
+	client.middleware = []Middleware{record}
+
+	if _, err := client.GetUserWithContext(context.Background(), "1", GetUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a context reused from an earlier request reports the current method
+	mu.Lock()
+	ctx := reqCtx
+	mu.Unlock()
+
+	for _, err := range client.OnCalls(ctx, ListOnCallOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.GetUserWithContext(ctx, "1", GetUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	testEqual(t, []string{"GetUserWithContext", "OnCalls", "GetUserWithContext"}, ops)
+}
+
+// TestClient_OperationNames checks that the client methods pass their own name
+// as the operation of their requests, as the names are spelled out by hand.
+func TestClient_OperationNames(t *testing.T) {
+	// the position of the operation in the arguments of the functions making
+	// requests
+	opArg := map[string]int{
+		"get": 1, "post": 1, "put": 1, "delete": 1, "deleteWithHeaders": 1, "do": 1, "doWithEndpoint": 1,
+		"pagedGet": 1, "cursorGet": 1, "cursorGetWithParam": 1, "getAggregatedData": 1, "getAggregatedResponderData": 1,
+		"offsetSeq": 2, "cursorSeq": 2, "getTagList": 2,
+	}
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	var checked int
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, e.Name(), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || !fd.Name.IsExported() {
+				continue
+			}
+
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				var name string
+				switch fn := call.Fun.(type) {
+				case *ast.SelectorExpr:
+					name = fn.Sel.Name
+				case *ast.Ident:
+					name = fn.Name
+				}
+
+				i, ok := opArg[name]
+				if !ok || len(call.Args) <= i {
+					return true
+				}
+
+				lit, ok := call.Args[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+
+				if op, _ := strconv.Unquote(lit.Value); op != fd.Name.Name {
+					t.Errorf("%s: %s makes a request as %q", fset.Position(call.Pos()), fd.Name.Name, op)
+				}
+				checked++
+
+				return true
+			})
+		}
+	}
+
+	if checked == 0 {
+		t.Fatal("no requests were checked")
+	}
+}
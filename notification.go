@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"context"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -69,7 +70,7 @@ func (c *Client) ListNotificationsWithContext(ctx context.Context, o ListNotific
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/notifications?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListNotificationsWithContext", "/notifications?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,3 +82,11 @@ func (c *Client) ListNotificationsWithContext(ctx context.Context, o ListNotific
 
 	return &result, nil
 }
+
+// Notifications returns an iterator over all of the notifications for the
+// given time range, automatically fetching additional pages as needed.
+func (c *Client) Notifications(ctx context.Context, o ListNotificationOptions) iter.Seq2[Notification, error] {
+	return offsetSeq(ctx, c, "Notifications", "/notifications", o, func(r *ListNotificationsResponse) ([]Notification, APIListObject) {
+		return r.Notifications, r.APIListObject
+	})
+}
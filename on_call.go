@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"context"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -12,8 +13,8 @@ type OnCall struct {
 	Schedule         Schedule         `json:"schedule,omitempty"`
 	EscalationPolicy EscalationPolicy `json:"escalation_policy,omitempty"`
 	EscalationLevel  uint             `json:"escalation_level,omitempty"`
-	Start            string           `json:"start,omitempty"`
-	End              string           `json:"end,omitempty"`
+	Start            Time             `json:"start,omitempty"`
+	End              Time             `json:"end,omitempty"`
 }
 
 // ListOnCallsResponse is the data structure returned from calling the ListOnCalls API endpoint.
@@ -67,7 +68,7 @@ func (c *Client) ListOnCallsWithContext(ctx context.Context, o ListOnCallOptions
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/oncalls?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListOnCallsWithContext", "/oncalls?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -79,3 +80,11 @@ func (c *Client) ListOnCallsWithContext(ctx context.Context, o ListOnCallOptions
 
 	return &result, nil
 }
+
+// OnCalls returns an iterator over all of the on-call entries matching the
+// options, automatically fetching additional pages as needed.
+func (c *Client) OnCalls(ctx context.Context, o ListOnCallOptions) iter.Seq2[OnCall, error] {
+	return offsetSeq(ctx, c, "OnCalls", "/oncalls", o, func(r *ListOnCallsResponse) ([]OnCall, APIListObject) {
+		return r.OnCalls, r.APIListObject
+	})
+}
@@ -0,0 +1,307 @@
+// Package otelpagerduty provides OpenTelemetry tracing and metrics
+// instrumentation for the PagerDuty API client.
+//
+// A span is created for each API call, named after the client method that was
+// called (e.g., "ListIncidentsWithContext"), with a child span for each HTTP
+// request attempt made as part of it. The IDs of the resources found in the
+// API path are recorded as span attributes, such as "pagerduty.incidents.id".
+//
+// The following metrics are recorded:
+//
+//   - pagerduty.client.operation.duration: duration of API calls, including retries
+//   - pagerduty.client.request.duration: duration of each HTTP request attempt
+//   - pagerduty.client.requests: number of HTTP request attempts, by status code
+//   - pagerduty.client.retries: number of retried attempts
+//   - pagerduty.client.rate_limit.wait: time spent waiting for the rate limiter
+package otelpagerduty
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter.
+const ScopeName = "github.com/PagerDuty/go-pagerduty/otelpagerduty"
+
+// OperationKey is the attribute key holding the name of the client method.
+const OperationKey = attribute.Key("pagerduty.operation")
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider used to create spans. The global
+// TracerProvider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the MeterProvider used to record metrics. The global
+// MeterProvider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type instrumentation struct {
+	tracer trace.Tracer
+
+	operationDuration metric.Float64Histogram
+	requestDuration   metric.Float64Histogram
+	requests          metric.Int64Counter
+	retries           metric.Int64Counter
+	rateLimitWaitTime metric.Float64Histogram
+}
+
+// Instrument returns a ClientOptions which instruments the *pagerduty.Client
+// with OpenTelemetry tracing and metrics:
+//
+//	client := pagerduty.NewClient(authToken, otelpagerduty.Instrument())
+func Instrument(opts ...Option) pagerduty.ClientOptions {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	inst := newInstrumentation(cfg)
+
+	return func(c *pagerduty.Client) {
+		pagerduty.WithHooks(pagerduty.Hooks{
+			OperationStart: inst.operationStart,
+			OperationEnd:   inst.operationEnd,
+			Retry:          inst.retry,
+			RateLimitWait:  inst.rateLimitWait,
+		})(c)
+
+		pagerduty.WithMiddleware(inst.middleware)(c)
+	}
+}
+
+func newInstrumentation(cfg config) *instrumentation {
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	inst := &instrumentation{
+		tracer: cfg.tracerProvider.Tracer(ScopeName),
+	}
+
+	// errors creating instruments are handled by the global error handler,
+	// and a no-op instrument is returned in their place
+	var err error
+
+	inst.operationDuration, err = meter.Float64Histogram("pagerduty.client.operation.duration",
+		metric.WithDescription("Duration of PagerDuty API calls, including retries."),
+		metric.WithUnit("s"),
+	)
+	handleErr(err)
+
+	inst.requestDuration, err = meter.Float64Histogram("pagerduty.client.request.duration",
+		metric.WithDescription("Duration of each HTTP request attempt made to the PagerDuty API."),
+		metric.WithUnit("s"),
+	)
+	handleErr(err)
+
+	inst.requests, err = meter.Int64Counter("pagerduty.client.requests",
+		metric.WithDescription("Number of HTTP request attempts made to the PagerDuty API."),
+		metric.WithUnit("{request}"),
+	)
+	handleErr(err)
+
+	inst.retries, err = meter.Int64Counter("pagerduty.client.retries",
+		metric.WithDescription("Number of PagerDuty API request attempts which were retried."),
+		metric.WithUnit("{retry}"),
+	)
+	handleErr(err)
+
+	inst.rateLimitWaitTime, err = meter.Float64Histogram("pagerduty.client.rate_limit.wait",
+		metric.WithDescription("Time spent waiting for the client-side rate limiter."),
+		metric.WithUnit("s"),
+	)
+	handleErr(err)
+
+	return inst
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+type startTimeCtxKey struct{}
+
+func (i *instrumentation) operationStart(ctx context.Context, op pagerduty.Operation) context.Context {
+	attrs := append(operationAttributes(op), semconv.HTTPRequestMethodKey.String(op.Method))
+	attrs = append(attrs, resourceAttributes(op.Path)...)
+
+	ctx, _ = i.tracer.Start(ctx, spanName(op),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+
+	return context.WithValue(ctx, startTimeCtxKey{}, time.Now())
+}
+
+func (i *instrumentation) operationEnd(ctx context.Context, op pagerduty.Operation, resp *http.Response, err error) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	attrs := operationAttributes(op)
+	if resp != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	}
+
+	if err != nil {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(errorType(resp, err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if start, ok := ctx.Value(startTimeCtxKey{}).(time.Time); ok {
+		i.operationDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+func (i *instrumentation) retry(ctx context.Context, op pagerduty.Operation, attempt int, delay time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("pagerduty.retry.attempt", attempt),
+		attribute.Float64("pagerduty.retry.delay", delay.Seconds()),
+	))
+
+	i.retries.Add(ctx, 1, metric.WithAttributes(operationAttributes(op)...))
+}
+
+func (i *instrumentation) rateLimitWait(ctx context.Context, op pagerduty.Operation, delay time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("rate_limit.wait", trace.WithAttributes(
+		attribute.Float64("pagerduty.rate_limit.wait", delay.Seconds()),
+	))
+
+	i.rateLimitWaitTime.Record(ctx, delay.Seconds(), metric.WithAttributes(operationAttributes(op)...))
+}
+
+// middleware creates a span for each HTTP request attempt.
+func (i *instrumentation) middleware(next pagerduty.RoundTripFunc) pagerduty.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		op, _ := pagerduty.OperationFromContext(req.Context())
+
+		attrs := []attribute.KeyValue{
+			OperationKey.String(op),
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+		}
+
+		spanAttrs := append(attrs, semconv.URLFull(req.URL.Redacted()))
+		if attempt, _ := pagerduty.AttemptFromContext(req.Context()); attempt > 0 {
+			spanAttrs = append(spanAttrs, semconv.HTTPRequestResendCount(attempt))
+		}
+
+		ctx, span := i.tracer.Start(req.Context(), req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(spanAttrs...),
+		)
+		defer span.End()
+
+		start := time.Now()
+		resp, err := next(req.WithContext(ctx))
+		elapsed := time.Since(start)
+
+		if resp != nil {
+			attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		if err != nil || resp.StatusCode >= 400 {
+			attrs = append(attrs, semconv.ErrorTypeKey.String(errorType(resp, err)))
+		}
+
+		i.requestDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+		i.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+		return resp, err
+	}
+}
+
+func spanName(op pagerduty.Operation) string {
+	if op.Name != "" {
+		return op.Name
+	}
+
+	return "pagerduty " + op.Method
+}
+
+func operationAttributes(op pagerduty.Operation) []attribute.KeyValue {
+	return []attribute.KeyValue{OperationKey.String(op.Name)}
+}
+
+// errorType returns the value of the error.type attribute, which is the status
+// code for error responses, or a generic type otherwise.
+func errorType(resp *http.Response, err error) string {
+	if resp != nil && resp.StatusCode >= 400 {
+		return http.StatusText(resp.StatusCode)
+	}
+
+	if err != nil {
+		return "error"
+	}
+
+	return ""
+}
+
+// resourceAttributes returns the IDs of the resources found in the API path as
+// attributes, keyed by the collection they belong to. For example, the path
+// "/incidents/PABC123/notes" results in pagerduty.incidents.id=PABC123.
+//
+// Path segments are considered to be IDs when they contain an uppercase letter
+// or a digit, as opposed to collection names which are lowercase words.
+func resourceAttributes(path string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if !isResourceID(segments[i]) || isResourceID(segments[i-1]) {
+			continue
+		}
+
+		key := attribute.Key("pagerduty." + segments[i-1] + ".id")
+		attrs = append(attrs, key.String(segments[i]))
+	}
+
+	return attrs
+}
+
+func isResourceID(segment string) bool {
+	return strings.IndexFunc(segment, func(r rune) bool {
+		return unicode.IsUpper(r) || unicode.IsDigit(r)
+	}) >= 0
+}
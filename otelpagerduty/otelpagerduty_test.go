@@ -0,0 +1,272 @@
+package otelpagerduty
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testRetryStrategy struct{ max int }
+
+func (s testRetryStrategy) Retry(_ *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= s.max {
+		return false, 0
+	}
+
+	return err != nil || resp.StatusCode >= 500, time.Millisecond
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*pagerduty.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := pagerduty.NewClient("foo",
+		pagerduty.WithAPIEndpoint(server.URL),
+		pagerduty.WithV2EventsAPIEndpoint(server.URL),
+		pagerduty.WithRetryStrategy(testRetryStrategy{max: 2}),
+		Instrument(WithTracerProvider(tp), WithMeterProvider(mp)),
+	)
+
+	return client, sr, reader
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestInstrument_Spans(t *testing.T) {
+	var calls int32
+
+	client, sr, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/incidents/PABC123/notes" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"notes": [{"id": "1", "content": "foo"}]}`))
+	})
+
+	if _, err := client.ListIncidentNotesWithContext(context.Background(), "PABC123"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("len(spans) = %d, want 3", len(spans))
+	}
+
+	// the attempt spans end before the operation span
+	first, second, op := spans[0], spans[1], spans[2]
+
+	if got, want := op.Name(), "ListIncidentNotesWithContext"; got != want {
+		t.Errorf("op.Name() = %q, want %q", got, want)
+	}
+
+	if got := op.SpanKind(); got != trace.SpanKindInternal {
+		t.Errorf("op.SpanKind() = %v, want %v", got, trace.SpanKindInternal)
+	}
+
+	if v, _ := spanAttr(op, "pagerduty.incidents.id"); v.AsString() != "PABC123" {
+		t.Errorf("pagerduty.incidents.id = %q, want %q", v.AsString(), "PABC123")
+	}
+
+	if v, _ := spanAttr(op, "http.response.status_code"); v.AsInt64() != 200 {
+		t.Errorf("op http.response.status_code = %d, want 200", v.AsInt64())
+	}
+
+	if got := op.Status().Code; got != codes.Unset {
+		t.Errorf("op.Status().Code = %v, want %v", got, codes.Unset)
+	}
+
+	if len(op.Events()) != 1 || op.Events()[0].Name != "retry" {
+		t.Errorf("op.Events() = %v, want a single retry event", op.Events())
+	}
+
+	for i, s := range []sdktrace.ReadOnlySpan{first, second} {
+		if s.Parent().SpanID() != op.SpanContext().SpanID() {
+			t.Errorf("attempt %d is not a child of the operation span", i)
+		}
+
+		if got := s.SpanKind(); got != trace.SpanKindClient {
+			t.Errorf("attempt %d SpanKind() = %v, want %v", i, got, trace.SpanKindClient)
+		}
+
+		if v, _ := spanAttr(s, OperationKey); v.AsString() != "ListIncidentNotesWithContext" {
+			t.Errorf("attempt %d %s = %q", i, OperationKey, v.AsString())
+		}
+	}
+
+	if got := first.Status().Code; got != codes.Error {
+		t.Errorf("first.Status().Code = %v, want %v", got, codes.Error)
+	}
+
+	if _, ok := spanAttr(first, "http.request.resend_count"); ok {
+		t.Error("first attempt has http.request.resend_count attribute")
+	}
+
+	if v, _ := spanAttr(second, "http.request.resend_count"); v.AsInt64() != 1 {
+		t.Errorf("second http.request.resend_count = %d, want 1", v.AsInt64())
+	}
+}
+
+func TestInstrument_SpanError(t *testing.T) {
+	client, sr, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": {"code": 2100, "message": "Not Found"}}`))
+	})
+
+	if _, err := client.GetServiceWithContext(context.Background(), "PXYZ789", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+
+	op := spans[1]
+
+	if got := op.Status().Code; got != codes.Error {
+		t.Errorf("op.Status().Code = %v, want %v", got, codes.Error)
+	}
+
+	if v, _ := spanAttr(op, "pagerduty.services.id"); v.AsString() != "PXYZ789" {
+		t.Errorf("pagerduty.services.id = %q, want %q", v.AsString(), "PXYZ789")
+	}
+
+	if v, _ := spanAttr(op, "http.response.status_code"); v.AsInt64() != 404 {
+		t.Errorf("op http.response.status_code = %d, want 404", v.AsInt64())
+	}
+}
+
+func TestInstrument_Metrics(t *testing.T) {
+	var calls int32
+
+	client, _, reader := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"incidents": []}`))
+	})
+
+	if _, err := client.ListIncidentsWithContext(context.Background(), pagerduty.ListIncidentsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	requests, ok := metrics["pagerduty.client.requests"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("pagerduty.client.requests not recorded: %v", metrics)
+	}
+
+	byStatus := make(map[int64]int64)
+	for _, dp := range requests.DataPoints {
+		status, _ := dp.Attributes.Value("http.response.status_code")
+		byStatus[status.AsInt64()] += dp.Value
+
+		if op, _ := dp.Attributes.Value(OperationKey); op.AsString() != "ListIncidentsWithContext" {
+			t.Errorf("%s = %q, want %q", OperationKey, op.AsString(), "ListIncidentsWithContext")
+		}
+	}
+
+	if want := map[int64]int64{200: 1, 503: 1}; !reflect.DeepEqual(byStatus, want) {
+		t.Errorf("requests by status = %v, want %v", byStatus, want)
+	}
+
+	retries, ok := metrics["pagerduty.client.retries"].(metricdata.Sum[int64])
+	if !ok || len(retries.DataPoints) != 1 || retries.DataPoints[0].Value != 1 {
+		t.Errorf("pagerduty.client.retries = %v, want 1", metrics["pagerduty.client.retries"])
+	}
+
+	for _, name := range []string{"pagerduty.client.request.duration", "pagerduty.client.operation.duration"} {
+		h, ok := metrics[name].(metricdata.Histogram[float64])
+		if !ok {
+			t.Errorf("%s not recorded", name)
+			continue
+		}
+
+		var count uint64
+		for _, dp := range h.DataPoints {
+			count += dp.Count
+		}
+
+		want := uint64(2)
+		if name == "pagerduty.client.operation.duration" {
+			want = 1
+		}
+
+		if count != want {
+			t.Errorf("%s count = %d, want %d", name, count, want)
+		}
+	}
+}
+
+func TestResourceAttributes(t *testing.T) {
+	tests := []struct {
+		path string
+		want []attribute.KeyValue
+	}{
+		{path: "/incidents", want: nil},
+		{path: "/incidents/PABC123", want: []attribute.KeyValue{attribute.String("pagerduty.incidents.id", "PABC123")}},
+		{
+			path: "/teams/PT1/escalation_policies/PEP2",
+			want: []attribute.KeyValue{
+				attribute.String("pagerduty.teams.id", "PT1"),
+				attribute.String("pagerduty.escalation_policies.id", "PEP2"),
+			},
+		},
+		{path: "/incidents/PABC123/notes", want: []attribute.KeyValue{attribute.String("pagerduty.incidents.id", "PABC123")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := resourceAttributes(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resourceAttributes(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
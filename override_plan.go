@@ -0,0 +1,285 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OverrideStrategy is how PlanOverrides picks the users replacing a user.
+type OverrideStrategy string
+
+// The supported override strategies.
+const (
+	// OverrideStrategySubstitute replaces the user with a named substitute
+	// for all shifts.
+	OverrideStrategySubstitute OverrideStrategy = "substitute"
+
+	// OverrideStrategyNextInRotation replaces the user with the user
+	// following them in the rotation of the layer of each shift.
+	OverrideStrategyNextInRotation OverrideStrategy = "next_in_rotation"
+
+	// OverrideStrategyRoundRobin replaces the user with the other users of
+	// the layer of each shift in turn, so that the shifts are spread evenly
+	// among them.
+	OverrideStrategyRoundRobin OverrideStrategy = "round_robin"
+)
+
+// PlanOverridesOptions is the data structure used when calling PlanOverrides.
+type PlanOverridesOptions struct {
+	// UserID is the ID of the user to replace, such as when going on
+	// vacation.
+	UserID string
+
+	// Since and Until are the start and end of the time window during which
+	// the user is replaced.
+	Since time.Time
+	Until time.Time
+
+	Strategy OverrideStrategy
+
+	// Substitute is the user replacing the user, when using
+	// OverrideStrategySubstitute.
+	Substitute APIObject
+}
+
+// PlannedOverride is an override to create in a schedule, so that its user
+// replaces ReplacedUser.
+type PlannedOverride struct {
+	Schedule     APIObject
+	Override     Override
+	ReplacedUser APIObject
+}
+
+// PlanOverrides returns the overrides replacing the user in the schedules
+// during the time window, using the rendered entries of their layers and
+// final schedule, such as returned by GetScheduleWithContext or
+// RenderSchedule. The overrides are in the order of the schedules, and in
+// chronological order for each schedule, and consecutive shifts with the same
+// replacement are covered by a single override.
+//
+// The rotation strategies use the layer with the highest precedence that
+// schedules the user for each part of their shifts, and return an error if
+// the user is only on call due to an existing override, or if the layer has
+// no other users.
+func PlanOverrides(schedules []Schedule, o PlanOverridesOptions) ([]PlannedOverride, error) {
+	if o.UserID == "" {
+		return nil, errors.New("the user to replace must be set")
+	}
+
+	if o.Since.IsZero() || o.Until.IsZero() || !o.Since.Before(o.Until) {
+		return nil, errors.New("the time window to replace the user during must have a start before its end")
+	}
+
+	switch o.Strategy {
+	case OverrideStrategySubstitute:
+		if o.Substitute.ID == "" || o.Substitute.ID == o.UserID {
+			return nil, errors.New("the substitute must be set, and differ from the user to replace")
+		}
+	case OverrideStrategyNextInRotation, OverrideStrategyRoundRobin:
+	default:
+		return nil, fmt.Errorf("unknown override strategy %q", o.Strategy)
+	}
+
+	var planned []PlannedOverride
+	for _, s := range schedules {
+		spans, replaced, err := planScheduleOverrides(s, o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan overrides of schedule %s: %w", s.ID, err)
+		}
+
+		for _, span := range mergeSpans(spans) {
+			planned = append(planned, PlannedOverride{
+				Schedule: scheduleReference(s),
+				Override: Override{
+					Start: NewTime(span.start).String(),
+					End:   NewTime(span.end).String(),
+					User:  userReference(span.user),
+				},
+				ReplacedUser: replaced,
+			})
+		}
+	}
+
+	return planned, nil
+}
+
+// planScheduleOverrides returns the spans during which the replacements of the
+// user are on call for the schedule, in chronological order, and the reference
+// of the user as rendered by the schedule.
+func planScheduleOverrides(s Schedule, o PlanOverridesOptions) ([]scheduleSpan, APIObject, error) {
+	final, err := entrySpans(s.FinalSchedule.RenderedScheduleEntries, o.Since, o.Until)
+	if err != nil {
+		return nil, APIObject{}, fmt.Errorf("invalid final schedule: %w", err)
+	}
+
+	replaced := APIObject{ID: o.UserID}
+
+	var shifts []scheduleSpan
+	for _, span := range final {
+		if span.user.ID == o.UserID {
+			shifts = append(shifts, span)
+			replaced = span.user
+		}
+	}
+
+	if len(shifts) == 0 {
+		return nil, replaced, nil
+	}
+
+	if o.Strategy == OverrideStrategySubstitute {
+		for i := range shifts {
+			shifts[i].user = o.Substitute
+		}
+
+		return shifts, replaced, nil
+	}
+
+	// the parts of the shifts scheduled by each layer, from the highest
+	// precedence to the lowest
+	var spans []scheduleSpan
+	remaining := shifts
+	for i := len(s.ScheduleLayers) - 1; i >= 0 && len(remaining) > 0; i-- {
+		l := s.ScheduleLayers[i]
+
+		layerSpans, err := entrySpans(l.RenderedScheduleEntries, o.Since, o.Until)
+		if err != nil {
+			return nil, replaced, fmt.Errorf("invalid layer %s: %w", l.ID, err)
+		}
+
+		var scheduled []scheduleSpan
+		for _, span := range layerSpans {
+			if span.user.ID == o.UserID {
+				scheduled = append(scheduled, span)
+			}
+		}
+
+		parts := intersectSpans(remaining, scheduled)
+		if len(parts) == 0 {
+			continue
+		}
+
+		candidates, err := layerReplacements(l, o)
+		if err != nil {
+			return nil, replaced, err
+		}
+
+		for k := range parts {
+			parts[k].user = candidates[k%len(candidates)]
+		}
+
+		spans = append(spans, parts...)
+
+		var next []scheduleSpan
+		for _, r := range remaining {
+			next = append(next, subtractSpans(r, parts)...)
+		}
+
+		remaining = next
+	}
+
+	if len(remaining) > 0 {
+		return nil, replaced, fmt.Errorf("no layer schedules user %s from %s to %s",
+			o.UserID, remaining[0].start.Format(time.RFC3339), remaining[0].end.Format(time.RFC3339))
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	return spans, replaced, nil
+}
+
+// layerReplacements returns the users replacing the user in turn for the
+// shifts of the layer: the user following them in the rotation, or all the
+// other users of the layer in the order of the rotation.
+func layerReplacements(l ScheduleLayer, o PlanOverridesOptions) ([]APIObject, error) {
+	var others []APIObject
+	seen := make(map[string]bool)
+	position := -1
+	for _, u := range l.Users {
+		if u.User.ID == o.UserID {
+			if position < 0 {
+				position = len(others)
+			}
+
+			continue
+		}
+
+		if seen[u.User.ID] {
+			continue
+		}
+
+		seen[u.User.ID] = true
+		others = append(others, u.User)
+	}
+
+	if len(others) == 0 {
+		return nil, fmt.Errorf("layer %s has no other users to replace user %s", l.ID, o.UserID)
+	}
+
+	if o.Strategy == OverrideStrategyNextInRotation {
+		if position < 0 {
+			position = 0
+		}
+
+		return []APIObject{others[position%len(others)]}, nil
+	}
+
+	return others, nil
+}
+
+func userReference(u APIObject) APIObject {
+	return APIObject{ID: u.ID, Type: "user_reference", Summary: u.Summary}
+}
+
+// PlanOverridesWithContext gets the schedules, rendered during the time window,
+// and plans the overrides replacing the user in them using PlanOverrides.
+func (c *Client) PlanOverridesWithContext(ctx context.Context, scheduleIDs []string, o PlanOverridesOptions) ([]PlannedOverride, error) {
+	schedules := make([]Schedule, 0, len(scheduleIDs))
+	for _, id := range scheduleIDs {
+		s, err := c.GetScheduleWithContext(ctx, id, GetScheduleOptions{Since: NewTime(o.Since).String(), Until: NewTime(o.Until).String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schedule %s: %w", id, err)
+		}
+
+		schedules = append(schedules, *s)
+	}
+
+	return PlanOverrides(schedules, o)
+}
+
+// ApplyOverridesWithContext creates the planned overrides, and returns them
+// with the overrides as created by the API. If creating an override fails, the
+// overrides already created are deleted, so that either all or none of the
+// planned overrides are applied, and the returned error includes any failure
+// to delete them.
+func (c *Client) ApplyOverridesWithContext(ctx context.Context, planned []PlannedOverride) ([]PlannedOverride, error) {
+	created := make([]PlannedOverride, 0, len(planned))
+	for _, p := range planned {
+		o, err := c.CreateOverrideWithContext(ctx, p.Schedule.ID, p.Override)
+		if err != nil {
+			err = fmt.Errorf("failed to create override in schedule %s: %w", p.Schedule.ID, err)
+			return nil, errors.Join(err, c.deleteOverrides(context.WithoutCancel(ctx), created))
+		}
+
+		p.Override = *o
+		created = append(created, p)
+	}
+
+	return created, nil
+}
+
+// deleteOverrides deletes the created overrides, in the reverse order of their
+// creation.
+func (c *Client) deleteOverrides(ctx context.Context, created []PlannedOverride) error {
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		p := created[i]
+		if err := c.DeleteOverrideWithContext(ctx, p.Schedule.ID, p.Override.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back override %s of schedule %s: %w", p.Override.ID, p.Schedule.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
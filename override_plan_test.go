@@ -0,0 +1,179 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func plannedUsers(planned []PlannedOverride) []string {
+	var users []string
+	for _, p := range planned {
+		users = append(users, string(p.Override.Start)+" "+string(p.Override.End)+" "+p.Override.User.ID)
+	}
+
+	return users
+}
+
+func TestPlanOverrides(t *testing.T) {
+	s := Schedule{
+		APIObject: APIObject{ID: "PS1"},
+		Name:      "Primary",
+		ScheduleLayers: []ScheduleLayer{{
+			Start:                     "2026-01-01T00:00:00Z",
+			RotationVirtualStart:      "2026-01-01T00:00:00Z",
+			RotationTurnLengthSeconds: 86400,
+			Users:                     testUsers("PA", "PB", "PC"),
+		}},
+	}
+
+	since, until := testTime(t, "2026-01-01T00:00:00Z"), testTime(t, "2026-01-08T00:00:00Z")
+
+	rendered, err := RenderSchedule(s, RenderScheduleOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PA is on call on the 1st, 4th, and 7th
+	tests := []struct {
+		strategy   OverrideStrategy
+		substitute APIObject
+		want       []string
+	}{
+		{
+			strategy:   OverrideStrategySubstitute,
+			substitute: APIObject{ID: "PX"},
+			want: []string{
+				"2026-01-01T00:00:00Z 2026-01-02T00:00:00Z PX",
+				"2026-01-04T00:00:00Z 2026-01-05T00:00:00Z PX",
+				"2026-01-07T00:00:00Z 2026-01-08T00:00:00Z PX",
+			},
+		},
+		{
+			strategy: OverrideStrategyNextInRotation,
+			want: []string{
+				"2026-01-01T00:00:00Z 2026-01-02T00:00:00Z PB",
+				"2026-01-04T00:00:00Z 2026-01-05T00:00:00Z PB",
+				"2026-01-07T00:00:00Z 2026-01-08T00:00:00Z PB",
+			},
+		},
+		{
+			strategy: OverrideStrategyRoundRobin,
+			want: []string{
+				"2026-01-01T00:00:00Z 2026-01-02T00:00:00Z PB",
+				"2026-01-04T00:00:00Z 2026-01-05T00:00:00Z PC",
+				"2026-01-07T00:00:00Z 2026-01-08T00:00:00Z PB",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			planned, err := PlanOverrides([]Schedule{*rendered}, PlanOverridesOptions{
+				UserID:     "PA",
+				Since:      since,
+				Until:      until,
+				Strategy:   tt.strategy,
+				Substitute: tt.substitute,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testEqual(t, tt.want, plannedUsers(planned))
+			testEqual(t, APIObject{ID: "PS1", Summary: "Primary"}, planned[0].Schedule)
+			testEqual(t, "PA", planned[0].ReplacedUser.ID)
+			testEqual(t, "user_reference", planned[0].Override.User.Type)
+		})
+	}
+}
+
+func TestPlanOverrides_errors(t *testing.T) {
+	s := Schedule{
+		APIObject: APIObject{ID: "PS1"},
+		ScheduleLayers: []ScheduleLayer{{
+			APIObject:                 APIObject{ID: "PL1"},
+			Start:                     "2026-01-01T00:00:00Z",
+			RotationTurnLengthSeconds: 86400,
+			Users:                     testUsers("PA"),
+		}},
+	}
+
+	since, until := testTime(t, "2026-01-01T00:00:00Z"), testTime(t, "2026-01-02T00:00:00Z")
+
+	rendered, err := RenderSchedule(s, RenderScheduleOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := PlanOverridesOptions{UserID: "PA", Since: since, Until: until, Strategy: OverrideStrategyRoundRobin}
+	_, err = PlanOverrides([]Schedule{*rendered}, o)
+	testErrCheck(t, "PlanOverrides()", "failed to plan overrides of schedule PS1: layer PL1 has no other users to replace user PA", err)
+
+	o.Strategy = OverrideStrategySubstitute
+	o.Substitute = APIObject{ID: "PA"}
+	_, err = PlanOverrides([]Schedule{*rendered}, o)
+	testErrCheck(t, "PlanOverrides()", "the substitute must be set, and differ from the user to replace", err)
+
+	o.Strategy = "random"
+	_, err = PlanOverrides([]Schedule{*rendered}, o)
+	testErrCheck(t, "PlanOverrides()", `unknown override strategy "random"`, err)
+}
+
+func TestClient_ApplyOverridesWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var created, deleted []string
+	mux.HandleFunc("/schedules/PS1/overrides", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		var body map[string]Override
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		if body["override"].User.ID == "PFAIL" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": 2001, "message": "Invalid Input Provided"}}`))
+			return
+		}
+
+		id := "PO" + body["override"].User.ID
+		created = append(created, id)
+		_, _ = w.Write([]byte(`{"override": {"id": "` + id + `", "user": {"id": "` + body["override"].User.ID + `"}}}`))
+	})
+
+	mux.HandleFunc("/schedules/PS1/overrides/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		deleted = append(deleted, r.URL.Path[len("/schedules/PS1/overrides/"):])
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	planned := []PlannedOverride{
+		{Schedule: APIObject{ID: "PS1"}, Override: Override{User: APIObject{ID: "PU1"}}},
+		{Schedule: APIObject{ID: "PS1"}, Override: Override{User: APIObject{ID: "PU2"}}},
+	}
+
+	applied, err := client.ApplyOverridesWithContext(context.Background(), planned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, "POPU1", applied[0].Override.ID)
+	testEqual(t, "POPU2", applied[1].Override.ID)
+
+	// the overrides created before the failure are rolled back, latest first
+	created = nil
+	planned = append(planned, PlannedOverride{Schedule: APIObject{ID: "PS1"}, Override: Override{User: APIObject{ID: "PFAIL"}}})
+
+	if _, err := client.ApplyOverridesWithContext(context.Background(), planned); err == nil {
+		t.Fatal("ApplyOverridesWithContext() error = nil, want an error")
+	}
+
+	testEqual(t, []string{"POPU1", "POPU2"}, created)
+	testEqual(t, []string{"POPU2", "POPU1"}, deleted)
+}
@@ -0,0 +1,505 @@
+package pagerdutytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+const (
+	eventsPath       = "/v2/enqueue"
+	changeEventsPath = "/v2/change/enqueue"
+	legacyEventsPath = "/generic/2010-04-15/create_event.json"
+
+	routingKeyLength  = 32
+	maxDedupKeyLength = 255
+	maxSummaryLength  = 1024
+	maxEventSize      = 512 * 1024
+)
+
+// isEventsPath returns whether the path is an Events API endpoint, which
+// doesn't require authentication and isn't subject to the REST API rate limit.
+func isEventsPath(path string) bool {
+	return path == eventsPath || path == changeEventsPath || path == legacyEventsPath
+}
+
+var validSeverities = []pagerduty.V2Severity{
+	pagerduty.V2SeverityCritical,
+	pagerduty.V2SeverityError,
+	pagerduty.V2SeverityWarning,
+	pagerduty.V2SeverityInfo,
+}
+
+// EventsReceiver is a fake of the PagerDuty Events API, for testing code
+// sending Events API V2 events, change events, and legacy Events API V1 events.
+// It validates events like the Events API does, responds with the same bodies,
+// and records the events it accepts. Faults, such as 429 and 5xx responses,
+// can be injected to test how code handles them.
+//
+// EventsReceiver is an http.Handler, so it can be served on its own, or
+// embedded in another server:
+//
+//	receiver := pagerdutytest.NewEventsReceiver()
+//	server := httptest.NewServer(receiver)
+//	defer server.Close()
+//
+//	events := pagerduty.NewEventsClient(routingKey, pagerduty.WithV2EventsAPIEndpoint(server.URL))
+//
+// Unlike Server, it doesn't keep track of incidents. It's safe for concurrent
+// use.
+type EventsReceiver struct {
+	mux *http.ServeMux
+
+	// checkRoutingKeys enables checking the length of routing keys, which the
+	// Server disables to accept the integration keys it's seeded with.
+	checkRoutingKeys bool
+
+	// onEvent is called with each accepted event, other than change events.
+	onEvent func(e receivedEvent)
+
+	mu           sync.Mutex
+	events       []pagerduty.V2Event
+	changeEvents []pagerduty.ChangeEvent
+	legacyEvents []pagerduty.Event
+	faults       []*Fault
+}
+
+// receivedEvent is the common part of Events API V2 and V1 events.
+type receivedEvent struct {
+	routingKey string
+	action     pagerduty.V2Action
+	dedupKey   string
+	summary    string
+}
+
+// NewEventsReceiver returns a new EventsReceiver.
+func NewEventsReceiver() *EventsReceiver {
+	r := &EventsReceiver{checkRoutingKeys: true}
+
+	r.mux = http.NewServeMux()
+	r.mux.HandleFunc("POST "+eventsPath, r.enqueueEvent)
+	r.mux.HandleFunc("POST "+changeEventsPath, r.enqueueChangeEvent)
+	r.mux.HandleFunc("POST "+legacyEventsPath, r.createEvent)
+	r.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeEventsError(w, http.StatusNotFound, "")
+	})
+
+	return r
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (r *EventsReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if f := r.fault(req); f != nil {
+		writeEventsFault(w, f)
+		return
+	}
+
+	r.mux.ServeHTTP(w, req)
+}
+
+// InjectFault makes the receiver return an error response for the requests
+// matching f, such as a 429 response with a Retry-After header. The Code of
+// the fault is ignored, as the Events API doesn't return error codes. When
+// multiple faults match a request, the first one injected is returned.
+func (r *EventsReceiver) InjectFault(f Fault) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.faults = append(r.faults, &f)
+}
+
+// ClearFaults removes all the faults injected into the receiver.
+func (r *EventsReceiver) ClearFaults() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.faults = nil
+}
+
+func (r *EventsReceiver) fault(req *http.Request) *Fault {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return matchFault(&r.faults, req)
+}
+
+// V2Events returns the Events API V2 events accepted by the receiver. The
+// dedup keys the receiver generated for events without one are included.
+func (r *EventsReceiver) V2Events() []pagerduty.V2Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+// ChangeEvents returns the change events accepted by the receiver.
+func (r *EventsReceiver) ChangeEvents() []pagerduty.ChangeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.changeEvents)
+}
+
+// Events returns the legacy Events API V1 events accepted by the receiver.
+// The incident keys the receiver generated for events without one are
+// included.
+func (r *EventsReceiver) Events() []pagerduty.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.legacyEvents)
+}
+
+// Reset forgets the events accepted by the receiver.
+func (r *EventsReceiver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+	r.changeEvents = nil
+	r.legacyEvents = nil
+}
+
+func (r *EventsReceiver) enqueueEvent(w http.ResponseWriter, req *http.Request) {
+	var e pagerduty.V2Event
+	size, err := decodeEvent(req, &e)
+	if err != nil {
+		writeInvalidEvent(w, "Malformed JSON")
+		return
+	}
+
+	if errs := r.validateEvent(e, size); len(errs) > 0 {
+		writeInvalidEvent(w, errs...)
+		return
+	}
+
+	if e.DedupKey == "" {
+		e.DedupKey = newDedupKey()
+	}
+
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+
+	re := receivedEvent{routingKey: e.RoutingKey, action: e.Action, dedupKey: e.DedupKey}
+	if e.Payload != nil {
+		re.summary = e.Payload.Summary
+	}
+
+	r.received(re)
+
+	writeJSON(w, http.StatusAccepted, pagerduty.V2EventResponse{
+		Status:   "success",
+		Message:  "Event processed",
+		DedupKey: e.DedupKey,
+	})
+}
+
+// decodeEvent decodes the JSON event of the request body into v, returning the
+// size of the body.
+func decodeEvent(req *http.Request, v interface{}) (int, error) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), json.Unmarshal(data, v)
+}
+
+// validateSize returns the error for events over the size limit of the Events
+// API.
+func validateSize(size int) []string {
+	if size > maxEventSize {
+		return []string{fmt.Sprintf("Event object is too large (maximum is %d bytes)", maxEventSize)}
+	}
+
+	return nil
+}
+
+// validateSummary returns the errors of the summary of an event.
+func validateSummary(summary string) []string {
+	switch {
+	case summary == "":
+		return []string{"'payload.summary' is missing or blank"}
+
+	case utf8.RuneCountInString(summary) > maxSummaryLength:
+		return []string{fmt.Sprintf("'payload.summary' is too long (maximum is %d characters)", maxSummaryLength)}
+
+	default:
+		return nil
+	}
+}
+
+func (r *EventsReceiver) validateEvent(e pagerduty.V2Event, size int) []string {
+	errs := r.validateRoutingKey("routing_key", e.RoutingKey)
+	errs = append(errs, validateSize(size)...)
+
+	if utf8.RuneCountInString(e.DedupKey) > maxDedupKeyLength {
+		errs = append(errs, fmt.Sprintf("'dedup_key' is too long (maximum is %d characters)", maxDedupKeyLength))
+	}
+
+	switch e.Action {
+	case pagerduty.V2ActionTrigger:
+		if e.Payload == nil {
+			errs = append(errs, "'payload' is missing or blank")
+			break
+		}
+
+		errs = append(errs, validateSummary(e.Payload.Summary)...)
+
+		if e.Payload.Source == "" {
+			errs = append(errs, "'payload.source' is missing or blank")
+		}
+
+		if !slices.Contains(validSeverities, e.Payload.Severity) {
+			errs = append(errs, "'payload.severity' is invalid (must be one of the following: 'critical', 'warning', 'error' or 'info')")
+		}
+
+	case pagerduty.V2ActionAcknowledge, pagerduty.V2ActionResolve:
+		if e.DedupKey == "" {
+			errs = append(errs, "'dedup_key' is missing or blank")
+		}
+
+	case "":
+		errs = append(errs, "'event_action' is missing or blank")
+
+	default:
+		errs = append(errs, "'event_action' is invalid (must be one of the following: 'trigger', 'acknowledge' or 'resolve')")
+	}
+
+	for i, image := range e.Images {
+		if image.Src == "" {
+			errs = append(errs, fmt.Sprintf("'images[%d].src' is missing or blank", i))
+		}
+	}
+
+	for i, link := range e.Links {
+		if link.Href == "" {
+			errs = append(errs, fmt.Sprintf("'links[%d].href' is missing or blank", i))
+		}
+	}
+
+	return errs
+}
+
+func (r *EventsReceiver) validateRoutingKey(field, key string) []string {
+	switch {
+	case key == "":
+		return []string{fmt.Sprintf("'%s' is missing or blank", field)}
+
+	case r.checkRoutingKeys && len(key) != routingKeyLength:
+		return []string{fmt.Sprintf("Length of '%s' is incorrect (should be %d characters)", field, routingKeyLength)}
+
+	default:
+		return nil
+	}
+}
+
+func (r *EventsReceiver) enqueueChangeEvent(w http.ResponseWriter, req *http.Request) {
+	var e pagerduty.ChangeEvent
+	size, err := decodeEvent(req, &e)
+	if err != nil {
+		writeInvalidEvent(w, "Malformed JSON")
+		return
+	}
+
+	errs := r.validateRoutingKey("routing_key", e.RoutingKey)
+	errs = append(errs, validateSize(size)...)
+	errs = append(errs, validateSummary(e.Payload.Summary)...)
+
+	for i, link := range e.Links {
+		if link.Href == "" {
+			errs = append(errs, fmt.Sprintf("'links[%d].href' is missing or blank", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		writeInvalidEvent(w, errs...)
+		return
+	}
+
+	r.mu.Lock()
+	r.changeEvents = append(r.changeEvents, e)
+	r.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, pagerduty.ChangeEventResponse{
+		Status:  "success",
+		Message: "Change event processed",
+	})
+}
+
+// createEvent handles legacy Events API V1 events.
+func (r *EventsReceiver) createEvent(w http.ResponseWriter, req *http.Request) {
+	var e pagerduty.Event
+	if err := json.NewDecoder(req.Body).Decode(&e); err != nil {
+		writeInvalidEvent(w, "Malformed JSON")
+		return
+	}
+
+	errs := r.validateRoutingKey("service_key", e.ServiceKey)
+
+	switch pagerduty.V2Action(e.Type) {
+	case pagerduty.V2ActionTrigger:
+		if e.Description == "" {
+			errs = append(errs, "'description' is missing or blank")
+		}
+
+	case pagerduty.V2ActionAcknowledge, pagerduty.V2ActionResolve:
+		if e.IncidentKey == "" {
+			errs = append(errs, "'incident_key' is missing or blank")
+		}
+
+	case "":
+		errs = append(errs, "'event_type' is missing or blank")
+
+	default:
+		errs = append(errs, "'event_type' is invalid (must be one of the following: 'trigger', 'acknowledge' or 'resolve')")
+	}
+
+	if len(errs) > 0 {
+		writeInvalidEvent(w, errs...)
+		return
+	}
+
+	if e.IncidentKey == "" {
+		e.IncidentKey = newDedupKey()
+	}
+
+	r.mu.Lock()
+	r.legacyEvents = append(r.legacyEvents, e)
+	r.mu.Unlock()
+
+	r.received(receivedEvent{
+		routingKey: e.ServiceKey,
+		action:     pagerduty.V2Action(e.Type),
+		dedupKey:   e.IncidentKey,
+		summary:    e.Description,
+	})
+
+	writeJSON(w, http.StatusOK, pagerduty.EventResponse{
+		Status:      "success",
+		Message:     "Event processed",
+		IncidentKey: e.IncidentKey,
+	})
+}
+
+func (r *EventsReceiver) received(e receivedEvent) {
+	if r.onEvent != nil {
+		r.onEvent(e)
+	}
+}
+
+// newDedupKey returns a new random dedup key, in the style of the ones
+// generated by the Events API.
+func newDedupKey() string {
+	b := make([]byte, routingKeyLength/2)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// eventsErrorStatus returns the status of the Events API responses with the
+// HTTP status code.
+func eventsErrorStatus(code int) string {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return "throttle event"
+
+	case code >= 400 && code < 500:
+		return "invalid event"
+
+	default:
+		return "error"
+	}
+}
+
+func writeEventsError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	writeJSON(w, status, pagerduty.EventsAPIV2ErrorObject{
+		Status:  eventsErrorStatus(status),
+		Message: message,
+	})
+}
+
+func writeEventsFault(w http.ResponseWriter, f *Fault) {
+	for name, values := range f.Header {
+		w.Header()[name] = values
+	}
+
+	message := f.Message
+	if message == "" && f.StatusCode == http.StatusTooManyRequests {
+		message = "Requests for this service are arriving too quickly. Please retry later."
+	}
+
+	writeEventsError(w, f.StatusCode, message)
+}
+
+func writeInvalidEvent(w http.ResponseWriter, errs ...string) {
+	writeJSON(w, http.StatusBadRequest, pagerduty.EventsAPIV2ErrorObject{
+		Status:  "invalid event",
+		Message: "Event object is invalid",
+		Errors:  errs,
+	})
+}
+
+// registerEvents serves the Events API using the receiver of the server.
+func (s *Server) registerEvents(mux *http.ServeMux) {
+	for _, path := range []string{eventsPath, changeEventsPath, legacyEventsPath} {
+		mux.Handle("POST "+path, s.receiver)
+	}
+}
+
+// handleEvent triggers, acknowledges, and resolves the incident with the
+// event's dedup key as its incident key.
+func (s *Server) handleEvent(e receivedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident := s.openIncident(e.dedupKey)
+
+	switch {
+	case e.action == pagerduty.V2ActionTrigger && incident == nil:
+		obj := object{
+			"title":        e.summary,
+			"incident_key": e.dedupKey,
+		}
+
+		if id := s.serviceForRoutingKey(e.routingKey); id != "" {
+			obj["service"] = object{"id": id}
+		}
+
+		s.newIncident(obj)
+
+	case e.action == pagerduty.V2ActionAcknowledge && incident != nil && incident["status"] == "triggered":
+		s.updateIncident(incident["id"].(string), object{"status": "acknowledged"})
+
+	case e.action == pagerduty.V2ActionResolve && incident != nil:
+		s.updateIncident(incident["id"].(string), object{"status": "resolved"})
+	}
+}
+
+// serviceForRoutingKey returns the ID of the service with an integration
+// using the routing key, if any. s.mu must be held.
+func (s *Server) serviceForRoutingKey(key string) string {
+	for _, svc := range s.services.list() {
+		integrations, _ := svc["integrations"].([]interface{})
+		for _, i := range integrations {
+			if integration, _ := i.(object); integration["integration_key"] == key {
+				return svc["id"].(string)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,232 @@
+package pagerdutytest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+const testRoutingKey = "R0123456789ABCDEF0123456789ABCDE"
+
+func newTestEventsClient(t *testing.T, opts ...pagerduty.ClientOptions) (*EventsReceiver, *pagerduty.EventsClient) {
+	t.Helper()
+
+	receiver := NewEventsReceiver()
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	opts = append([]pagerduty.ClientOptions{pagerduty.WithV2EventsAPIEndpoint(server.URL)}, opts...)
+
+	return receiver, pagerduty.NewEventsClient(testRoutingKey, opts...)
+}
+
+func TestEventsReceiver_V2Events(t *testing.T) {
+	receiver, client := newTestEventsClient(t)
+	ctx := context.Background()
+
+	resp, err := client.ManageEventWithContext(ctx, &pagerduty.V2Event{
+		Action:  pagerduty.V2ActionTrigger,
+		Payload: &pagerduty.V2Payload{Summary: "disk full", Source: "db1", Severity: pagerduty.V2SeverityCritical},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Status != "success" || resp.Message != "Event processed" || len(resp.DedupKey) != 32 {
+		t.Errorf("ManageEventWithContext() = %+v, want a success with a generated dedup key", resp)
+	}
+
+	_, err = client.ManageEventWithContext(ctx, &pagerduty.V2Event{Action: pagerduty.V2ActionResolve, DedupKey: resp.DedupKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := receiver.V2Events()
+	if len(events) != 2 || events[0].DedupKey != resp.DedupKey || events[1].Action != pagerduty.V2ActionResolve {
+		t.Errorf("V2Events() = %+v", events)
+	}
+
+	receiver.Reset()
+
+	if n := len(receiver.V2Events()); n != 0 {
+		t.Errorf("len(V2Events()) = %d after Reset(), want 0", n)
+	}
+}
+
+func TestEventsReceiver_invalidEvents(t *testing.T) {
+	receiver, client := newTestEventsClient(t)
+
+	tests := []struct {
+		name  string
+		event pagerduty.V2Event
+		want  []string
+	}{
+		{
+			name:  "routing_key",
+			event: pagerduty.V2Event{RoutingKey: "abc", Action: pagerduty.V2ActionResolve, DedupKey: "abc"},
+			want:  []string{"Length of 'routing_key' is incorrect (should be 32 characters)"},
+		},
+		{
+			name:  "event_action",
+			event: pagerduty.V2Event{DedupKey: "abc"},
+			want:  []string{"'event_action' is missing or blank"},
+		},
+		{
+			name:  "payload",
+			event: pagerduty.V2Event{Action: pagerduty.V2ActionTrigger},
+			want:  []string{"'payload' is missing or blank"},
+		},
+		{
+			name: "payload_fields",
+			event: pagerduty.V2Event{
+				Action:  pagerduty.V2ActionTrigger,
+				Payload: &pagerduty.V2Payload{Severity: "warn"},
+			},
+			want: []string{
+				"'payload.summary' is missing or blank",
+				"'payload.source' is missing or blank",
+				"'payload.severity' is invalid (must be one of the following: 'critical', 'warning', 'error' or 'info')",
+			},
+		},
+		{
+			name:  "dedup_key",
+			event: pagerduty.V2Event{Action: pagerduty.V2ActionAcknowledge},
+			want:  []string{"'dedup_key' is missing or blank"},
+		},
+		{
+			name: "summary_too_long",
+			event: pagerduty.V2Event{
+				Action:  pagerduty.V2ActionTrigger,
+				Payload: &pagerduty.V2Payload{Summary: strings.Repeat("é", 1025), Source: "db1", Severity: pagerduty.V2SeverityInfo},
+			},
+			want: []string{"'payload.summary' is too long (maximum is 1024 characters)"},
+		},
+		{
+			name: "too_large",
+			event: pagerduty.V2Event{
+				Action:  pagerduty.V2ActionTrigger,
+				Payload: &pagerduty.V2Payload{Summary: "disk full", Source: "db1", Severity: pagerduty.V2SeverityInfo, Details: strings.Repeat("a", 512*1024)},
+			},
+			want: []string{"Event object is too large (maximum is 524288 bytes)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ManageEventWithContext(context.Background(), &tt.event)
+
+			var eae pagerduty.EventsAPIV2Error
+			if !errors.As(err, &eae) || !eae.BadRequest() {
+				t.Fatalf("ManageEventWithContext() error = %v, want a bad request", err)
+			}
+
+			got := eae.APIError.ErrorObject
+			if got.Status != "invalid event" || got.Message != "Event object is invalid" {
+				t.Errorf("error object = %+v", got)
+			}
+
+			if len(got.Errors) != len(tt.want) {
+				t.Fatalf("errors = %q, want %q", got.Errors, tt.want)
+			}
+
+			for i := range tt.want {
+				if got.Errors[i] != tt.want[i] {
+					t.Errorf("errors[%d] = %q, want %q", i, got.Errors[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if n := len(receiver.V2Events()); n != 0 {
+		t.Errorf("len(V2Events()) = %d, want 0", n)
+	}
+}
+
+func TestEventsReceiver_ChangeAndLegacyEvents(t *testing.T) {
+	receiver, client := newTestEventsClient(t)
+	ctx := context.Background()
+
+	_, err := client.CreateChangeEventWithContext(ctx, pagerduty.ChangeEvent{
+		Payload: pagerduty.ChangeEventPayload{Summary: "deployed v1.2.3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if events := receiver.ChangeEvents(); len(events) != 1 || events[0].Payload.Summary != "deployed v1.2.3" {
+		t.Errorf("ChangeEvents() = %+v", events)
+	}
+
+	_, err = client.CreateChangeEventWithContext(ctx, pagerduty.ChangeEvent{})
+	if err == nil {
+		t.Error("expected an error for a change event without a summary")
+	}
+
+	_, err = client.CreateChangeEventWithContext(ctx, pagerduty.ChangeEvent{
+		Payload: pagerduty.ChangeEventPayload{Summary: strings.Repeat("a", 1025)},
+	})
+	if err == nil {
+		t.Error("expected an error for a change event with a summary over 1024 characters")
+	}
+
+	resp, err := client.CreateEventWithContext(ctx, pagerduty.Event{Type: "trigger", Description: "disk full"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Status != "success" || resp.HTTPStatus != http.StatusOK || resp.IncidentKey == "" {
+		t.Errorf("CreateEventWithContext() = %+v", resp)
+	}
+
+	resp, err = client.CreateEventWithContext(ctx, pagerduty.Event{Type: "resolve"})
+	if err == nil || resp.HTTPStatus != http.StatusBadRequest {
+		t.Errorf("CreateEventWithContext() = %+v, %v, want a bad request", resp, err)
+	}
+
+	if events := receiver.Events(); len(events) != 1 || events[0].ServiceKey != testRoutingKey {
+		t.Errorf("Events() = %+v", events)
+	}
+}
+
+func TestEventsReceiver_InjectFault(t *testing.T) {
+	receiver, client := newTestEventsClient(t, pagerduty.WithRetryStrategy(&pagerduty.FullJitterRetryStrategy{MaxRetries: 2, BaseDelay: time.Millisecond}))
+	ctx := context.Background()
+
+	event := &pagerduty.V2Event{Action: pagerduty.V2ActionResolve, DedupKey: "abc"}
+
+	// rate limited events are retried
+	receiver.InjectFault(Fault{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"0"}},
+		Times:      1,
+	})
+
+	if _, err := client.ManageEventWithContext(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+
+	receiver.InjectFault(Fault{Path: eventsPath, StatusCode: http.StatusServiceUnavailable})
+
+	_, err := client.ManageEventWithContext(ctx, event)
+
+	var eae pagerduty.EventsAPIV2Error
+	if !errors.As(err, &eae) || !eae.Temporary() || eae.APIError.ErrorObject.Status != "error" {
+		t.Fatalf("ManageEventWithContext() error = %v, want injected fault", err)
+	}
+
+	receiver.ClearFaults()
+
+	if _, err := client.ManageEventWithContext(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(receiver.V2Events()); n != 2 {
+		t.Errorf("len(V2Events()) = %d, want 2", n)
+	}
+}
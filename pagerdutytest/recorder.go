@@ -0,0 +1,425 @@
+package pagerdutytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+// Mode is the mode of operation of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of an existing cassette, without
+	// making any real requests.
+	ModeReplay Mode = iota
+
+	// ModeRecord makes real requests, and records them to the cassette when
+	// the Recorder is stopped, replacing its existing content.
+	ModeRecord
+
+	// ModeAuto replays the cassette if it exists, and records it otherwise.
+	ModeAuto
+)
+
+// redacted replaces the value of sensitive data in cassettes.
+const redacted = "REDACTED"
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is an HTTP request and its response, recorded in a cassette.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is an HTTP request recorded in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is an HTTP response recorded in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Matcher returns whether a request matches a recorded request when replaying
+// a cassette. The request has been sanitized in the same way as the recorded
+// requests were.
+type Matcher func(r RecordedRequest, recorded RecordedRequest) bool
+
+// MatchMethod matches requests with the same HTTP method.
+func MatchMethod(r, recorded RecordedRequest) bool {
+	return r.Method == recorded.Method
+}
+
+// MatchPath matches requests with the same URL path.
+func MatchPath(r, recorded RecordedRequest) bool {
+	u1, err1 := url.Parse(r.URL)
+	u2, err2 := url.Parse(recorded.URL)
+	return err1 == nil && err2 == nil && u1.Path == u2.Path
+}
+
+// MatchQuery matches requests with the same query parameters, regardless of
+// their order.
+func MatchQuery(r, recorded RecordedRequest) bool {
+	u1, err1 := url.Parse(r.URL)
+	u2, err2 := url.Parse(recorded.URL)
+	return err1 == nil && err2 == nil && reflect.DeepEqual(u1.Query(), u2.Query())
+}
+
+// MatchJSONBody matches requests with equivalent JSON bodies, regardless of
+// formatting and the order of object keys. Bodies which aren't valid JSON must
+// be identical.
+func MatchJSONBody(r, recorded RecordedRequest) bool {
+	if r.Body == recorded.Body {
+		return true
+	}
+
+	var v1, v2 interface{}
+	if json.Unmarshal([]byte(r.Body), &v1) != nil || json.Unmarshal([]byte(recorded.Body), &v2) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(v1, v2)
+}
+
+// MatchAll matches requests which are matched by all of the matchers.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(r, recorded RecordedRequest) bool {
+		for _, m := range matchers {
+			if !m(r, recorded) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// DefaultMatcher matches requests on their method, path, query, and JSON body.
+var DefaultMatcher = MatchAll(MatchMethod, MatchPath, MatchQuery, MatchJSONBody)
+
+// Recorder is a pagerduty.HTTPClient which records HTTP interactions to a
+// cassette file, and replays them, allowing tests to run deterministically
+// without access to the PagerDuty API. It's used by setting it as the
+// HTTPClient of a *pagerduty.Client:
+//
+//	rec, err := pagerdutytest.NewRecorder("testdata/create_service.json", pagerdutytest.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client := pagerduty.NewClient(os.Getenv("PAGERDUTY_TOKEN"))
+//	client.HTTPClient = rec
+//
+// Before being recorded, interactions are sanitized: the Authorization header
+// and the routing keys found in JSON bodies are redacted. Additional
+// sanitization can be configured using WithSanitizer.
+type Recorder struct {
+	path       string
+	mode       Mode
+	client     pagerduty.HTTPClient
+	matcher    Matcher
+	sanitizers []func(*Interaction)
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithRealClient sets the HTTP client used to make real requests when
+// recording. http.DefaultClient is used by default.
+func WithRealClient(client pagerduty.HTTPClient) RecorderOption {
+	return func(r *Recorder) {
+		r.client = client
+	}
+}
+
+// WithMatcher sets the Matcher used to find the recorded interaction of a
+// request when replaying. DefaultMatcher is used by default.
+func WithMatcher(m Matcher) RecorderOption {
+	return func(r *Recorder) {
+		r.matcher = m
+	}
+}
+
+// WithSanitizer adds a function called to sanitize each interaction before
+// it's recorded, after the default sanitization. When replaying, it's called
+// with the interaction of each request, before matching it.
+func WithSanitizer(sanitize func(*Interaction)) RecorderOption {
+	return func(r *Recorder) {
+		r.sanitizers = append(r.sanitizers, sanitize)
+	}
+}
+
+// NewRecorder returns a Recorder using the cassette file at path. In ModeAuto,
+// the Recorder replays the cassette if the file exists, and records it
+// otherwise. Replaying a cassette which doesn't exist returns an error.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:       path,
+		mode:       mode,
+		client:     http.DefaultClient,
+		matcher:    DefaultMatcher,
+		sanitizers: []func(*Interaction){sanitizeInteraction},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// Mode returns the mode the Recorder is operating in, which is never ModeAuto.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Do implements pagerduty.HTTPClient, by making the request and recording the
+// interaction when recording, or by returning the recorded response of the
+// first unused interaction matching the request when replaying.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	i := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   string(body),
+		},
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, i)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	i.Response = RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       string(respBody),
+	}
+
+	r.sanitize(&i)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, i Interaction) (*http.Response, error) {
+	r.sanitize(&i)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for n, recorded := range r.cassette.Interactions {
+		if r.used[n] || !r.matcher(i.Request, recorded.Request) {
+			continue
+		}
+
+		r.used[n] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode:    recorded.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(recorded.Response.Body)),
+			ContentLength: int64(len(recorded.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction in %s matching %s %s", r.path, req.Method, req.URL)
+}
+
+func (r *Recorder) sanitize(i *Interaction) {
+	for _, sanitize := range r.sanitizers {
+		sanitize(i)
+	}
+}
+
+// Stop saves the cassette when recording. When replaying, it returns an error
+// if some of the recorded interactions weren't replayed.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		var unused int
+		for _, used := range r.used {
+			if !used {
+				unused++
+			}
+		}
+
+		if unused > 0 {
+			return fmt.Errorf("%d recorded interactions in %s were not replayed", unused, r.path)
+		}
+
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// readBody reads the body, and replaces it with a new reader with the same
+// content, so that it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, err
+}
+
+// redactedFields are the JSON fields whose values are redacted from the
+// bodies of interactions, such as the routing keys of Events API payloads and
+// service integrations.
+var redactedFields = map[string]struct{}{
+	"routing_key":     {},
+	"routing_keys":    {},
+	"service_key":     {},
+	"integration_key": {},
+}
+
+// sanitizeInteraction redacts the Authorization header, and the routing keys
+// found in the request and response bodies.
+func sanitizeInteraction(i *Interaction) {
+	if i.Request.Header.Get("Authorization") != "" {
+		i.Request.Header.Set("Authorization", redacted)
+	}
+
+	i.Request.Body = redactJSON(i.Request.Body)
+	i.Response.Body = redactJSON(i.Response.Body)
+}
+
+// redactJSON redacts the values of redactedFields from a JSON document.
+// Documents which aren't valid JSON are returned as-is.
+func redactJSON(body string) string {
+	var v interface{}
+	if body == "" || json.Unmarshal([]byte(body), &v) != nil {
+		return body
+	}
+
+	if !redactValue(v) {
+		return body
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return string(data)
+}
+
+// redactValue redacts the fields of a decoded JSON value, returning whether
+// any were found.
+func redactValue(v interface{}) bool {
+	var found bool
+
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range vv {
+			if _, ok := redactedFields[k]; ok {
+				vv[k] = redacted
+				found = true
+				continue
+			}
+
+			found = redactValue(fv) || found
+		}
+
+	case []interface{}:
+		for _, ev := range vv {
+			found = redactValue(ev) || found
+		}
+	}
+
+	return found
+}
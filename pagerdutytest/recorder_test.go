@@ -0,0 +1,180 @@
+package pagerdutytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+func TestRecorder_RecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	ctx := context.Background()
+
+	event := &pagerduty.V2Event{
+		RoutingKey: "secret-routing-key",
+		Action:     "trigger",
+		Payload:    &pagerduty.V2Payload{Summary: "disk full", Source: "db1", Severity: "critical"},
+	}
+
+	// record against the fake server
+	fake := NewServer()
+
+	rec, err := NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, ModeRecord, rec.Mode())
+
+	client := pagerduty.NewClient("secret-token",
+		pagerduty.WithAPIEndpoint(fake.URL),
+		pagerduty.WithV2EventsAPIEndpoint(fake.URL),
+	)
+	client.HTTPClient = rec
+
+	svc, err := client.CreateServiceWithContext(ctx, pagerduty.Service{Name: "API"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ManageEventWithContext(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"secret-token", "secret-routing-key"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// replay with the fake server shut down, and a different routing key
+	rec, err = NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, ModeReplay, rec.Mode())
+
+	client = pagerduty.NewClient("other-token",
+		pagerduty.WithAPIEndpoint(fake.URL),
+		pagerduty.WithV2EventsAPIEndpoint(fake.URL),
+	)
+	client.HTTPClient = rec
+
+	got, err := client.CreateServiceWithContext(ctx, pagerduty.Service{Name: "API"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, svc.ID, got.ID)
+
+	// unmatched requests fail, and don't consume interactions
+	if _, err := client.CreateServiceWithContext(ctx, pagerduty.Service{Name: "Other"}); err == nil {
+		t.Error("expected an error for an unrecorded request")
+	}
+
+	if err := rec.Stop(); err == nil {
+		t.Error("expected an error from Stop() with an unreplayed interaction")
+	}
+
+	event.RoutingKey = "another-routing-key"
+	resp, err := client.ManageEventWithContext(ctx, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.DedupKey == "" {
+		t.Error("replayed response has no dedup key")
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("expected an error replaying a missing cassette")
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	recorded := RecordedRequest{
+		Method: "GET",
+		URL:    "https://api.pagerduty.com/incidents?statuses[]=triggered&limit=25",
+		Body:   `{"a": 1, "b": [true, null]}`,
+	}
+
+	tests := []struct {
+		name    string
+		req     RecordedRequest
+		matcher Matcher
+		want    bool
+	}{
+		{
+			name:    "query_order",
+			req:     RecordedRequest{URL: "http://127.0.0.1:1234/incidents?limit=25&statuses[]=triggered"},
+			matcher: MatchAll(MatchPath, MatchQuery),
+			want:    true,
+		},
+		{
+			name:    "query_mismatch",
+			req:     RecordedRequest{URL: "https://api.pagerduty.com/incidents?limit=100&statuses[]=triggered"},
+			matcher: MatchQuery,
+			want:    false,
+		},
+		{
+			name:    "path_mismatch",
+			req:     RecordedRequest{URL: "https://api.pagerduty.com/services?limit=25&statuses[]=triggered"},
+			matcher: MatchPath,
+			want:    false,
+		},
+		{
+			name:    "method_mismatch",
+			req:     RecordedRequest{Method: "POST"},
+			matcher: MatchMethod,
+			want:    false,
+		},
+		{
+			name:    "json_formatting",
+			req:     RecordedRequest{Body: `{"b":[true,null],"a":1}`},
+			matcher: MatchJSONBody,
+			want:    true,
+		},
+		{
+			name:    "json_mismatch",
+			req:     RecordedRequest{Body: `{"a": 2, "b": [true, null]}`},
+			matcher: MatchJSONBody,
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEqual(t, tt.want, tt.matcher(tt.req, recorded))
+		})
+	}
+}
+
+func testEqual(t *testing.T, expected interface{}, actual interface{}) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("returned %#v; want %#v", actual, expected)
+	}
+}
@@ -0,0 +1,674 @@
+package pagerdutytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+// object is the JSON representation of an API resource.
+type object = map[string]interface{}
+
+// collection stores the resources of a given type, in creation order.
+type collection struct {
+	singular string
+	plural   string
+	typ      string
+
+	items map[string]object
+	order []string
+}
+
+func newCollection(singular, plural, typ string) *collection {
+	return &collection{
+		singular: singular,
+		plural:   plural,
+		typ:      typ,
+		items:    make(map[string]object),
+	}
+}
+
+func (c *collection) get(id string) (object, bool) {
+	obj, ok := c.items[id]
+	return obj, ok
+}
+
+func (c *collection) list() []object {
+	objs := make([]object, 0, len(c.order))
+	for _, id := range c.order {
+		objs = append(objs, c.items[id])
+	}
+
+	return objs
+}
+
+// create stores obj as a new resource, filling in its ID and the fields
+// common to all resources. s.mu must be held.
+func (c *collection) create(s *Server, obj object) object {
+	id, _ := obj["id"].(string)
+	if _, exists := c.items[id]; id == "" || exists {
+		id = s.newID()
+	}
+
+	obj["id"] = id
+	obj["type"] = c.typ
+	obj["self"] = s.URL + "/" + c.plural + "/" + id
+
+	if name, ok := obj["name"].(string); ok {
+		setDefault(obj, "summary", name)
+	}
+
+	c.items[id] = obj
+	c.order = append(c.order, id)
+
+	return obj
+}
+
+// update merges the fields of changes into the resource, like the API does
+// for PUT requests.
+func (c *collection) update(id string, changes object) (object, bool) {
+	obj, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+
+	for k, v := range changes {
+		if k == "id" || k == "type" || k == "self" {
+			continue
+		}
+
+		obj[k] = v
+	}
+
+	return obj, true
+}
+
+func (c *collection) delete(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+
+	delete(c.items, id)
+	c.order = slices.DeleteFunc(c.order, func(v string) bool { return v == id })
+
+	return true
+}
+
+func (s *Server) registerREST(mux *http.ServeMux) {
+	mux.HandleFunc("GET /incidents", s.listIncidents)
+	mux.HandleFunc("POST /incidents", s.createIncident)
+	mux.HandleFunc("PUT /incidents", s.manageIncidents)
+	mux.HandleFunc("GET /incidents/{id}", s.getHandler(s.incidents))
+	mux.HandleFunc("PUT /incidents/{id}", s.updateHandler(s.incidents))
+
+	mux.HandleFunc("POST /schedules/preview", s.previewSchedule)
+	mux.HandleFunc("GET /schedules/{id}/overrides", s.listOverrides)
+	mux.HandleFunc("POST /schedules/{id}/overrides", s.createOverride)
+	mux.HandleFunc("DELETE /schedules/{id}/overrides/{override_id}", s.deleteOverride)
+
+	for _, c := range []*collection{s.services, s.users, s.schedules, s.escalationPolicies} {
+		s.registerCRUD(mux, "/"+c.plural, c)
+	}
+
+	s.registerCRUD(mux, "/event_orchestrations", s.orchestrations)
+
+	mux.HandleFunc("GET /oncalls", s.listOnCalls)
+}
+
+func (s *Server) registerCRUD(mux *http.ServeMux, path string, c *collection) {
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		query := strings.ToLower(r.URL.Query().Get("query"))
+		objs := slices.DeleteFunc(c.list(), func(obj object) bool {
+			name, _ := obj["name"].(string)
+			return !strings.Contains(strings.ToLower(name), query)
+		})
+
+		writePage(w, r, c.plural, objs)
+	})
+
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := decodeObject(w, r, c.singular)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, object{c.singular: c.create(s, obj)})
+	})
+
+	mux.HandleFunc("GET "+path+"/{id}", s.getHandler(c))
+	mux.HandleFunc("PUT "+path+"/{id}", s.updateHandler(c))
+
+	mux.HandleFunc("DELETE "+path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !c.delete(r.PathValue("id")) {
+			writeError(w, http.StatusNotFound, 2100, "Not Found")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) getHandler(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		obj, ok := c.get(r.PathValue("id"))
+		if !ok {
+			writeError(w, http.StatusNotFound, 2100, "Not Found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, object{c.singular: obj})
+	}
+}
+
+func (s *Server) updateHandler(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		changes, ok := decodeObject(w, r, c.singular)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		obj, ok := c.update(r.PathValue("id"), changes)
+		if !ok {
+			writeError(w, http.StatusNotFound, 2100, "Not Found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, object{c.singular: obj})
+	}
+}
+
+func (s *Server) listIncidents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	statuses := q["statuses[]"]
+	serviceIDs := q["service_ids[]"]
+	incidentKey := q.Get("incident_key")
+
+	objs := slices.DeleteFunc(s.incidents.list(), func(obj object) bool {
+		status, _ := obj["status"].(string)
+		if len(statuses) > 0 && !slices.Contains(statuses, status) {
+			return true
+		}
+
+		if len(serviceIDs) > 0 && !slices.Contains(serviceIDs, refID(obj["service"])) {
+			return true
+		}
+
+		return incidentKey != "" && obj["incident_key"] != incidentKey
+	})
+
+	writePage(w, r, "incidents", objs)
+}
+
+func (s *Server) createIncident(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decodeObject(w, r, "incident")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, _ := obj["incident_key"].(string); key != "" && s.openIncident(key) != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Open incident with key "+strconv.Quote(key)+" already exists")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, object{"incident": s.newIncident(obj)})
+}
+
+func (s *Server) manageIncidents(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Incidents []object `json:"incidents"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, changes := range body.Incidents {
+		id, _ := changes["id"].(string)
+		if _, ok := s.incidents.get(id); !ok {
+			writeError(w, http.StatusNotFound, 2100, "Incident "+strconv.Quote(id)+" Not Found")
+			return
+		}
+	}
+
+	incidents := make([]object, 0, len(body.Incidents))
+	for _, changes := range body.Incidents {
+		incidents = append(incidents, s.updateIncident(changes["id"].(string), changes))
+	}
+
+	writeJSON(w, http.StatusOK, object{"incidents": incidents})
+}
+
+// newIncident stores a new incident, with the defaults set by the API.
+// s.mu must be held.
+func (s *Server) newIncident(obj object) object {
+	s.nextIncidentNumber++
+
+	obj["incident_number"] = s.nextIncidentNumber
+	obj["created_at"] = s.timestamp()
+	obj["last_status_change_at"] = obj["created_at"]
+
+	setDefault(obj, "status", "triggered")
+	setDefault(obj, "urgency", "high")
+
+	if title, ok := obj["title"].(string); ok {
+		obj["summary"] = title
+	}
+
+	if svc, ok := s.services.get(refID(obj["service"])); ok {
+		obj["service"] = object{
+			"id":      svc["id"],
+			"type":    "service_reference",
+			"summary": svc["summary"],
+			"self":    svc["self"],
+		}
+	}
+
+	return s.incidents.create(s, obj)
+}
+
+// updateIncident merges changes into an existing incident. s.mu must be held.
+func (s *Server) updateIncident(id string, changes object) object {
+	obj, _ := s.incidents.get(id)
+	if status, ok := changes["status"]; ok && status != obj["status"] {
+		obj["last_status_change_at"] = s.timestamp()
+	}
+
+	obj, _ = s.incidents.update(id, changes)
+
+	return obj
+}
+
+// openIncident returns the unresolved incident with the incident key, if
+// any. s.mu must be held.
+func (s *Server) openIncident(key string) object {
+	for _, obj := range s.incidents.list() {
+		if obj["incident_key"] == key && obj["status"] != "resolved" {
+			return obj
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listOnCalls(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	userIDs := q["user_ids[]"]
+	scheduleIDs := q["schedule_ids[]"]
+	policyIDs := q["escalation_policy_ids[]"]
+
+	var objs []object
+	for _, oc := range s.oncalls {
+		if (len(userIDs) > 0 && !slices.Contains(userIDs, oc.User.ID)) ||
+			(len(scheduleIDs) > 0 && !slices.Contains(scheduleIDs, oc.Schedule.ID)) ||
+			(len(policyIDs) > 0 && !slices.Contains(policyIDs, oc.EscalationPolicy.ID)) {
+			continue
+		}
+
+		obj, err := toObject(oc)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, 0, err.Error())
+			return
+		}
+
+		objs = append(objs, obj)
+	}
+
+	writePage(w, r, "oncalls", objs)
+}
+
+// previewSchedule renders the schedule using pagerduty.RenderSchedule, between
+// the since and until query parameters, which default to now and a week later.
+// The schedule isn't saved.
+func (s *Server) previewSchedule(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decodeObject(w, r, "schedule")
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, 0, err.Error())
+		return
+	}
+
+	var sched pagerduty.Schedule
+	if err := json.Unmarshal(data, &sched); err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return
+	}
+
+	q := r.URL.Query()
+
+	parsedSince, err := pagerduty.ParseTime(q.Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return
+	}
+
+	since := parsedSince.Time
+	if since.IsZero() {
+		since = time.Now().UTC()
+	}
+
+	parsedUntil, err := pagerduty.ParseTime(q.Get("until"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return
+	}
+
+	until := parsedUntil.Time
+
+	if until.IsZero() {
+		until = since.AddDate(0, 0, 7)
+	}
+
+	rendered, err := pagerduty.RenderSchedule(sched, pagerduty.RenderScheduleOptions{Since: since, Until: until})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 2001, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"schedule": rendered})
+}
+
+// scheduleOverrides returns the overrides of the schedule, if the schedule
+// exists.
+func (s *Server) scheduleOverrides(scheduleID string) (*collection, bool) {
+	if _, ok := s.schedules.get(scheduleID); !ok {
+		return nil, false
+	}
+
+	c, ok := s.overrides[scheduleID]
+	if !ok {
+		c = newCollection("override", "schedules/"+scheduleID+"/overrides", "override")
+		s.overrides[scheduleID] = c
+	}
+
+	return c, true
+}
+
+func (s *Server) listOverrides(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.scheduleOverrides(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+
+	writePage(w, r, "overrides", c.list())
+}
+
+func (s *Server) createOverride(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decodeObject(w, r, "override")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.scheduleOverrides(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, object{"override": c.create(s, obj)})
+}
+
+func (s *Server) deleteOverride(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.scheduleOverrides(r.PathValue("id"))
+	if !ok || !c.delete(r.PathValue("override_id")) {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writePage writes the page of objs selected by the limit and offset query
+// parameters, along with the pagination fields.
+func writePage(w http.ResponseWriter, r *http.Request, key string, objs []object) {
+	q := r.URL.Query()
+
+	limit := queryInt(q, "limit", 25)
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	offset := queryInt(q, "offset", 0)
+	if offset < 0 {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return
+	}
+
+	page := []object{}
+	if offset < len(objs) {
+		page = objs[offset:min(offset+limit, len(objs))]
+	}
+
+	resp := object{
+		key:      page,
+		"limit":  limit,
+		"offset": offset,
+		"more":   offset+len(page) < len(objs),
+	}
+
+	if q.Get("total") == "true" {
+		resp["total"] = len(objs)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func queryInt(q url.Values, key string, def int) int {
+	if n, err := strconv.Atoi(q.Get(key)); err == nil {
+		return n
+	}
+
+	return def
+}
+
+// decodeObject decodes a request body with the resource wrapped in key, such
+// as {"service": {...}}, writing an error response if it's invalid.
+func decodeObject(w http.ResponseWriter, r *http.Request, key string) (object, bool) {
+	var body map[string]object
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body[key] == nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided")
+		return nil, false
+	}
+
+	return body[key], true
+}
+
+// setDefault sets the field of obj to value, unless it's already set.
+func setDefault(obj object, key, value string) {
+	if v, _ := obj[key].(string); v == "" {
+		obj[key] = value
+	}
+}
+
+// refID returns the ID of a resource reference, such as an incident's
+// service.
+func refID(v interface{}) string {
+	ref, _ := v.(object)
+	id, _ := ref["id"].(string)
+	return id
+}
+
+func toObject(v interface{}) (object, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func fromObject(obj object, v interface{}) {
+	// the object was decoded from the JSON encoding of v, so can't fail
+	data, _ := json.Marshal(obj)
+	_ = json.Unmarshal(data, v)
+}
+
+// seed stores v as a new resource of collection c, and returns it with the
+// fields set by the server, such as its ID.
+func seed[T any](s *Server, c *collection, v T, create func(object) object) T {
+	obj, err := toObject(v)
+	if err != nil {
+		panic("pagerdutytest: failed to encode " + c.singular + ": " + err.Error())
+	}
+
+	s.mu.Lock()
+	obj = create(obj)
+	s.mu.Unlock()
+
+	var result T
+	fromObject(obj, &result)
+
+	return result
+}
+
+func (s *Server) createFunc(c *collection) func(object) object {
+	return func(obj object) object {
+		return c.create(s, obj)
+	}
+}
+
+// AddIncident adds an incident to the server, and returns it with the fields
+// set by the server, such as its ID and incident number. The incident's status
+// defaults to "triggered".
+func (s *Server) AddIncident(i pagerduty.Incident) pagerduty.Incident {
+	return seed(s, s.incidents, i, s.newIncident)
+}
+
+// AddService adds a service to the server, and returns it with the fields set
+// by the server, such as its ID.
+func (s *Server) AddService(svc pagerduty.Service) pagerduty.Service {
+	return seed(s, s.services, svc, s.createFunc(s.services))
+}
+
+// AddUser adds a user to the server, and returns it with the fields set by the
+// server, such as its ID.
+func (s *Server) AddUser(u pagerduty.User) pagerduty.User {
+	return seed(s, s.users, u, s.createFunc(s.users))
+}
+
+// AddSchedule adds a schedule to the server, and returns it with the fields set
+// by the server, such as its ID.
+func (s *Server) AddSchedule(sched pagerduty.Schedule) pagerduty.Schedule {
+	return seed(s, s.schedules, sched, s.createFunc(s.schedules))
+}
+
+// AddEscalationPolicy adds an escalation policy to the server, and returns it
+// with the fields set by the server, such as its ID.
+func (s *Server) AddEscalationPolicy(ep pagerduty.EscalationPolicy) pagerduty.EscalationPolicy {
+	return seed(s, s.escalationPolicies, ep, s.createFunc(s.escalationPolicies))
+}
+
+// AddOrchestration adds an event orchestration to the server, and returns it
+// with the fields set by the server, such as its ID.
+func (s *Server) AddOrchestration(o pagerduty.Orchestration) pagerduty.Orchestration {
+	return seed(s, s.orchestrations, o, s.createFunc(s.orchestrations))
+}
+
+// AddOnCall adds an on-call entry to the server. On-call entries are returned
+// as-is by the on-calls endpoint, filtered by user, schedule, and escalation
+// policy.
+func (s *Server) AddOnCall(oc pagerduty.OnCall) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.oncalls = append(s.oncalls, oc)
+}
+
+// Overrides returns the overrides of the schedule, in creation order.
+func (s *Server) Overrides(scheduleID string) []pagerduty.Override {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var overrides []pagerduty.Override
+	if c, ok := s.overrides[scheduleID]; ok {
+		for _, obj := range c.list() {
+			var o pagerduty.Override
+			fromObject(obj, &o)
+			overrides = append(overrides, o)
+		}
+	}
+
+	return overrides
+}
+
+// Incident returns the incident with the ID, if it exists.
+func (s *Server) Incident(id string) (pagerduty.Incident, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var i pagerduty.Incident
+
+	obj, ok := s.incidents.get(id)
+	if ok {
+		fromObject(obj, &i)
+	}
+
+	return i, ok
+}
+
+// Incidents returns all the incidents of the server, in creation order.
+func (s *Server) Incidents() []pagerduty.Incident {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incidents := make([]pagerduty.Incident, 0, len(s.incidents.order))
+	for _, obj := range s.incidents.list() {
+		var i pagerduty.Incident
+		fromObject(obj, &i)
+		incidents = append(incidents, i)
+	}
+
+	return incidents
+}
@@ -0,0 +1,330 @@
+// Package pagerdutytest provides an in-process fake of the PagerDuty REST and
+// Events APIs, for testing code which uses the pagerduty package without
+// making requests to PagerDuty.
+//
+// The fake server is stateful: resources created using the API, or seeded
+// using methods like AddService, can be read, updated, listed, and deleted.
+// Incidents, services, users, schedules and their overrides, escalation
+// policies, on-calls, and event orchestrations are supported, along with the Events API V2, change
+// events, and legacy Events API V1 events. Schedule previews are rendered
+// using pagerduty.RenderSchedule. Faults, such as error responses and
+// rate limiting, can be injected to test how code handles them.
+//
+//	fake := pagerdutytest.NewServer()
+//	defer fake.Close()
+//
+//	client := pagerduty.NewClient("token",
+//		pagerduty.WithAPIEndpoint(fake.URL),
+//		pagerduty.WithV2EventsAPIEndpoint(fake.URL),
+//	)
+//
+// Code which only sends events can be tested using an EventsReceiver, which
+// validates events like the real Events API.
+package pagerdutytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+// Server is a fake PagerDuty API server. It's safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to be used as the client's API and
+	// Events API endpoints.
+	URL string
+
+	server *httptest.Server
+
+	mu sync.Mutex
+
+	nextID             uint64
+	nextIncidentNumber uint
+
+	incidents          *collection
+	services           *collection
+	users              *collection
+	schedules          *collection
+	escalationPolicies *collection
+	orchestrations     *collection
+	oncalls            []pagerduty.OnCall
+
+	// overrides are the overrides of each schedule, by schedule ID
+	overrides map[string]*collection
+
+	receiver *EventsReceiver
+
+	faults    []*Fault
+	rateLimit *rateLimit
+
+	now func() time.Time
+}
+
+// NewServer starts and returns a new fake PagerDuty API server. The caller
+// should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		incidents:          newCollection("incident", "incidents", "incident"),
+		services:           newCollection("service", "services", "service"),
+		users:              newCollection("user", "users", "user"),
+		schedules:          newCollection("schedule", "schedules", "schedule"),
+		escalationPolicies: newCollection("escalation_policy", "escalation_policies", "escalation_policy"),
+		orchestrations:     newCollection("orchestration", "orchestrations", "event_orchestration"),
+		overrides:          make(map[string]*collection),
+		receiver:           NewEventsReceiver(),
+		now:                time.Now,
+	}
+
+	// accept the integration keys the server is seeded with, which are
+	// usually shorter than real routing keys
+	s.receiver.checkRoutingKeys = false
+	s.receiver.onEvent = s.handleEvent
+
+	s.server = httptest.NewServer(s.handler())
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on
+// it have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Fault describes an error response returned by the server in place of
+// handling matching requests.
+type Fault struct {
+	// Method is the HTTP method of the requests to match. An empty Method
+	// matches all methods.
+	Method string
+
+	// Path is the path of the requests to match, such as "/incidents". A path
+	// ending with "*" matches all paths with that prefix, and an empty Path
+	// matches all paths.
+	Path string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code and Message are the PagerDuty error code and message included in
+	// the response's error object. The responses of the Events API only
+	// include the message.
+	Code    int
+	Message string
+
+	// Header contains additional headers to set on the response, such as
+	// Retry-After.
+	Header http.Header
+
+	// Times is the number of requests the fault is returned for. Zero means
+	// the fault is returned until the faults are cleared.
+	Times int
+
+	hits int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+
+	if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+
+	return f.Path == "" || f.Path == r.URL.Path
+}
+
+// InjectFault makes the server return an error response for the requests
+// matching f. When multiple faults match a request, the first one injected is
+// returned.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all the faults injected into the server.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+type rateLimit struct {
+	limit     int
+	window    time.Duration
+	remaining int
+	reset     time.Time
+}
+
+// SetRateLimit simulates the REST API rate limit, allowing limit requests per
+// window. The responses include the ratelimit-limit, ratelimit-remaining and
+// ratelimit-reset headers, and requests exceeding the limit receive a 429 Too
+// Many Requests response. A limit of zero disables the rate limit.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit <= 0 {
+		s.rateLimit = nil
+		return
+	}
+
+	s.rateLimit = &rateLimit{limit: limit, window: window}
+}
+
+// V2Events returns the Events API V2 events received by the server.
+func (s *Server) V2Events() []pagerduty.V2Event {
+	return s.receiver.V2Events()
+}
+
+// ChangeEvents returns the change events received by the server.
+func (s *Server) ChangeEvents() []pagerduty.ChangeEvent {
+	return s.receiver.ChangeEvents()
+}
+
+// Events returns the legacy Events API V1 events received by the server.
+func (s *Server) Events() []pagerduty.Event {
+	return s.receiver.Events()
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	s.registerREST(mux)
+	s.registerEvents(mux)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isEventsPath(r.URL.Path) {
+			if r.Header.Get("Authorization") == "" {
+				writeError(w, http.StatusUnauthorized, 2006, "Authentication failed")
+				return
+			}
+
+			if !s.allow(w) {
+				return
+			}
+		}
+
+		if f := s.fault(r); f != nil {
+			if isEventsPath(r.URL.Path) {
+				writeEventsFault(w, f)
+				return
+			}
+
+			for name, values := range f.Header {
+				w.Header()[name] = values
+			}
+
+			writeError(w, f.StatusCode, f.Code, f.Message)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// fault returns the fault matching r, if any.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return matchFault(&s.faults, r)
+}
+
+// matchFault returns the first of the faults matching r, if any, removing it
+// from the faults once it has been returned the number of Times it's for. The
+// lock guarding the faults must be held.
+func matchFault(faults *[]*Fault, r *http.Request) *Fault {
+	for i, f := range *faults {
+		if !f.matches(r) {
+			continue
+		}
+
+		f.hits++
+		if f.Times > 0 && f.hits >= f.Times {
+			*faults = append((*faults)[:i:i], (*faults)[i+1:]...)
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// allow applies the rate limit, returning false if the request exceeds it, in
+// which case a 429 response has been written.
+func (s *Server) allow(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rl := s.rateLimit
+	if rl == nil {
+		return true
+	}
+
+	now := s.now()
+	if !now.Before(rl.reset) {
+		rl.remaining = rl.limit
+		rl.reset = now.Add(rl.window)
+	}
+
+	// round up, so that the client doesn't retry before the reset
+	reset := int((rl.reset.Sub(now) + time.Second - 1) / time.Second)
+
+	w.Header().Set("ratelimit-limit", strconv.Itoa(rl.limit))
+	w.Header().Set("ratelimit-reset", strconv.Itoa(reset))
+
+	if rl.remaining == 0 {
+		w.Header().Set("ratelimit-remaining", "0")
+		writeError(w, http.StatusTooManyRequests, 2020, "Rate Limit Exceeded")
+		return false
+	}
+
+	rl.remaining--
+	w.Header().Set("ratelimit-remaining", strconv.Itoa(rl.remaining))
+
+	return true
+}
+
+// newID returns a new resource ID, in the style of the PagerDuty API.
+// s.mu must be held.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("P%06X", s.nextID)
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	writeJSON(w, status, map[string]pagerduty.APIErrorObject{
+		"error": {Code: code, Message: message},
+	})
+}
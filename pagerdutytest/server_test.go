@@ -0,0 +1,342 @@
+package pagerdutytest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+func newTestClient(t *testing.T, opts ...pagerduty.ClientOptions) (*Server, *pagerduty.Client) {
+	t.Helper()
+
+	fake := NewServer()
+	t.Cleanup(fake.Close)
+
+	opts = append([]pagerduty.ClientOptions{
+		pagerduty.WithAPIEndpoint(fake.URL),
+		pagerduty.WithV2EventsAPIEndpoint(fake.URL),
+	}, opts...)
+
+	return fake, pagerduty.NewClient("token", opts...)
+}
+
+func TestServer_ServiceCRUD(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	svc, err := client.CreateServiceWithContext(ctx, pagerduty.Service{Name: "Database"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if svc.ID == "" || svc.Type != "service" {
+		t.Fatalf("created service = %+v, want an ID and type", svc)
+	}
+
+	svc.Description = "Primary database"
+	if _, err := client.UpdateServiceWithContext(ctx, *svc); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.GetServiceWithContext(ctx, svc.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "Database" || got.Description != "Primary database" {
+		t.Errorf("service = %+v, want updated description", got)
+	}
+
+	if err := client.DeleteServiceWithContext(ctx, svc.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetServiceWithContext(ctx, svc.ID, nil)
+
+	var aerr pagerduty.APIError
+	if !errors.As(err, &aerr) || !aerr.NotFound() {
+		t.Errorf("GetServiceWithContext() error = %v, want not found", err)
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	fake, client := newTestClient(t)
+
+	for i := 0; i < 30; i++ {
+		fake.AddUser(pagerduty.User{Name: "user", Email: "user@example.com"})
+	}
+
+	resp, err := client.ListUsersWithContext(context.Background(), pagerduty.ListUsersOptions{Limit: 20, Offset: 20, Total: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Users) != 10 || resp.More || resp.Total != 30 {
+		t.Errorf("page = %d users, more %v, total %d; want 10 users, no more, total 30", len(resp.Users), resp.More, resp.Total)
+	}
+
+	var n int
+	for _, err := range client.Users(context.Background(), pagerduty.ListUsersOptions{Limit: 7}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n++
+	}
+
+	if n != 30 {
+		t.Errorf("iterated over %d users, want 30", n)
+	}
+}
+
+func TestServer_Incidents(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	svc := fake.AddService(pagerduty.Service{Name: "API"})
+
+	incident, err := client.CreateIncidentWithContext(ctx, "user@example.com", &pagerduty.CreateIncidentOptions{
+		Title:       "API is down",
+		Service:     &pagerduty.APIReference{ID: svc.ID, Type: "service_reference"},
+		IncidentKey: "api-down",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if incident.Status != "triggered" || incident.IncidentNumber != 1 || incident.Service.Summary != "API" {
+		t.Errorf("incident = %+v, want triggered incident #1 on service API", incident)
+	}
+
+	// the incident key of open incidents must be unique
+	_, err = client.CreateIncidentWithContext(ctx, "user@example.com", &pagerduty.CreateIncidentOptions{
+		Title:       "API is down again",
+		Service:     &pagerduty.APIReference{ID: svc.ID, Type: "service_reference"},
+		IncidentKey: "api-down",
+	})
+
+	var aerr pagerduty.APIError
+	if !errors.As(err, &aerr) || aerr.StatusCode != http.StatusBadRequest {
+		t.Errorf("CreateIncidentWithContext() error = %v, want bad request", err)
+	}
+
+	_, err = client.ManageIncidentsWithContext(ctx, "user@example.com", []pagerduty.ManageIncidentsOptions{
+		{ID: incident.ID, Status: "acknowledged"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.ListIncidentsWithContext(ctx, pagerduty.ListIncidentsOptions{Statuses: []string{"acknowledged"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Incidents) != 1 || resp.Incidents[0].ID != incident.ID {
+		t.Errorf("acknowledged incidents = %+v, want %s", resp.Incidents, incident.ID)
+	}
+}
+
+func TestServer_Events(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	fake.AddService(pagerduty.Service{
+		Name:         "API",
+		Integrations: []pagerduty.Integration{{IntegrationKey: "routing-key"}},
+	})
+
+	resp, err := client.ManageEventWithContext(ctx, &pagerduty.V2Event{
+		RoutingKey: "routing-key",
+		Action:     "trigger",
+		Payload:    &pagerduty.V2Payload{Summary: "disk full", Source: "db1", Severity: "critical"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	incidents := fake.Incidents()
+	if len(incidents) != 1 || incidents[0].IncidentKey != resp.DedupKey || incidents[0].Service.Summary != "API" {
+		t.Fatalf("incidents = %+v, want one incident with key %q on service API", incidents, resp.DedupKey)
+	}
+
+	_, err = client.ManageEventWithContext(ctx, &pagerduty.V2Event{
+		RoutingKey: "routing-key",
+		Action:     "resolve",
+		DedupKey:   resp.DedupKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if incident, _ := fake.Incident(incidents[0].ID); incident.Status != "resolved" {
+		t.Errorf("incident status = %q, want resolved", incident.Status)
+	}
+
+	_, err = client.ManageEventWithContext(ctx, &pagerduty.V2Event{RoutingKey: "routing-key", Action: "trigger"})
+	if err == nil {
+		t.Error("expected an error for an event without a payload")
+	}
+
+	if n := len(fake.V2Events()); n != 2 {
+		t.Errorf("len(V2Events()) = %d, want 2", n)
+	}
+
+	_, err = client.CreateChangeEventWithContext(ctx, pagerduty.ChangeEvent{
+		RoutingKey: "routing-key",
+		Payload:    pagerduty.ChangeEventPayload{Summary: "deployed v1.2.3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if events := fake.ChangeEvents(); len(events) != 1 || events[0].Payload.Summary != "deployed v1.2.3" {
+		t.Errorf("ChangeEvents() = %+v", events)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	fake.InjectFault(Fault{
+		Method:     http.MethodGet,
+		Path:       "/schedules*",
+		StatusCode: http.StatusForbidden,
+		Code:       2010,
+		Message:    "Access Denied",
+		Times:      1,
+	})
+
+	_, err := client.ListSchedulesWithContext(ctx, pagerduty.ListSchedulesOptions{})
+
+	var aerr pagerduty.APIError
+	if !errors.As(err, &aerr) || aerr.StatusCode != http.StatusForbidden || aerr.APIError.ErrorObject.Code != 2010 {
+		t.Fatalf("ListSchedulesWithContext() error = %v, want injected fault", err)
+	}
+
+	if _, err := client.ListSchedulesWithContext(ctx, pagerduty.ListSchedulesOptions{}); err != nil {
+		t.Errorf("ListSchedulesWithContext() error = %v after the fault expired", err)
+	}
+}
+
+func TestServer_SetRateLimit(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	fake.SetRateLimit(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListEscalationPoliciesWithContext(ctx, pagerduty.ListEscalationPoliciesOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := client.ListEscalationPoliciesWithContext(ctx, pagerduty.ListEscalationPoliciesOptions{})
+
+	var aerr pagerduty.APIError
+	if !errors.As(err, &aerr) || !aerr.RateLimited() {
+		t.Fatalf("ListEscalationPoliciesWithContext() error = %v, want rate limited", err)
+	}
+
+	// the Events API isn't subject to the REST API rate limit
+	_, err = client.CreateChangeEventWithContext(ctx, pagerduty.ChangeEvent{
+		RoutingKey: "routing-key",
+		Payload:    pagerduty.ChangeEventPayload{Summary: "deployed"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServer_OnCalls(t *testing.T) {
+	fake, client := newTestClient(t)
+
+	fake.AddOnCall(pagerduty.OnCall{User: pagerduty.User{APIObject: pagerduty.APIObject{ID: "PU1"}}, EscalationLevel: 1})
+	fake.AddOnCall(pagerduty.OnCall{User: pagerduty.User{APIObject: pagerduty.APIObject{ID: "PU2"}}, EscalationLevel: 2})
+
+	resp, err := client.ListOnCallsWithContext(context.Background(), pagerduty.ListOnCallOptions{UserIDs: []string{"PU2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.OnCalls) != 1 || resp.OnCalls[0].EscalationLevel != 2 {
+		t.Errorf("on-calls = %+v, want PU2's", resp.OnCalls)
+	}
+}
+
+func TestServer_PreviewSchedule(t *testing.T) {
+	_, client := newTestClient(t)
+
+	sched := pagerduty.Schedule{
+		Name: "Primary",
+		ScheduleLayers: []pagerduty.ScheduleLayer{{
+			Start:                     "2026-01-01T00:00:00Z",
+			RotationVirtualStart:      "2026-01-01T00:00:00Z",
+			RotationTurnLengthSeconds: 86400,
+			Users: []pagerduty.UserReference{
+				{User: pagerduty.APIObject{ID: "PU1"}},
+				{User: pagerduty.APIObject{ID: "PU2"}},
+			},
+		}},
+	}
+
+	preview, err := client.PreviewScheduleWithContext(context.Background(), sched, pagerduty.PreviewScheduleOptions{
+		Since: "2026-01-01T00:00:00Z",
+		Until: "2026-01-03T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := preview.FinalSchedule.RenderedScheduleEntries
+	if len(entries) != 2 || entries[0].User.ID != "PU1" || entries[1].User.ID != "PU2" {
+		t.Errorf("entries = %+v, want PU1 then PU2", entries)
+	}
+
+	// invalid schedules are rejected
+	sched.ScheduleLayers[0].RotationTurnLengthSeconds = 0
+	if _, err := client.PreviewScheduleWithContext(context.Background(), sched, pagerduty.PreviewScheduleOptions{}); err == nil {
+		t.Error("PreviewScheduleWithContext() error = nil, want an error for an invalid schedule")
+	}
+}
+
+func TestServer_Overrides(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	sched := fake.AddSchedule(pagerduty.Schedule{Name: "Primary"})
+
+	o, err := client.CreateOverrideWithContext(ctx, sched.ID, pagerduty.Override{
+		Start: "2026-01-01T00:00:00Z",
+		End:   "2026-01-02T00:00:00Z",
+		User:  pagerduty.APIObject{ID: "PU1", Type: "user_reference"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o.ID == "" || o.User.ID != "PU1" {
+		t.Fatalf("created override = %+v, want an ID and user PU1", o)
+	}
+
+	if overrides := fake.Overrides(sched.ID); len(overrides) != 1 || overrides[0].ID != o.ID {
+		t.Errorf("Overrides() = %+v, want the created override", overrides)
+	}
+
+	if err := client.DeleteOverrideWithContext(ctx, sched.ID, o.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if overrides := fake.Overrides(sched.ID); len(overrides) != 0 {
+		t.Errorf("Overrides() = %+v, want none after deleting", overrides)
+	}
+
+	if _, err := client.CreateOverrideWithContext(ctx, "PMISSING", *o); err == nil {
+		t.Error("CreateOverrideWithContext() error = nil, want an error for a missing schedule")
+	}
+}
@@ -0,0 +1,108 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// errStopPaging is returned from a page handler when the consumer of an
+// iterator stops ranging over it, so that no further pages are requested.
+var errStopPaging = errors.New("pagination stopped by consumer")
+
+// offsetSeq returns an iterator over every item of an offset-paginated list
+// endpoint, built on top of pagedGet, whose requests are made as the operation
+// op, the name of the iterator method. The query parameters are taken from o,
+// with the exception of the offset which is managed by the iterator. The page
+// function extracts the items and the pagination information from a decoded
+// response of type R.
+//
+// The iterator yields a non-nil error at most once, after which it stops. If
+// the consumer breaks out of the loop early, no further pages are fetched.
+func offsetSeq[R, T any](ctx context.Context, c *Client, op, path string, o interface{}, page func(*R) ([]T, APIListObject)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		basePath := path
+		if o != nil {
+			v, err := query.Values(o)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			v.Del("offset")
+
+			if q := v.Encode(); q != "" {
+				basePath += "?" + q
+			}
+		}
+
+		responseHandler := func(response *http.Response) (APIListObject, error) {
+			var result R
+			if err := c.decodeJSON(response, &result); err != nil {
+				return APIListObject{}, err
+			}
+
+			items, info := page(&result)
+			for _, item := range items {
+				if !yield(item, nil) {
+					return APIListObject{}, errStopPaging
+				}
+			}
+
+			return info, nil
+		}
+
+		if err := c.pagedGet(ctx, op, basePath, responseHandler); err != nil && !errors.Is(err, errStopPaging) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// cursorSeq is the cursor-based pagination equivalent of offsetSeq, built on
+// top of cursorGet. The query parameter used to pass the cursor to the API is
+// removed from o, as it's managed by the iterator.
+func cursorSeq[R, T any](ctx context.Context, c *Client, op, path, param string, o interface{}, page func(*R) ([]T, cursor)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		basePath := path
+		if o != nil {
+			v, err := query.Values(o)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			v.Del(param)
+
+			if q := v.Encode(); q != "" {
+				basePath += "?" + q
+			}
+		}
+
+		responseHandler := func(response *http.Response) (cursor, error) {
+			var result R
+			if err := c.decodeJSON(response, &result); err != nil {
+				return cursor{}, err
+			}
+
+			items, info := page(&result)
+			for _, item := range items {
+				if !yield(item, nil) {
+					return cursor{}, errStopPaging
+				}
+			}
+
+			return info, nil
+		}
+
+		if err := c.cursorGetWithParam(ctx, op, basePath, param, responseHandler); err != nil && !errors.Is(err, errStopPaging) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
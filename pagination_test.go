@@ -0,0 +1,233 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestPagination_OffsetSeq(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests int
+	mux.HandleFunc("/incidents", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		requests++
+
+		if got := r.URL.Query()["offset"]; len(got) != 1 {
+			t.Fatalf("offset query parameter = %v, want exactly one value", got)
+		}
+
+		if got := r.URL.Query().Get("statuses[]"); got != "triggered" {
+			t.Errorf("statuses[] = %q, want %q", got, "triggered")
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		more := offset < 4
+		_, _ = fmt.Fprintf(w, `{"incidents": [{"id": "%d"}, {"id": "%d"}], "offset": %d, "limit": 2, "more": %t}`, offset, offset+1, offset, more)
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+	opts := ListIncidentsOptions{Offset: 10, Statuses: []string{"triggered"}}
+
+	var got []string
+	for incident, err := range client.Incidents(context.Background(), opts) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, incident.ID)
+	}
+
+	testEqual(t, []string{"0", "1", "2", "3", "4", "5"}, got)
+
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestPagination_OffsetSeqBreak(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests int
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		requests++
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		_, _ = fmt.Fprintf(w, `{"users": [{"id": "%d"}, {"id": "%d"}], "offset": %d, "limit": 2, "more": true}`, offset, offset+1, offset)
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var got []string
+	for user, err := range client.Users(context.Background(), ListUsersOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, user.ID)
+		if len(got) == 3 {
+			break
+		}
+	}
+
+	testEqual(t, []string{"0", "1", "2"}, got)
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestPagination_OffsetSeqError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/schedules", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		if r.URL.Query().Get("offset") != "0" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": {"code": 2001, "message": "boom"}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"schedules": [{"id": "1"}], "offset": 0, "limit": 1, "more": true}`))
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var ids []string
+	var errs []error
+	for schedule, err := range client.Schedules(context.Background(), ListSchedulesOptions{}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, schedule.ID)
+	}
+
+	testEqual(t, []string{"1"}, ids)
+
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+
+	testErrCheck(t, "Schedules()", "boom", errs[0])
+}
+
+func TestPagination_CursorSeq(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/audit/records", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		switch c := r.URL.Query().Get("cursor"); c {
+		case "":
+			_, _ = w.Write([]byte(`{"records": [{"id": "1"}, {"id": "2"}], "next_cursor": "a+b", "limit": 2}`))
+		case "a+b":
+			_, _ = w.Write([]byte(`{"records": [{"id": "3"}], "next_cursor": null, "limit": 2}`))
+		default:
+			t.Fatalf("unexpected cursor %q", c)
+		}
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var got []string
+	for record, err := range client.AuditRecords(context.Background(), ListAuditRecordsOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, record.ID)
+	}
+
+	testEqual(t, []string{"1", "2", "3"}, got)
+}
+
+func TestPagination_AlertGroupingSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/alert_grouping_settings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		switch a := r.URL.Query().Get("after"); a {
+		case "":
+			_, _ = w.Write([]byte(`{"alert_grouping_settings": [{"id": "1", "type": "time", "config": {"timeout": 5}}], "after": "1", "limit": 1}`))
+		case "1":
+			_, _ = w.Write([]byte(`{"alert_grouping_settings": [{"id": "2", "type": "time", "config": {"timeout": 5}}], "limit": 1}`))
+		default:
+			t.Fatalf("unexpected after cursor %q", a)
+		}
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var got []string
+	for s, err := range client.AlertGroupingSettings(context.Background(), ListAlertGroupingSettingsOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s.ID)
+	}
+
+	testEqual(t, []string{"1", "2"}, got)
+}
+
+func TestPagination_AlertGroupingSettingsDecodeError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/alert_grouping_settings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = w.Write([]byte(`{"alert_grouping_settings": [{"id": "1", "type": "time", "config": {"timeout": 5}}, {"id": "2", "type": "time", "config": {"timeout": "5"}}, {"id": "3", "type": "time"}], "after": "3", "limit": 3}`))
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var got []string
+	var errs int
+	for s, err := range client.AlertGroupingSettings(context.Background(), ListAlertGroupingSettingsOptions{}) {
+		if err != nil {
+			testErrCheck(t, "AlertGroupingSettings()", `failed to decode the config of alert grouping setting "2"`, err)
+			errs++
+			continue
+		}
+		got = append(got, s.ID)
+	}
+
+	// the iterator stops after yielding the error
+	testEqual(t, []string{"1"}, got)
+	testEqual(t, 1, errs)
+}
+
+func TestPagination_IncidentNotificationSubscribers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/incidents/1/status_updates/subscribers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		more := offset < 2
+		_, _ = fmt.Fprintf(w, `{"subscribers": [{"subscriber_id": "PU%d", "subscriber_type": "user"}, {"subscriber_id": "PU%d", "subscriber_type": "user"}], "offset": %d, "limit": 2, "more": %t}`, offset, offset+1, offset, more)
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	var got []string
+	for s, err := range client.IncidentNotificationSubscribers(context.Background(), "1") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s.SubscriberID)
+	}
+
+	testEqual(t, []string{"PU0", "PU1", "PU2", "PU3"}, got)
+}
@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"context"
+	"iter"
 
 	"github.com/google/go-querystring/query"
 )
@@ -62,7 +63,7 @@ func (c *Client) ListPrioritiesWithContext(ctx context.Context, o ListPriorities
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/priorities?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListPrioritiesWithContext", "/priorities?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -74,3 +75,11 @@ func (c *Client) ListPrioritiesWithContext(ctx context.Context, o ListPriorities
 
 	return &p, nil
 }
+
+// Priorities returns an iterator over all of the configured priorities,
+// automatically fetching additional pages as needed.
+func (c *Client) Priorities(ctx context.Context, o ListPrioritiesOptions) iter.Seq2[Priority, error] {
+	return offsetSeq(ctx, c, "Priorities", "/priorities", o, func(r *ListPrioritiesResponse) ([]Priority, APIListObject) {
+		return r.Priorities, r.APIListObject
+	})
+}
@@ -0,0 +1,216 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitWindow is the period over which the PagerDuty APIs enforce
+	// their rate limits.
+	rateLimitWindow = time.Minute
+
+	// defaultRESTRateLimit is the number of REST API requests allowed per
+	// rateLimitWindow, until the API tells us otherwise via its headers.
+	defaultRESTRateLimit = 960
+
+	// defaultEventsRateLimit is the number of Events API requests allowed per
+	// rateLimitWindow. The Events API doesn't return rate limit headers.
+	defaultEventsRateLimit = 120
+)
+
+// WithRateLimiter configures the client to pre-emptively throttle its requests
+// to avoid being rate limited by the PagerDuty APIs, instead of only reacting
+// to 429 responses with retries. This is useful when the same *Client is used
+// concurrently to make many API calls, as requests from all goroutines are
+// throttled together.
+//
+// The REST API and the Events API are throttled independently, each using a
+// token bucket. The REST API bucket is kept in sync with the ratelimit-limit,
+// ratelimit-remaining and ratelimit-reset headers of the API responses.
+func WithRateLimiter() ClientOptions {
+	return func(c *Client) {
+		c.rateLimiter = &rateLimiter{
+			rest:   newTokenBucket(defaultRESTRateLimit, rateLimitWindow),
+			events: newTokenBucket(defaultEventsRateLimit, rateLimitWindow),
+		}
+	}
+}
+
+// rateLimiter holds the token buckets used to throttle requests to the
+// different PagerDuty APIs.
+type rateLimiter struct {
+	rest   *tokenBucket
+	events *tokenBucket
+}
+
+// rateLimitBucket returns the token bucket to use for a request. Only requests
+// to the REST API require authentication, which tells them apart from those
+// to the Events API.
+func (c *Client) rateLimitBucket(authRequired bool) *tokenBucket {
+	if c.rateLimiter == nil {
+		return nil
+	}
+
+	if !authRequired {
+		return c.rateLimiter.events
+	}
+
+	return c.rateLimiter.rest
+}
+
+// tokenBucket is a token bucket rate limiter, safe for concurrent use, whose
+// state can be updated from the rate limit headers returned by the API.
+type tokenBucket struct {
+	mu sync.Mutex
+
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens added per second
+
+	// last is when tokens were last added to the bucket.
+	last time.Time
+
+	// notBefore is when the API rate limit resets after it was exhausted. No
+	// tokens can be used until then, at which point the bucket holds the
+	// tokens it was refilled with when the rate limit was exhausted.
+	notBefore time.Time
+
+	now func() time.Time
+}
+
+func newTokenBucket(limit int, window time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(limit),
+		tokens:   float64(limit),
+		rate:     float64(limit) / window.Seconds(),
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill. The caller must
+// hold the lock.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.Before(b.notBefore) {
+		return
+	}
+
+	if !b.notBefore.IsZero() {
+		// the API rate limit has been reset
+		b.last = b.notBefore
+		b.notBefore = time.Time{}
+	}
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+// reserve takes a token from the bucket, returning how long the caller must
+// wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refill(now)
+
+	var delay time.Duration
+	if now.Before(b.notBefore) {
+		delay = b.notBefore.Sub(now)
+	}
+
+	// when the bucket is empty, wait for the token being taken to be added
+	if b.tokens < 1 {
+		delay += time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+
+	b.tokens--
+
+	return delay
+}
+
+// cancel returns a token which was reserved but not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// wait blocks until a token is available, or the context is done. It returns
+// how long it waited for.
+func (b *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	delay := b.reserve()
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return delay, nil
+	case <-ctx.Done():
+		b.cancel()
+		return 0, fmt.Errorf("context completed while waiting for rate limiter: %w", ctx.Err())
+	}
+}
+
+// update synchronizes the bucket with the rate limit state reported by the
+// API response.
+func (b *tokenBucket) update(resp *http.Response) {
+	limit, lerr := strconv.Atoi(resp.Header.Get("ratelimit-limit"))
+	remaining, rerr := strconv.Atoi(resp.Header.Get("ratelimit-remaining"))
+	reset, serr := strconv.Atoi(resp.Header.Get("ratelimit-reset"))
+
+	rateLimited := resp.StatusCode == http.StatusTooManyRequests
+	if lerr != nil && rerr != nil && !rateLimited {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refill(now)
+
+	// responses to requests sent before the rate limit was exhausted may still
+	// be arriving, but the bucket already accounts for the reset
+	if now.Before(b.notBefore) {
+		return
+	}
+
+	if lerr == nil && limit > 0 {
+		b.capacity = float64(limit)
+		b.rate = float64(limit) / rateLimitWindow.Seconds()
+	}
+
+	if rateLimited {
+		remaining, rerr = 0, nil
+	}
+
+	if rerr == nil && float64(remaining) < b.tokens {
+		// the API is the source of truth, but don't hand back tokens which
+		// are reserved by requests that are still in flight
+		b.tokens = float64(remaining)
+	}
+
+	if b.tokens < 1 && serr == nil && reset > 0 {
+		b.notBefore = now.Add(time.Duration(reset) * time.Second)
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,186 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testTokenBucket(limit int, now *time.Time) *tokenBucket {
+	b := newTokenBucket(limit, rateLimitWindow)
+	b.last = *now
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := testTokenBucket(60, &now) // one token per second
+
+	for i := 0; i < 60; i++ {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("reserve() #%d = %s, want 0", i, d)
+		}
+	}
+
+	if d := b.reserve(); d != time.Second {
+		t.Fatalf("reserve() on empty bucket = %s, want 1s", d)
+	}
+
+	if d := b.reserve(); d != 2*time.Second {
+		t.Fatalf("second reserve() on empty bucket = %s, want 2s", d)
+	}
+
+	now = now.Add(10 * time.Second)
+
+	if d := b.reserve(); d != 0 {
+		t.Fatalf("reserve() after refill = %s, want 0", d)
+	}
+}
+
+func TestTokenBucket_Update(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	header := func(limit, remaining, reset string) *http.Response {
+		h := make(http.Header)
+		h.Set("ratelimit-limit", limit)
+		h.Set("ratelimit-remaining", remaining)
+		h.Set("ratelimit-reset", reset)
+		return &http.Response{StatusCode: http.StatusOK, Header: h}
+	}
+
+	t.Run("remaining", func(t *testing.T) {
+		b := testTokenBucket(960, &now)
+		b.update(header("120", "2", "30"))
+
+		if b.capacity != 120 || b.rate != 2 {
+			t.Fatalf("capacity = %v, rate = %v, want 120 and 2", b.capacity, b.rate)
+		}
+
+		for i := 0; i < 2; i++ {
+			if d := b.reserve(); d != 0 {
+				t.Fatalf("reserve() #%d = %s, want 0", i, d)
+			}
+		}
+
+		if d := b.reserve(); d != 500*time.Millisecond {
+			t.Fatalf("reserve() = %s, want 500ms", d)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		b := testTokenBucket(960, &now)
+		b.update(header("960", "0", "12"))
+
+		if d := b.reserve(); d != 12*time.Second {
+			t.Fatalf("reserve() = %s, want 12s", d)
+		}
+
+		// a late response doesn't reset the bucket again
+		b.update(header("960", "0", "12"))
+
+		now = now.Add(12 * time.Second)
+
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("reserve() after reset = %s, want 0", d)
+		}
+
+		if b.tokens != 958 {
+			t.Fatalf("tokens = %v, want 958", b.tokens)
+		}
+	})
+
+	t.Run("rate_limited", func(t *testing.T) {
+		b := testTokenBucket(60, &now)
+		b.update(&http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header)})
+
+		if d := b.reserve(); d != time.Second {
+			t.Fatalf("reserve() = %s, want 1s", d)
+		}
+	})
+
+	t.Run("no_headers", func(t *testing.T) {
+		b := testTokenBucket(60, &now)
+		b.update(&http.Response{StatusCode: http.StatusOK, Header: make(http.Header)})
+
+		if b.tokens != 60 {
+			t.Fatalf("tokens = %v, want 60", b.tokens)
+		}
+	})
+}
+
+func TestTokenBucket_Concurrent(t *testing.T) {
+	b := newTokenBucket(1000, rateLimitWindow)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				if _, err := b.wait(context.Background()); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if b.tokens > 500.5 || b.tokens < 500 {
+		t.Fatalf("tokens = %v, want ~500", b.tokens)
+	}
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var restRequests, eventRequests int
+
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		restRequests++
+
+		w.Header().Set("ratelimit-limit", "960")
+		w.Header().Set("ratelimit-remaining", "0")
+		w.Header().Set("ratelimit-reset", "30")
+		_, _ = w.Write([]byte(`{"user": {"id": "1"}}`))
+	})
+
+	mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		eventRequests++
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status": "success", "dedup_key": "foo"}`))
+	})
+
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithV2EventsAPIEndpoint(server.URL),
+		WithRateLimiter(),
+	)
+
+	if _, err := client.GetUserWithContext(context.Background(), "1", GetUserOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the REST API rate limit is exhausted, so the request is throttled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetUserWithContext(ctx, "1", GetUserOptions{})
+	testErrCheck(t, "GetUserWithContext()", "waiting for rate limiter", err)
+
+	// but the Events API has its own rate limit
+	if _, err := client.ManageEventWithContext(context.Background(), &V2Event{RoutingKey: "abc", Action: "trigger"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if restRequests != 1 || eventRequests != 1 {
+		t.Fatalf("got %d REST and %d Events API requests, want 1 each", restRequests, eventRequests)
+	}
+}
@@ -57,7 +57,7 @@ func (c *Client) ListResponsePlays(ctx context.Context, o ListResponsePlaysOptio
 		"From": o.From,
 	}
 
-	resp, err := c.get(ctx, "/response_plays?"+v.Encode(), h)
+	resp, err := c.get(ctx, "ListResponsePlays", "/response_plays?"+v.Encode(), h)
 	if err != nil {
 		return nil, err
 	}
@@ -76,13 +76,13 @@ func (c *Client) CreateResponsePlay(ctx context.Context, rp ResponsePlay) (Respo
 		"response_play": rp,
 	}
 
-	resp, err := c.post(ctx, "/response_plays", d, nil)
+	resp, err := c.post(ctx, "CreateResponsePlay", "/response_plays", d, nil)
 	return getResponsePlayFromResponse(c, resp, err)
 }
 
 // GetResponsePlay gets details about an existing response play.
 func (c *Client) GetResponsePlay(ctx context.Context, id string) (ResponsePlay, error) {
-	resp, err := c.get(ctx, "/response_plays/"+id, nil)
+	resp, err := c.get(ctx, "GetResponsePlay", "/response_plays/"+id, nil)
 	return getResponsePlayFromResponse(c, resp, err)
 }
 
@@ -92,13 +92,13 @@ func (c *Client) UpdateResponsePlay(ctx context.Context, rp ResponsePlay) (Respo
 		"response_play": rp,
 	}
 
-	resp, err := c.put(ctx, "/response_plays/"+rp.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateResponsePlay", "/response_plays/"+rp.ID, d, nil)
 	return getResponsePlayFromResponse(c, resp, err)
 }
 
 // DeleteResponsePlay deletes an existing response play.
 func (c *Client) DeleteResponsePlay(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/response_plays/"+id)
+	_, err := c.delete(ctx, "DeleteResponsePlay", "/response_plays/"+id)
 	return err
 }
 
@@ -115,7 +115,7 @@ func (c *Client) RunResponsePlay(ctx context.Context, from string, responsePlayI
 		"From": from,
 	}
 
-	resp, err := c.post(ctx, "/response_plays/"+responsePlayID+"/run", d, h)
+	resp, err := c.post(ctx, "RunResponsePlay", "/response_plays/"+responsePlayID+"/run", d, h)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,275 @@
+package pagerduty
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryStrategy decides whether a request to the PagerDuty API should be
+// retried, and if so how long to wait before the next attempt. It can be
+// configured on the client using the WithRetryStrategy() option.
+//
+// The attempt number starts at 0 for the first attempt. Either resp or err may
+// be nil, depending on whether the attempt resulted in an HTTP response. A
+// RetryStrategy may be called from multiple goroutines at the same time.
+type RetryStrategy interface {
+	Retry(req *http.Request, resp *http.Response, err error, attempt int) (retry bool, delay time.Duration)
+}
+
+// FullJitterRetryStrategy is a RetryStrategy using an exponential backoff with
+// full jitter, where the delay before each retry is chosen randomly between
+// zero and min(MaxDelay, BaseDelay * 2^attempt). A BaseDelay of zero means
+// one second, and a MaxDelay of zero means the delay isn't capped.
+//
+// Requests are only retried when they fail with a network error, are rate
+// limited, or receive a 5xx response. Unless RetryNonIdempotent is set, only
+// rate limited requests are retried for non-idempotent requests (see
+// IsIdempotentRequest), as retrying them may result in the action being
+// performed more than once.
+//
+// If the API response indicates how long to wait, using either the
+// ratelimit-reset or Retry-After headers, that delay is used instead, capped
+// to MaxDelay.
+type FullJitterRetryStrategy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// RetryNonIdempotent allows non-idempotent requests, such as POST requests
+	// or merging incidents, to be retried after network errors and 5xx
+	// responses.
+	RetryNonIdempotent bool
+}
+
+var _ RetryStrategy = (*FullJitterRetryStrategy)(nil) // assert it satisfies the RetryStrategy interface.
+
+// Retry satisfies the RetryStrategy interface.
+func (s *FullJitterRetryStrategy) Retry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= s.MaxRetries || !retryable(req, resp, err, s.RetryNonIdempotent) {
+		return false, 0
+	}
+
+	if delay, ok := serverRetryDelay(resp, s.MaxDelay); ok {
+		return true, delay
+	}
+
+	return true, randomDelay(0, exponentialDelay(retryBaseDelay(s.BaseDelay), s.MaxDelay, attempt))
+}
+
+// DecorrelatedJitterRetryStrategy is a RetryStrategy using the decorrelated
+// jitter backoff algorithm, where each delay is chosen randomly between
+// BaseDelay and three times the previous delay, capped to MaxDelay unless it's
+// zero. A BaseDelay of zero means one second. This tends to spread
+// retries from concurrent clients out more than FullJitterRetryStrategy.
+//
+// The conditions under which requests are retried are the same as for
+// FullJitterRetryStrategy.
+type DecorrelatedJitterRetryStrategy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// RetryNonIdempotent allows non-idempotent requests, such as POST requests
+	// or merging incidents, to be retried after network errors and 5xx
+	// responses.
+	RetryNonIdempotent bool
+}
+
+var _ RetryStrategy = (*DecorrelatedJitterRetryStrategy)(nil) // assert it satisfies the RetryStrategy interface.
+
+// Retry satisfies the RetryStrategy interface.
+func (s *DecorrelatedJitterRetryStrategy) Retry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= s.MaxRetries || !retryable(req, resp, err, s.RetryNonIdempotent) {
+		return false, 0
+	}
+
+	if delay, ok := serverRetryDelay(resp, s.MaxDelay); ok {
+		return true, delay
+	}
+
+	// The strategy doesn't keep any state between calls, so that it can be
+	// shared by concurrent requests, and as such walks the chain of delays up
+	// to the current attempt.
+	base := retryBaseDelay(s.BaseDelay)
+	delay := base
+	for i := 0; i <= attempt; i++ {
+		delay = randomDelay(base, 3*delay)
+		if s.MaxDelay > 0 && delay > s.MaxDelay {
+			delay = s.MaxDelay
+		}
+	}
+
+	return true, delay
+}
+
+// defaultRetryBaseDelay is the BaseDelay of the retry strategies when it's not
+// set, so that a zero value doesn't mean retrying without any backoff.
+const defaultRetryBaseDelay = time.Second
+
+func retryBaseDelay(base time.Duration) time.Duration {
+	if base <= 0 {
+		return defaultRetryBaseDelay
+	}
+
+	return base
+}
+
+// retryable reports whether the outcome of an attempt may be retried.
+func retryable(req *http.Request, resp *http.Response, err error, nonIdempotent bool) bool {
+	// rate limited requests weren't actioned by the API, so are always safe to
+	// retry regardless of their method
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if err == nil && resp.StatusCode < 500 {
+		return false
+	}
+
+	return nonIdempotent || req == nil || IsIdempotentRequest(req)
+}
+
+// retryPolicy is the RetryStrategy configured by WithRetryPolicy. It retries
+// rate limited requests, and idempotent requests after network errors and 5xx
+// responses, waiting 2^attempt seconds capped to MaxDelay, or as long as the
+// ratelimit-reset header of rate limited responses asks.
+type retryPolicy struct {
+	MaxRetries int
+	MaxDelay   time.Duration
+}
+
+var _ RetryStrategy = retryPolicy{} // assert it satisfies the RetryStrategy interface.
+
+// Retry satisfies the RetryStrategy interface.
+func (p retryPolicy) Retry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= p.MaxRetries || !retryable(req, resp, err, false) {
+		return false, 0
+	}
+
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("ratelimit-reset")); err == nil {
+			return true, time.Duration(seconds) * time.Second
+		}
+	}
+
+	return true, p.delay(attempt)
+}
+
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := exponentialDelay(time.Second, 0, attempt)
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+type nonIdempotentCtxKey struct{}
+
+// withNonIdempotent returns a context marking the requests made with it as not
+// idempotent, for API operations which use an idempotent HTTP method but
+// aren't safe to perform more than once (e.g., merging incidents).
+func withNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonIdempotentCtxKey{}, true)
+}
+
+type noRetryCtxKey struct{}
+
+// withoutRetries returns a context disabling the retry strategy of the client
+// for the requests made with it, for callers which retry failed requests
+// themselves (e.g., EventSender).
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryCtxKey{}, true)
+}
+
+// IsIdempotentRequest reports whether the request made by the client can
+// safely be sent to the API more than once. This is based on the HTTP method
+// of the request, except for API operations the client knows not to be
+// idempotent despite their method, like MergeIncidentsWithContext.
+//
+// This is meant to help implementing a custom RetryStrategy.
+func IsIdempotentRequest(req *http.Request) bool {
+	if v, _ := req.Context().Value(nonIdempotentCtxKey{}).(bool); v {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// serverRetryDelay returns how long the API asked us to wait before retrying,
+// using the ratelimit-reset header returned by the REST API when rate
+// limiting, or the standard Retry-After header. The delay is capped to max,
+// unless it's zero.
+func serverRetryDelay(resp *http.Response, max time.Duration) (time.Duration, bool) {
+	delay, ok := parseServerRetryDelay(resp)
+	if ok && max > 0 && delay > max {
+		delay = max
+	}
+
+	return delay, ok
+}
+
+func parseServerRetryDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("ratelimit-reset")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	ra := resp.Header.Get("Retry-After")
+	if ra == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(ra); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(ra); err == nil {
+		delay := time.Until(t)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// exponentialDelay returns base * 2^attempt, capped to max unless it's zero.
+func exponentialDelay(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := 0; i < attempt && (max <= 0 || delay < max) && delay <= math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+
+	if max > 0 && delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+// randomDelay returns a random duration in the range [min, max).
+func randomDelay(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+
+	return min + rand.N(max-min)
+}
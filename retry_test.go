@@ -0,0 +1,258 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testRetryResponse(status int, header map[string]string) *http.Response {
+	h := make(http.Header)
+	for k, v := range header {
+		h.Set(k, v)
+	}
+
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestRetryStrategy_Retry(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/incidents", nil)
+	put, _ := http.NewRequest(http.MethodPut, "https://api.pagerduty.com/incidents/1/merge", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://api.pagerduty.com/incidents", nil)
+	errNetwork := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name          string
+		req           *http.Request
+		resp          *http.Response
+		err           error
+		attempt       int
+		nonIdempotent bool
+		want          bool
+	}{
+		{name: "get_server_error", req: get, resp: testRetryResponse(500, nil), want: true},
+		{name: "get_network_error", req: get, err: errNetwork, want: true},
+		{name: "get_rate_limited", req: get, resp: testRetryResponse(429, nil), want: true},
+		{name: "get_bad_request", req: get, resp: testRetryResponse(400, nil), want: false},
+		{name: "get_ok", req: get, resp: testRetryResponse(200, nil), want: false},
+		{name: "get_max_retries", req: get, resp: testRetryResponse(500, nil), attempt: 3, want: false},
+		{name: "put_server_error", req: put, resp: testRetryResponse(503, nil), want: true},
+		{name: "post_server_error", req: post, resp: testRetryResponse(500, nil), want: false},
+		{name: "post_network_error", req: post, err: errNetwork, want: false},
+		{name: "post_rate_limited", req: post, resp: testRetryResponse(429, nil), want: true},
+		{name: "post_server_error_opt_in", req: post, resp: testRetryResponse(500, nil), nonIdempotent: true, want: true},
+		{name: "post_network_error_opt_in", req: post, err: errNetwork, nonIdempotent: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategies := map[string]RetryStrategy{
+				"full_jitter": &FullJitterRetryStrategy{
+					MaxRetries: 3, BaseDelay: time.Second, MaxDelay: 20 * time.Second, RetryNonIdempotent: tt.nonIdempotent,
+				},
+				"decorrelated_jitter": &DecorrelatedJitterRetryStrategy{
+					MaxRetries: 3, BaseDelay: time.Second, MaxDelay: 20 * time.Second, RetryNonIdempotent: tt.nonIdempotent,
+				},
+			}
+
+			for name, s := range strategies {
+				got, _ := s.Retry(tt.req, tt.resp, tt.err, tt.attempt)
+				if got != tt.want {
+					t.Errorf("%s: Retry() = %t, want %t", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryStrategy_Delay(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/incidents", nil)
+	resp := testRetryResponse(500, nil)
+
+	full := &FullJitterRetryStrategy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	decorrelated := &DecorrelatedJitterRetryStrategy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		for i := 0; i < 100; i++ {
+			_, delay := full.Retry(req, resp, nil, attempt)
+			if ceiling := exponentialDelay(time.Second, 5*time.Second, attempt); delay < 0 || delay >= ceiling {
+				t.Fatalf("full jitter attempt %d: delay %s not within [0, %s)", attempt, delay, ceiling)
+			}
+
+			_, delay = decorrelated.Retry(req, resp, nil, attempt)
+			if delay < time.Second || delay > 5*time.Second {
+				t.Fatalf("decorrelated jitter attempt %d: delay %s not within [1s, 5s]", attempt, delay)
+			}
+		}
+	}
+}
+
+func TestRetryStrategy_NoMaxDelay(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/incidents", nil)
+
+	testEqual(t, 8*time.Second, exponentialDelay(time.Second, 0, 3))
+	if delay := exponentialDelay(time.Second, 0, 100); delay < time.Duration(math.MaxInt64/2) {
+		t.Errorf("exponentialDelay() = %s, want it not to overflow", delay)
+	}
+
+	decorrelated := &DecorrelatedJitterRetryStrategy{MaxRetries: 10, BaseDelay: time.Second}
+	if _, delay := decorrelated.Retry(req, testRetryResponse(500, nil), nil, 3); delay < time.Second {
+		t.Errorf("decorrelated jitter delay = %s, want at least 1s", delay)
+	}
+
+	full := &FullJitterRetryStrategy{MaxRetries: 1, BaseDelay: time.Second}
+	_, delay := full.Retry(req, testRetryResponse(http.StatusTooManyRequests, map[string]string{"ratelimit-reset": "3600"}), nil, 0)
+	testEqual(t, time.Hour, delay)
+}
+
+func TestRetryStrategy_DefaultBaseDelay(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/incidents", nil)
+	resp := testRetryResponse(500, nil)
+
+	full := &FullJitterRetryStrategy{MaxRetries: 10}
+	decorrelated := &DecorrelatedJitterRetryStrategy{MaxRetries: 10}
+
+	// without a BaseDelay, the retries still back off
+	for i := 0; i < 100; i++ {
+		if _, delay := full.Retry(req, resp, nil, 3); delay >= 8*time.Second {
+			t.Fatalf("full jitter delay %s not within [0, 8s)", delay)
+		}
+
+		if _, delay := decorrelated.Retry(req, resp, nil, 0); delay < time.Second || delay >= 3*time.Second {
+			t.Fatalf("decorrelated jitter delay %s not within [1s, 3s)", delay)
+		}
+	}
+
+	var total time.Duration
+	for i := 0; i < 100; i++ {
+		_, delay := full.Retry(req, resp, nil, 3)
+		total += delay
+	}
+
+	if total == 0 {
+		t.Error("full jitter delays are all zero, want a backoff")
+	}
+}
+
+func TestRetryPolicy_Retry(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/incidents", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://api.pagerduty.com/incidents", nil)
+	merge, _ := http.NewRequestWithContext(withNonIdempotent(context.Background()), http.MethodPut, "https://api.pagerduty.com/incidents/1/merge", nil)
+	policy := retryPolicy{MaxRetries: 3, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		resp    *http.Response
+		err     error
+		attempt int
+		retry   bool
+		delay   time.Duration
+	}{
+		{name: "server_error", req: get, resp: testRetryResponse(500, nil), retry: true, delay: time.Second},
+		{name: "network_error", req: get, err: errors.New("connection reset by peer"), attempt: 1, retry: true, delay: 2 * time.Second},
+		{name: "capped", req: get, resp: testRetryResponse(502, nil), attempt: 2, retry: true, delay: 4 * time.Second},
+		{name: "rate_limited", req: get, resp: testRetryResponse(429, map[string]string{"ratelimit-reset": "30"}), retry: true, delay: 30 * time.Second},
+		{name: "bad_request", req: get, resp: testRetryResponse(400, nil), retry: false},
+		{name: "max_retries", req: get, resp: testRetryResponse(500, nil), attempt: 3, retry: false},
+
+		// non-idempotent requests are only retried when rate limited
+		{name: "post_server_error", req: post, resp: testRetryResponse(500, nil), retry: false},
+		{name: "post_network_error", req: post, err: errors.New("connection reset by peer"), retry: false},
+		{name: "post_rate_limited", req: post, resp: testRetryResponse(429, nil), retry: true, delay: time.Second},
+		{name: "merge_server_error", req: merge, resp: testRetryResponse(503, nil), retry: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, delay := policy.Retry(tt.req, tt.resp, tt.err, tt.attempt)
+			testEqual(t, tt.retry, retry)
+			testEqual(t, tt.delay, delay)
+		})
+	}
+
+	_, delay := retryPolicy{MaxRetries: 10, MaxDelay: 5 * time.Second}.Retry(get, testRetryResponse(500, nil), nil, 6)
+	testEqual(t, 5*time.Second, delay)
+}
+
+func TestRetryStrategy_ServerDelay(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://events.pagerduty.com/v2/enqueue", nil)
+	strategy := &FullJitterRetryStrategy{MaxRetries: 1, BaseDelay: time.Second, MaxDelay: 2 * time.Minute}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{name: "ratelimit_reset", header: map[string]string{"ratelimit-reset": "42"}, min: 42 * time.Second, max: 42 * time.Second},
+		{name: "retry_after_seconds", header: map[string]string{"Retry-After": "7"}, min: 7 * time.Second, max: 7 * time.Second},
+		{
+			name:   "retry_after_date",
+			header: map[string]string{"Retry-After": time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)},
+			min:    58 * time.Second,
+			max:    time.Minute,
+		},
+		{name: "capped", header: map[string]string{"ratelimit-reset": "3600"}, min: 2 * time.Minute, max: 2 * time.Minute},
+		{name: "retry_after_past_date", header: map[string]string{"Retry-After": "Wed, 21 Oct 2015 07:28:00 GMT"}, min: 0, max: 0},
+		{name: "retry_after_invalid", header: map[string]string{"Retry-After": "soon"}, min: 0, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, delay := strategy.Retry(req, testRetryResponse(http.StatusTooManyRequests, tt.header), nil, 0)
+			if !retry {
+				t.Fatal("Retry() = false, want true")
+			}
+
+			if delay < tt.min || delay > tt.max {
+				t.Fatalf("delay = %s, want within [%s, %s]", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestClient_RetryStrategyNonIdempotent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	attempts := make(map[string]int)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method+" "+r.URL.Path]++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"message":"oops"}}`))
+	}
+
+	mux.HandleFunc("/incidents", handler)
+	mux.HandleFunc("/incidents/1", handler)
+	mux.HandleFunc("/incidents/1/merge", handler)
+
+	client := NewClient("foo",
+		WithAPIEndpoint(server.URL),
+		WithRetryStrategy(&FullJitterRetryStrategy{MaxRetries: 2, BaseDelay: time.Millisecond}),
+	)
+
+	ctx := context.Background()
+
+	_, err := client.CreateIncidentWithContext(ctx, "foo@example.com", &CreateIncidentOptions{Title: "foo"})
+	testErrCheck(t, "CreateIncidentWithContext()", "status code 500", err)
+
+	_, err = client.MergeIncidentsWithContext(ctx, "foo@example.com", "1", []MergeIncidentsOptions{{ID: "2", Type: "incident"}})
+	testErrCheck(t, "MergeIncidentsWithContext()", "status code 500", err)
+
+	_, err = client.GetIncidentWithContext(ctx, "1")
+	testErrCheck(t, "GetIncidentWithContext()", "status code 500", err)
+
+	want := map[string]int{
+		"POST /incidents":        1,
+		"PUT /incidents/1/merge": 1,
+		"GET /incidents/1":       3,
+	}
+
+	testEqual(t, want, attempts)
+}
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -195,13 +196,21 @@ func (c *Client) ListRulesetsPaginated(ctx context.Context) ([]*Ruleset, error)
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/rulesets/", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListRulesetsPaginated", "/rulesets/", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return rulesets, nil
 }
 
+// Rulesets returns an iterator over all of the rulesets, automatically
+// fetching additional pages as needed.
+func (c *Client) Rulesets(ctx context.Context) iter.Seq2[*Ruleset, error] {
+	return offsetSeq(ctx, c, "Rulesets", "/rulesets/", nil, func(r *ListRulesetsResponse) ([]*Ruleset, APIListObject) {
+		return r.Rulesets, APIListObject{Limit: r.Limit, Offset: r.Offset, More: r.More}
+	})
+}
+
 // CreateRuleset creates a new ruleset.
 //
 // Deprecated: Use CreateRulesetWithContext instead.
@@ -215,7 +224,7 @@ func (c *Client) CreateRulesetWithContext(ctx context.Context, r *Ruleset) (*Rul
 		"ruleset": r,
 	}
 
-	resp, err := c.post(ctx, "/rulesets", d, nil)
+	resp, err := c.post(ctx, "CreateRulesetWithContext", "/rulesets", d, nil)
 	return getRulesetFromResponse(c, resp, err)
 }
 
@@ -228,7 +237,7 @@ func (c *Client) DeleteRuleset(id string) error {
 
 // DeleteRulesetWithContext deletes a ruleset.
 func (c *Client) DeleteRulesetWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/rulesets/"+id)
+	_, err := c.delete(ctx, "DeleteRulesetWithContext", "/rulesets/"+id)
 	return err
 }
 
@@ -241,7 +250,7 @@ func (c *Client) GetRuleset(id string) (*Ruleset, error) {
 
 // GetRulesetWithContext gets details about a ruleset.
 func (c *Client) GetRulesetWithContext(ctx context.Context, id string) (*Ruleset, error) {
-	resp, err := c.get(ctx, "/rulesets/"+id, nil)
+	resp, err := c.get(ctx, "GetRulesetWithContext", "/rulesets/"+id, nil)
 	return getRulesetFromResponse(c, resp, err)
 }
 
@@ -258,7 +267,7 @@ func (c *Client) UpdateRulesetWithContext(ctx context.Context, r *Ruleset) (*Rul
 		"ruleset": r,
 	}
 
-	resp, err := c.put(ctx, "/rulesets/"+r.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateRulesetWithContext", "/rulesets/"+r.ID, d, nil)
 	return getRulesetFromResponse(c, resp, err)
 }
 
@@ -319,13 +328,21 @@ func (c *Client) ListRulesetRulesPaginated(ctx context.Context, rulesetID string
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/rulesets/"+rulesetID+"/rules", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListRulesetRulesPaginated", "/rulesets/"+rulesetID+"/rules", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return rules, nil
 }
 
+// RulesetRules returns an iterator over all of the rules for a ruleset,
+// automatically fetching additional pages as needed.
+func (c *Client) RulesetRules(ctx context.Context, rulesetID string) iter.Seq2[*RulesetRule, error] {
+	return offsetSeq(ctx, c, "RulesetRules", "/rulesets/"+rulesetID+"/rules", nil, func(r *ListRulesetRulesResponse) ([]*RulesetRule, APIListObject) {
+		return r.Rules, APIListObject{Limit: r.Limit, Offset: r.Offset, More: r.More}
+	})
+}
+
 // GetRulesetRule gets an event rule.
 //
 // Deprecated: Use GetRulesetRuleWithContext instead.
@@ -335,7 +352,7 @@ func (c *Client) GetRulesetRule(rulesetID, ruleID string) (*RulesetRule, error)
 
 // GetRulesetRuleWithContext gets an event rule
 func (c *Client) GetRulesetRuleWithContext(ctx context.Context, rulesetID, ruleID string) (*RulesetRule, error) {
-	resp, err := c.get(ctx, "/rulesets/"+rulesetID+"/rules/"+ruleID, nil)
+	resp, err := c.get(ctx, "GetRulesetRuleWithContext", "/rulesets/"+rulesetID+"/rules/"+ruleID, nil)
 	return getRuleFromResponse(c, resp, err)
 }
 
@@ -348,7 +365,7 @@ func (c *Client) DeleteRulesetRule(rulesetID, ruleID string) error {
 
 // DeleteRulesetRuleWithContext deletes a rule.
 func (c *Client) DeleteRulesetRuleWithContext(ctx context.Context, rulesetID, ruleID string) error {
-	_, err := c.delete(ctx, "/rulesets/"+rulesetID+"/rules/"+ruleID)
+	_, err := c.delete(ctx, "DeleteRulesetRuleWithContext", "/rulesets/"+rulesetID+"/rules/"+ruleID)
 	return err
 }
 
@@ -365,7 +382,7 @@ func (c *Client) CreateRulesetRuleWithContext(ctx context.Context, rulesetID str
 		"rule": rule,
 	}
 
-	resp, err := c.post(ctx, "/rulesets/"+rulesetID+"/rules/", d, nil)
+	resp, err := c.post(ctx, "CreateRulesetRuleWithContext", "/rulesets/"+rulesetID+"/rules/", d, nil)
 	return getRuleFromResponse(c, resp, err)
 }
 
@@ -382,7 +399,7 @@ func (c *Client) UpdateRulesetRuleWithContext(ctx context.Context, rulesetID, ru
 		"rule": r,
 	}
 
-	resp, err := c.put(ctx, "/rulesets/"+rulesetID+"/rules/"+ruleID, d, nil)
+	resp, err := c.put(ctx, "UpdateRulesetRuleWithContext", "/rulesets/"+rulesetID+"/rules/"+ruleID, d, nil)
 	return getRuleFromResponse(c, resp, err)
 }
 
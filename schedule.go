@@ -3,7 +3,9 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -18,8 +20,8 @@ type Restriction struct {
 
 // RenderedScheduleEntry represents the computed set of schedule layer entries that put users on call for a schedule, and cannot be modified directly.
 type RenderedScheduleEntry struct {
-	Start string    `json:"start,omitempty"`
-	End   string    `json:"end,omitempty"`
+	Start Time      `json:"start,omitempty"`
+	End   Time      `json:"end,omitempty"`
 	User  APIObject `json:"user,omitempty"`
 }
 
@@ -101,7 +103,7 @@ func (c *Client) ListSchedulesWithContext(ctx context.Context, o ListSchedulesOp
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/schedules?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListSchedulesWithContext", "/schedules?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +116,14 @@ func (c *Client) ListSchedulesWithContext(ctx context.Context, o ListSchedulesOp
 	return &result, nil
 }
 
+// Schedules returns an iterator over all of the on-call schedules matching
+// the options, automatically fetching additional pages as needed.
+func (c *Client) Schedules(ctx context.Context, o ListSchedulesOptions) iter.Seq2[Schedule, error] {
+	return offsetSeq(ctx, c, "Schedules", "/schedules", o, func(r *ListSchedulesResponse) ([]Schedule, APIListObject) {
+		return r.Schedules, r.APIListObject
+	})
+}
+
 // CreateSchedule creates a new on-call schedule.
 //
 // Deprecated: Use CreateScheduleWithContext instead.
@@ -127,7 +137,7 @@ func (c *Client) CreateScheduleWithContext(ctx context.Context, s Schedule) (*Sc
 		"schedule": s,
 	}
 
-	resp, err := c.post(ctx, "/schedules", d, nil)
+	resp, err := c.post(ctx, "CreateScheduleWithContext", "/schedules", d, nil)
 	return getScheduleFromResponse(c, resp, err)
 }
 
@@ -143,25 +153,28 @@ type PreviewScheduleOptions struct {
 //
 // Deprecated: Use PreviewScheduleWithContext instead.
 func (c *Client) PreviewSchedule(s Schedule, o PreviewScheduleOptions) error {
-	return c.PreviewScheduleWithContext(context.Background(), s, o)
+	_, err := c.PreviewScheduleWithContext(context.Background(), s, o)
+	return err
 }
 
 // PreviewScheduleWithContext previews what an on-call schedule would look like
-// without saving it. Nothing is returned from this method, because the API
-// should return the Schedule as we posted it. If this method call returns no
-// error, the schedule should be valid and can be updated.
-func (c *Client) PreviewScheduleWithContext(ctx context.Context, s Schedule, o PreviewScheduleOptions) error {
+// without saving it. It returns the schedule as rendered by the API, including
+// the RenderedScheduleEntries and RenderedCoveragePercentage fields of its
+// layers and final schedule between the since and until options. If this
+// method call returns no error, the schedule is valid and can be created or
+// updated.
+func (c *Client) PreviewScheduleWithContext(ctx context.Context, s Schedule, o PreviewScheduleOptions) (*Schedule, error) {
 	v, err := query.Values(o)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	d := map[string]Schedule{
 		"schedule": s,
 	}
 
-	_, err = c.post(ctx, "/schedules/preview?"+v.Encode(), d, nil)
-	return err
+	resp, err := c.post(ctx, "PreviewScheduleWithContext", "/schedules/preview?"+v.Encode(), d, nil)
+	return getScheduleFromResponse(c, resp, err)
 }
 
 // DeleteSchedule deletes an on-call schedule.
@@ -173,7 +186,7 @@ func (c *Client) DeleteSchedule(id string) error {
 
 // DeleteScheduleWithContext deletes an on-call schedule.
 func (c *Client) DeleteScheduleWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/schedules/"+id)
+	_, err := c.delete(ctx, "DeleteScheduleWithContext", "/schedules/"+id)
 	return err
 }
 
@@ -200,7 +213,7 @@ func (c *Client) GetScheduleWithContext(ctx context.Context, id string, o GetSch
 		return nil, fmt.Errorf("Could not parse values for query: %v", err)
 	}
 
-	resp, err := c.get(ctx, "/schedules/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetScheduleWithContext", "/schedules/"+id+"?"+v.Encode(), nil)
 	return getScheduleFromResponse(c, resp, err)
 }
 
@@ -222,7 +235,7 @@ func (c *Client) UpdateScheduleWithContext(ctx context.Context, id string, s Sch
 		"schedule": s,
 	}
 
-	resp, err := c.put(ctx, "/schedules/"+id, d, nil)
+	resp, err := c.put(ctx, "UpdateScheduleWithContext", "/schedules/"+id, d, nil)
 	return getScheduleFromResponse(c, resp, err)
 }
 
@@ -251,6 +264,18 @@ type Override struct {
 	User    APIObject `json:"user,omitempty"`
 }
 
+// StartTime parses Start, returning the zero time.Time if it's unset.
+func (o Override) StartTime() (time.Time, error) {
+	t, err := ParseTime(o.Start)
+	return t.Time, err
+}
+
+// EndTime parses End, returning the zero time.Time if it's unset.
+func (o Override) EndTime() (time.Time, error) {
+	t, err := ParseTime(o.End)
+	return t.Time, err
+}
+
 // ListOverrides lists overrides for a given time range.
 //
 // Deprecated: Use ListOverridesWithContext instead.
@@ -265,7 +290,7 @@ func (c *Client) ListOverridesWithContext(ctx context.Context, id string, o List
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/schedules/"+id+"/overrides?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListOverridesWithContext", "/schedules/"+id+"/overrides?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -293,7 +318,7 @@ func (c *Client) CreateOverrideWithContext(ctx context.Context, id string, o Ove
 		"override": o,
 	}
 
-	resp, err := c.post(ctx, "/schedules/"+id+"/overrides", d, nil)
+	resp, err := c.post(ctx, "CreateOverrideWithContext", "/schedules/"+id+"/overrides", d, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +335,7 @@ func (c *Client) DeleteOverride(scheduleID, overrideID string) error {
 
 // DeleteOverrideWithContext removes an override.
 func (c *Client) DeleteOverrideWithContext(ctx context.Context, scheduleID, overrideID string) error {
-	_, err := c.delete(ctx, "/schedules/"+scheduleID+"/overrides/"+overrideID)
+	_, err := c.delete(ctx, "DeleteOverrideWithContext", "/schedules/"+scheduleID+"/overrides/"+overrideID)
 	return err
 }
 
@@ -336,7 +361,7 @@ func (c *Client) ListOnCallUsersWithContext(ctx context.Context, id string, o Li
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/schedules/"+id+"/users?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListOnCallUsersWithContext", "/schedules/"+id+"/users?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
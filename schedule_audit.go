@@ -0,0 +1,387 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduleGap is a time interval during which nobody is on call for a
+// schedule.
+type ScheduleGap struct {
+	Schedule APIObject
+	Start    time.Time
+	End      time.Time
+}
+
+// ScheduleDoubleBooking is a time interval during which a user is on call for
+// two schedules at once.
+type ScheduleDoubleBooking struct {
+	User      APIObject
+	Start     time.Time
+	End       time.Time
+	Schedules []APIObject
+}
+
+// ScheduleOverrideConflict is a time interval during which a user is on call
+// for a schedule, while also covering another schedule using an override.
+type ScheduleOverrideConflict struct {
+	User             APIObject
+	Start            time.Time
+	End              time.Time
+	Schedule         APIObject
+	OverrideSchedule APIObject
+}
+
+// EscalationPolicyGap is a time interval during which nobody is on call for a
+// rule of an escalation policy, as all of the rule's targets are schedules
+// with a gap.
+type EscalationPolicyGap struct {
+	EscalationPolicy APIObject
+
+	// Level is the level of the rule, starting at 1 for the first rule.
+	Level uint
+
+	Start time.Time
+	End   time.Time
+}
+
+// ScheduleAudit is the result of auditing schedules using AuditSchedules.
+type ScheduleAudit struct {
+	Gaps                 []ScheduleGap
+	DoubleBookings       []ScheduleDoubleBooking
+	OverrideConflicts    []ScheduleOverrideConflict
+	EscalationPolicyGaps []EscalationPolicyGap
+}
+
+// Empty returns whether the audit found no issues.
+func (a *ScheduleAudit) Empty() bool {
+	return len(a.Gaps) == 0 && len(a.DoubleBookings) == 0 && len(a.OverrideConflicts) == 0 && len(a.EscalationPolicyGaps) == 0
+}
+
+// AuditSchedulesOptions is the data structure used when calling
+// AuditSchedules.
+type AuditSchedulesOptions struct {
+	// Since and Until are the start and end of the time window to audit, which
+	// the rendered entries of the schedules must cover.
+	Since time.Time
+	Until time.Time
+
+	// EscalationPolicies are the escalation policies whose rules are checked
+	// for gaps. Rules targeting users, or schedules which aren't audited, are
+	// considered covered.
+	EscalationPolicies []EscalationPolicy
+}
+
+// AuditSchedules finds coverage issues in the schedules during the time
+// window, using the rendered entries of their final schedule and override
+// sub-schedule, such as returned by GetScheduleWithContext or RenderSchedule.
+// It reports:
+//
+//   - the gaps during which nobody is on call for a schedule
+//   - the users on call for two schedules at once
+//   - the users on call for a schedule while covering another one using an
+//     override, which aren't also reported as double bookings
+//   - the gaps during which nobody is on call for a rule of the escalation
+//     policies
+func AuditSchedules(schedules []Schedule, o AuditSchedulesOptions) (*ScheduleAudit, error) {
+	if o.Since.IsZero() || o.Until.IsZero() || !o.Since.Before(o.Until) {
+		return nil, errors.New("the time window to audit must have a start before its end")
+	}
+
+	audit := &ScheduleAudit{}
+
+	gapsByID := make(map[string][]scheduleSpan, len(schedules))
+
+	// the pieces of each user's on-call time, across all schedules
+	var pieces []auditPiece
+
+	for i, s := range schedules {
+		final, err := entrySpans(s.FinalSchedule.RenderedScheduleEntries, o.Since, o.Until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid final schedule of schedule %s: %w", s.ID, err)
+		}
+
+		overrides, err := entrySpans(s.OverrideSubschedule.RenderedScheduleEntries, o.Since, o.Until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid override sub-schedule of schedule %s: %w", s.ID, err)
+		}
+
+		gaps := spanGaps(final, o.Since, o.Until)
+		gapsByID[s.ID] = gaps
+
+		for _, g := range gaps {
+			audit.Gaps = append(audit.Gaps, ScheduleGap{Schedule: scheduleReference(s), Start: g.start, End: g.end})
+		}
+
+		for _, span := range final {
+			for _, p := range subtractUserSpans(span, overrides) {
+				pieces = append(pieces, auditPiece{schedule: i, span: p})
+			}
+		}
+
+		for _, span := range overrides {
+			pieces = append(pieces, auditPiece{schedule: i, span: span, override: true})
+		}
+	}
+
+	audit.addOverlaps(schedules, pieces)
+
+	for _, ep := range o.EscalationPolicies {
+		for i, rule := range ep.EscalationRules {
+			for _, g := range ruleGaps(rule, gapsByID, o.Since, o.Until) {
+				audit.EscalationPolicyGaps = append(audit.EscalationPolicyGaps, EscalationPolicyGap{
+					EscalationPolicy: escalationPolicyReference(ep),
+					Level:            uint(i + 1),
+					Start:            g.start,
+					End:              g.end,
+				})
+			}
+		}
+	}
+
+	return audit, nil
+}
+
+// auditPiece is a span of on-call time of a user for a schedule, which is
+// either scheduled, or covered using an override.
+type auditPiece struct {
+	schedule int
+	span     scheduleSpan
+	override bool
+}
+
+// addOverlaps adds the double bookings and override conflicts of the pieces
+// of on-call time, whose schedules are indexes of the schedules.
+func (a *ScheduleAudit) addOverlaps(schedules []Schedule, pieces []auditPiece) {
+	byUser := make(map[string][]auditPiece)
+	var userIDs []string
+	for _, p := range pieces {
+		id := p.span.user.ID
+		if _, ok := byUser[id]; !ok {
+			userIDs = append(userIDs, id)
+		}
+
+		byUser[id] = append(byUser[id], p)
+	}
+
+	sort.Strings(userIDs)
+
+	for _, id := range userIDs {
+		ps := byUser[id]
+		sort.SliceStable(ps, func(i, j int) bool { return ps[i].span.start.Before(ps[j].span.start) })
+
+		for i, p := range ps {
+			for _, q := range ps[i+1:] {
+				if !q.span.start.Before(p.span.end) {
+					break
+				}
+
+				if q.schedule == p.schedule {
+					continue
+				}
+
+				start, end := q.span.start, p.span.end
+				if q.span.end.Before(end) {
+					end = q.span.end
+				}
+
+				switch {
+				case p.override == q.override:
+					a.DoubleBookings = append(a.DoubleBookings, ScheduleDoubleBooking{
+						User:      p.span.user,
+						Start:     start,
+						End:       end,
+						Schedules: []APIObject{scheduleReference(schedules[p.schedule]), scheduleReference(schedules[q.schedule])},
+					})
+
+				case p.override:
+					a.OverrideConflicts = append(a.OverrideConflicts, ScheduleOverrideConflict{
+						User:             p.span.user,
+						Start:            start,
+						End:              end,
+						Schedule:         scheduleReference(schedules[q.schedule]),
+						OverrideSchedule: scheduleReference(schedules[p.schedule]),
+					})
+
+				default:
+					a.OverrideConflicts = append(a.OverrideConflicts, ScheduleOverrideConflict{
+						User:             p.span.user,
+						Start:            start,
+						End:              end,
+						Schedule:         scheduleReference(schedules[p.schedule]),
+						OverrideSchedule: scheduleReference(schedules[q.schedule]),
+					})
+				}
+			}
+		}
+	}
+}
+
+// ruleGaps returns the time intervals during which all the targets of the
+// rule are schedules with a gap.
+func ruleGaps(rule EscalationRule, gapsByID map[string][]scheduleSpan, since, until time.Time) []scheduleSpan {
+	if len(rule.Targets) == 0 {
+		return nil
+	}
+
+	gaps := []scheduleSpan{{start: since, end: until}}
+	for _, t := range rule.Targets {
+		if t.Type != "schedule" && t.Type != "schedule_reference" {
+			return nil
+		}
+
+		scheduleGaps, ok := gapsByID[t.ID]
+		if !ok {
+			return nil
+		}
+
+		gaps = intersectSpans(gaps, scheduleGaps)
+	}
+
+	return gaps
+}
+
+// entrySpans returns the spans of the rendered entries within the time window,
+// in chronological order.
+func entrySpans(entries []RenderedScheduleEntry, since, until time.Time) ([]scheduleSpan, error) {
+	spans := make([]scheduleSpan, 0, len(entries))
+	for _, e := range entries {
+		start, end := e.Start.Time, e.End.Time
+
+		if start.Before(since) {
+			start = since
+		}
+
+		if end.After(until) {
+			end = until
+		}
+
+		if start.Before(end) {
+			spans = append(spans, scheduleSpan{start: start, end: end, user: e.User})
+		}
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	return spans, nil
+}
+
+// spanGaps returns the time intervals within the time window which aren't
+// covered by the spans, which must be in chronological order.
+func spanGaps(spans []scheduleSpan, since, until time.Time) []scheduleSpan {
+	var gaps []scheduleSpan
+
+	covered := since
+	for _, s := range spans {
+		if s.start.After(covered) {
+			gaps = append(gaps, scheduleSpan{start: covered, end: s.start})
+		}
+
+		if s.end.After(covered) {
+			covered = s.end
+		}
+	}
+
+	if until.After(covered) {
+		gaps = append(gaps, scheduleSpan{start: covered, end: until})
+	}
+
+	return gaps
+}
+
+// subtractUserSpans returns the parts of the span which aren't covered by the
+// spans of the same user.
+func subtractUserSpans(span scheduleSpan, spans []scheduleSpan) []scheduleSpan {
+	var same []scheduleSpan
+	for _, s := range spans {
+		if s.user.ID == span.user.ID {
+			same = append(same, s)
+		}
+	}
+
+	return subtractSpans(span, same)
+}
+
+// subtractSpans returns the parts of the span which aren't covered by the
+// spans, whatever their users.
+func subtractSpans(span scheduleSpan, spans []scheduleSpan) []scheduleSpan {
+	parts := []scheduleSpan{span}
+	for _, s := range spans {
+		var next []scheduleSpan
+		for _, p := range parts {
+			if !s.start.Before(p.end) || !p.start.Before(s.end) {
+				next = append(next, p)
+				continue
+			}
+
+			if p.start.Before(s.start) {
+				next = append(next, scheduleSpan{start: p.start, end: s.start, user: p.user})
+			}
+
+			if s.end.Before(p.end) {
+				next = append(next, scheduleSpan{start: s.end, end: p.end, user: p.user})
+			}
+		}
+
+		parts = next
+	}
+
+	return parts
+}
+
+func scheduleReference(s Schedule) APIObject {
+	ref := s.APIObject
+	if ref.Summary == "" {
+		ref.Summary = s.Name
+	}
+
+	return ref
+}
+
+func escalationPolicyReference(ep EscalationPolicy) APIObject {
+	ref := ep.APIObject
+	if ref.Summary == "" {
+		ref.Summary = ep.Name
+	}
+
+	return ref
+}
+
+// AuditSchedulesWithContext gets the schedules, and the escalation policies
+// referencing them, and audits them during the time window using
+// AuditSchedules.
+func (c *Client) AuditSchedulesWithContext(ctx context.Context, scheduleIDs []string, since, until time.Time) (*ScheduleAudit, error) {
+	o := AuditSchedulesOptions{Since: since, Until: until}
+
+	schedules := make([]Schedule, 0, len(scheduleIDs))
+	policies := make(map[string]bool)
+
+	for _, id := range scheduleIDs {
+		s, err := c.GetScheduleWithContext(ctx, id, GetScheduleOptions{Since: NewTime(since).String(), Until: NewTime(until).String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schedule %s: %w", id, err)
+		}
+
+		schedules = append(schedules, *s)
+
+		for _, ep := range s.EscalationPolicies {
+			if policies[ep.ID] {
+				continue
+			}
+
+			policies[ep.ID] = true
+
+			p, err := c.GetEscalationPolicyWithContext(ctx, ep.ID, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get escalation policy %s: %w", ep.ID, err)
+			}
+
+			o.EscalationPolicies = append(o.EscalationPolicies, *p)
+		}
+	}
+
+	return AuditSchedules(schedules, o)
+}
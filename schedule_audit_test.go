@@ -0,0 +1,174 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testEntry(start, end, userID string) RenderedScheduleEntry {
+	return RenderedScheduleEntry{Start: mustParseTime(start), End: mustParseTime(end), User: APIObject{ID: userID}}
+}
+
+func testTime(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	parsed, err := ParseTime(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.Time
+}
+
+func TestAuditSchedules(t *testing.T) {
+	primary := Schedule{
+		APIObject: APIObject{ID: "PS1", Type: "schedule"},
+		Name:      "Primary",
+		FinalSchedule: ScheduleLayer{RenderedScheduleEntries: []RenderedScheduleEntry{
+			testEntry("2026-01-01T00:00:00Z", "2026-01-01T08:00:00Z", "PA"),
+			testEntry("2026-01-01T08:00:00Z", "2026-01-01T12:00:00Z", "PB"),
+			// nobody is on call from 12:00 to 14:00
+			testEntry("2026-01-01T14:00:00Z", "2026-01-02T00:00:00Z", "PC"),
+		}},
+	}
+
+	secondary := Schedule{
+		APIObject: APIObject{ID: "PS2", Type: "schedule"},
+		Name:      "Secondary",
+		FinalSchedule: ScheduleLayer{RenderedScheduleEntries: []RenderedScheduleEntry{
+			testEntry("2026-01-01T00:00:00Z", "2026-01-01T06:00:00Z", "PE"),
+			// PA is on call for both schedules from 06:00 to 08:00
+			testEntry("2026-01-01T06:00:00Z", "2026-01-01T10:00:00Z", "PA"),
+			// PB covers the secondary using an override while on call for the primary
+			testEntry("2026-01-01T10:00:00Z", "2026-01-01T13:00:00Z", "PB"),
+			testEntry("2026-01-01T13:00:00Z", "2026-01-02T00:00:00Z", "PD"),
+		}},
+		OverrideSubschedule: ScheduleLayer{RenderedScheduleEntries: []RenderedScheduleEntry{
+			testEntry("2026-01-01T10:00:00Z", "2026-01-01T13:00:00Z", "PB"),
+		}},
+	}
+
+	policy := EscalationPolicy{
+		APIObject: APIObject{ID: "PEP1"},
+		Name:      "Default",
+		EscalationRules: []EscalationRule{
+			{Targets: []APIObject{{ID: "PS1", Type: "schedule_reference"}}},
+			{Targets: []APIObject{{ID: "PS1", Type: "schedule_reference"}, {ID: "PS2", Type: "schedule_reference"}}},
+			{Targets: []APIObject{{ID: "PS1", Type: "schedule_reference"}, {ID: "PU1", Type: "user_reference"}}},
+		},
+	}
+
+	audit, err := AuditSchedules([]Schedule{primary, secondary}, AuditSchedulesOptions{
+		Since:              testTime(t, "2026-01-01T00:00:00Z"),
+		Until:              testTime(t, "2026-01-02T00:00:00Z"),
+		EscalationPolicies: []EscalationPolicy{policy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primaryRef := APIObject{ID: "PS1", Type: "schedule", Summary: "Primary"}
+	secondaryRef := APIObject{ID: "PS2", Type: "schedule", Summary: "Secondary"}
+
+	testEqual(t, []ScheduleGap{
+		{Schedule: primaryRef, Start: testTime(t, "2026-01-01T12:00:00Z"), End: testTime(t, "2026-01-01T14:00:00Z")},
+	}, audit.Gaps)
+
+	testEqual(t, []ScheduleDoubleBooking{
+		{
+			User:      APIObject{ID: "PA"},
+			Start:     testTime(t, "2026-01-01T06:00:00Z"),
+			End:       testTime(t, "2026-01-01T08:00:00Z"),
+			Schedules: []APIObject{primaryRef, secondaryRef},
+		},
+	}, audit.DoubleBookings)
+
+	testEqual(t, []ScheduleOverrideConflict{
+		{
+			User:             APIObject{ID: "PB"},
+			Start:            testTime(t, "2026-01-01T10:00:00Z"),
+			End:              testTime(t, "2026-01-01T12:00:00Z"),
+			Schedule:         primaryRef,
+			OverrideSchedule: secondaryRef,
+		},
+	}, audit.OverrideConflicts)
+
+	// only the first rule has a gap, as the secondary, and the user, cover the
+	// gap of the primary for the other rules
+	testEqual(t, []EscalationPolicyGap{
+		{
+			EscalationPolicy: APIObject{ID: "PEP1", Summary: "Default"},
+			Level:            1,
+			Start:            testTime(t, "2026-01-01T12:00:00Z"),
+			End:              testTime(t, "2026-01-01T14:00:00Z"),
+		},
+	}, audit.EscalationPolicyGaps)
+
+	testEqual(t, false, audit.Empty())
+}
+
+func TestAuditSchedules_rendered(t *testing.T) {
+	// the edges of the time window are gaps when the schedule doesn't cover
+	// them
+	s := Schedule{
+		APIObject: APIObject{ID: "PS1"},
+		ScheduleLayers: []ScheduleLayer{{
+			Start:                     "2026-01-01T06:00:00Z",
+			End:                       "2026-01-01T18:00:00Z",
+			RotationTurnLengthSeconds: 86400,
+			Users:                     testUsers("PA"),
+		}},
+	}
+
+	since, until := testTime(t, "2026-01-01T00:00:00Z"), testTime(t, "2026-01-02T00:00:00Z")
+
+	rendered, err := RenderSchedule(s, RenderScheduleOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit, err := AuditSchedules([]Schedule{*rendered}, AuditSchedulesOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []ScheduleGap{
+		{Schedule: APIObject{ID: "PS1"}, Start: since, End: testTime(t, "2026-01-01T06:00:00Z")},
+		{Schedule: APIObject{ID: "PS1"}, Start: testTime(t, "2026-01-01T18:00:00Z"), End: until},
+	}, audit.Gaps)
+
+	_, err = AuditSchedules(nil, AuditSchedulesOptions{Since: until, Until: since})
+	testErrCheck(t, "AuditSchedules()", "the time window to audit must have a start before its end", err)
+}
+
+func TestClient_AuditSchedulesWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/schedules/PS1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testEqual(t, "2026-01-01T00:00:00Z", r.URL.Query().Get("since"))
+		_, _ = w.Write([]byte(`{"schedule": {"id": "PS1", "name": "Primary", "escalation_policies": [{"id": "PEP1"}],
+			"final_schedule": {"rendered_schedule_entries": [{"start": "2026-01-01T00:00:00Z", "end": "2026-01-01T12:00:00Z", "user": {"id": "PA"}}]}}}`))
+	})
+
+	mux.HandleFunc("/escalation_policies/PEP1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = w.Write([]byte(`{"escalation_policy": {"id": "PEP1", "name": "Default", "escalation_rules": [{"targets": [{"id": "PS1", "type": "schedule_reference"}]}]}}`))
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+
+	since, until := testTime(t, "2026-01-01T00:00:00Z"), testTime(t, "2026-01-02T00:00:00Z")
+
+	audit, err := client.AuditSchedulesWithContext(context.Background(), []string{"PS1"}, since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []EscalationPolicyGap{
+		{EscalationPolicy: APIObject{ID: "PEP1", Summary: "Default"}, Level: 1, Start: testTime(t, "2026-01-01T12:00:00Z"), End: until},
+	}, audit.EscalationPolicyGaps)
+}
@@ -0,0 +1,494 @@
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The types of restrictions of schedule layers.
+const (
+	RestrictionTypeDaily  = "daily_restriction"
+	RestrictionTypeWeekly = "weekly_restriction"
+)
+
+// RenderScheduleOptions is the data structure used when calling
+// RenderSchedule.
+type RenderScheduleOptions struct {
+	// Since and Until are the start and end of the time window to render.
+	Since time.Time
+	Until time.Time
+
+	// Overrides are the overrides of the schedule, such as returned by
+	// ListOverridesWithContext, which take precedence over its layers. When
+	// overrides overlap, the one later in the slice takes precedence.
+	Overrides []Override
+
+	// TimeZone is the time zone of the rendered entries, such as
+	// "America/New_York". It defaults to the time zone of the schedule, in
+	// which rotations and restrictions are always computed.
+	TimeZone string
+}
+
+// RenderSchedule computes who is on call for the schedule during the time
+// window, without calling the API, such as to unit-test changes to a schedule
+// before saving them. It returns a copy of the schedule with the
+// RenderedScheduleEntries and RenderedCoveragePercentage fields of its layers,
+// override sub-schedule, and final schedule populated, like
+// GetScheduleWithContext does.
+//
+// The rotations and restrictions of the layers are computed in the time zone
+// of the schedule, or UTC if it has none. Rotations whose turns are a whole
+// number of days hand off at the same local time on each side of DST
+// transitions. Layers later in ScheduleLayers take precedence over earlier
+// ones, as in the layer numbering of the web app, and overrides take
+// precedence over all layers.
+func RenderSchedule(s Schedule, o RenderScheduleOptions) (*Schedule, error) {
+	if o.Since.IsZero() || o.Until.IsZero() || !o.Since.Before(o.Until) {
+		return nil, errors.New("the time window to render must have a start before its end")
+	}
+
+	loc, err := loadScheduleLocation(s.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	outLoc := loc
+	if o.TimeZone != "" {
+		if outLoc, err = loadScheduleLocation(o.TimeZone); err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := s
+	rendered.ScheduleLayers = make([]ScheduleLayer, len(s.ScheduleLayers))
+
+	// the spans of each level, from the lowest to the highest precedence
+	levels := make([][]scheduleSpan, 0, len(s.ScheduleLayers)+1)
+
+	for i, l := range s.ScheduleLayers {
+		spans, err := renderLayer(l, loc, o.Since, o.Until)
+		if err != nil {
+			name := l.Name
+			if name == "" {
+				name = strconv.Itoa(i + 1)
+			}
+
+			return nil, fmt.Errorf("failed to render schedule layer %s: %w", name, err)
+		}
+
+		l.RenderedScheduleEntries = renderedEntries(spans, outLoc)
+		l.RenderedCoveragePercentage = coveragePercentage(spans, o.Since, o.Until)
+		rendered.ScheduleLayers[i] = l
+
+		levels = append(levels, spans)
+	}
+
+	overrides, err := renderOverrides(o.Overrides, o.Since, o.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered.OverrideSubschedule.Name = "Overrides"
+	rendered.OverrideSubschedule.RenderedScheduleEntries = renderedEntries(overrides, outLoc)
+	rendered.OverrideSubschedule.RenderedCoveragePercentage = coveragePercentage(overrides, o.Since, o.Until)
+
+	final := composeSpans(append(levels, overrides))
+
+	rendered.FinalSchedule.Name = "Final Schedule"
+	rendered.FinalSchedule.RenderedScheduleEntries = renderedEntries(final, outLoc)
+	rendered.FinalSchedule.RenderedCoveragePercentage = coveragePercentage(final, o.Since, o.Until)
+
+	return &rendered, nil
+}
+
+func loadScheduleLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+
+	return loc, nil
+}
+
+// scheduleSpan is a time span during which the user is on call, which ends
+// before end.
+type scheduleSpan struct {
+	start, end time.Time
+	user       APIObject
+}
+
+// renderLayer returns the spans during which the users of the layer are on
+// call within the time window, in chronological order.
+func renderLayer(l ScheduleLayer, loc *time.Location, since, until time.Time) ([]scheduleSpan, error) {
+	layerStart, err := ParseTime(l.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	layerEnd, err := ParseTime(l.End)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := since, until
+	if layerStart.After(start) {
+		start = layerStart.Time
+	}
+
+	if !layerEnd.IsZero() && layerEnd.Before(end) {
+		end = layerEnd.Time
+	}
+
+	if len(l.Users) == 0 || !start.Before(end) {
+		return nil, nil
+	}
+
+	if l.RotationTurnLengthSeconds == 0 {
+		return nil, errors.New("rotation turn length must be positive")
+	}
+
+	virtualStart, err := ParseTime(l.RotationVirtualStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if virtualStart.IsZero() {
+		virtualStart = layerStart
+	}
+
+	if virtualStart.IsZero() {
+		return nil, errors.New("rotation virtual start or start must be set")
+	}
+
+	r := newRotation(virtualStart.In(loc), l.RotationTurnLengthSeconds)
+	n := len(l.Users)
+
+	var spans []scheduleSpan
+	for k := r.index(start); ; k++ {
+		turnStart, turnEnd := r.start(k), r.start(k+1)
+		if !turnStart.Before(end) {
+			break
+		}
+
+		if turnStart.Before(start) {
+			turnStart = start
+		}
+
+		if turnEnd.After(end) {
+			turnEnd = end
+		}
+
+		spans = append(spans, scheduleSpan{
+			start: turnStart,
+			end:   turnEnd,
+			user:  l.Users[((k%n)+n)%n].User,
+		})
+	}
+
+	if len(l.Restrictions) > 0 {
+		windows, err := restrictionWindows(l.Restrictions, loc, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		spans = intersectSpans(spans, windows)
+	}
+
+	return mergeSpans(spans), nil
+}
+
+// rotation computes the start of the turns of a layer's rotation. Turns which
+// are a whole number of days long are computed in calendar days, so that they
+// start at the same local time across DST transitions.
+type rotation struct {
+	virtualStart time.Time
+	turn         time.Duration
+	days         int
+}
+
+func newRotation(virtualStart time.Time, turnLengthSeconds uint) rotation {
+	r := rotation{
+		virtualStart: virtualStart,
+		turn:         time.Duration(turnLengthSeconds) * time.Second,
+	}
+
+	if turnLengthSeconds%86400 == 0 {
+		r.days = int(turnLengthSeconds / 86400)
+	}
+
+	return r
+}
+
+// start returns the start of the k-th turn, the turn starting at the virtual
+// start being the 0th one.
+func (r rotation) start(k int) time.Time {
+	if r.days > 0 {
+		return r.virtualStart.AddDate(0, 0, k*r.days)
+	}
+
+	return r.virtualStart.Add(time.Duration(k) * r.turn)
+}
+
+// index returns the index of the turn during which t is.
+func (r rotation) index(t time.Time) int {
+	k := int(t.Sub(r.virtualStart) / r.turn)
+
+	// the estimate is off by the DST transitions since the virtual start, and
+	// by one before the virtual start, as the division truncates
+	for r.start(k).After(t) {
+		k--
+	}
+
+	for !r.start(k + 1).After(t) {
+		k++
+	}
+
+	return k
+}
+
+// restrictionWindows returns the union of the time windows of the
+// restrictions within the time window from start to end, in chronological
+// order.
+func restrictionWindows(restrictions []Restriction, loc *time.Location, start, end time.Time) ([]scheduleSpan, error) {
+	var windows []scheduleSpan
+
+	for _, r := range restrictions {
+		hour, minute, sec, err := parseTimeOfDay(r.StartTimeOfDay)
+		if err != nil {
+			return nil, err
+		}
+
+		duration := time.Duration(r.DurationSeconds) * time.Second
+
+		// the restrictions starting up to a week before the start can
+		// overlap the time window
+		first := start.In(loc).AddDate(0, 0, -7)
+
+		switch r.Type {
+		case RestrictionTypeDaily:
+		case RestrictionTypeWeekly:
+			if r.StartDayOfWeek < 1 || r.StartDayOfWeek > 7 {
+				return nil, fmt.Errorf("invalid restriction start day of week %d", r.StartDayOfWeek)
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid restriction type %q", r.Type)
+		}
+
+		for i := 0; ; i++ {
+			ws := time.Date(first.Year(), first.Month(), first.Day()+i, hour, minute, sec, 0, loc)
+			if !ws.Before(end) {
+				break
+			}
+
+			if r.Type == RestrictionTypeWeekly && isoWeekday(ws) != r.StartDayOfWeek {
+				continue
+			}
+
+			we := ws.Add(duration)
+			if !we.After(start) {
+				continue
+			}
+
+			windows = append(windows, scheduleSpan{start: ws, end: we})
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].start.Before(windows[j].start) })
+
+	// merge the overlapping windows
+	var merged []scheduleSpan
+	for _, w := range windows {
+		if last := len(merged) - 1; last >= 0 && !w.start.After(merged[last].end) {
+			if w.end.After(merged[last].end) {
+				merged[last].end = w.end
+			}
+
+			continue
+		}
+
+		merged = append(merged, w)
+	}
+
+	return merged, nil
+}
+
+// parseTimeOfDay parses a time of day such as "09:00:00" or "09:00".
+func parseTimeOfDay(s string) (hour, minute, sec int, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, fmt.Errorf("invalid restriction start time of day %q", s)
+	}
+
+	values := make([]int, 3)
+	for i, p := range parts {
+		if values[i], err = strconv.Atoi(p); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid restriction start time of day %q", s)
+		}
+	}
+
+	if values[0] > 23 || values[1] > 59 || values[2] > 59 || values[0] < 0 || values[1] < 0 || values[2] < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid restriction start time of day %q", s)
+	}
+
+	return values[0], values[1], values[2], nil
+}
+
+// isoWeekday returns the ISO 8601 day of the week of t, from 1 for Monday to 7
+// for Sunday, as used by restrictions.
+func isoWeekday(t time.Time) uint {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+
+	return uint(t.Weekday())
+}
+
+// intersectSpans returns the parts of the spans within the windows. Both must
+// be in chronological order, and the windows must not overlap.
+func intersectSpans(spans, windows []scheduleSpan) []scheduleSpan {
+	var result []scheduleSpan
+
+	w := 0
+	for _, s := range spans {
+		for w < len(windows) && !windows[w].end.After(s.start) {
+			w++
+		}
+
+		for i := w; i < len(windows) && windows[i].start.Before(s.end); i++ {
+			start, end := s.start, s.end
+			if windows[i].start.After(start) {
+				start = windows[i].start
+			}
+
+			if windows[i].end.Before(end) {
+				end = windows[i].end
+			}
+
+			result = append(result, scheduleSpan{start: start, end: end, user: s.user})
+		}
+	}
+
+	return result
+}
+
+// mergeSpans joins the consecutive spans of the same user.
+func mergeSpans(spans []scheduleSpan) []scheduleSpan {
+	var merged []scheduleSpan
+	for _, s := range spans {
+		if last := len(merged) - 1; last >= 0 && merged[last].end.Equal(s.start) && merged[last].user.ID == s.user.ID {
+			merged[last].end = s.end
+			continue
+		}
+
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+// renderOverrides returns the spans of the overrides within the time window,
+// in chronological order.
+func renderOverrides(overrides []Override, since, until time.Time) ([]scheduleSpan, error) {
+	levels := make([][]scheduleSpan, 0, len(overrides))
+
+	for _, o := range overrides {
+		start, err := o.StartTime()
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of override %s: %w", o.ID, err)
+		}
+
+		end, err := o.EndTime()
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of override %s: %w", o.ID, err)
+		}
+
+		if start.Before(since) {
+			start = since
+		}
+
+		if end.After(until) {
+			end = until
+		}
+
+		if start.Before(end) {
+			levels = append(levels, []scheduleSpan{{start: start, end: end, user: o.User}})
+		}
+	}
+
+	return composeSpans(levels), nil
+}
+
+// composeSpans returns the spans of the levels, from the lowest to the highest
+// precedence, where each time is covered by the span of the level with the
+// highest precedence covering it. The spans of each level must be in
+// chronological order, and not overlap.
+func composeSpans(levels [][]scheduleSpan) []scheduleSpan {
+	var points []time.Time
+	for _, spans := range levels {
+		for _, s := range spans {
+			points = append(points, s.start, s.end)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].Before(points[j]) })
+
+	cursors := make([]int, len(levels))
+
+	var composed []scheduleSpan
+	for i := 0; i+1 < len(points); i++ {
+		start, end := points[i], points[i+1]
+		if !start.Before(end) {
+			continue
+		}
+
+		for l := len(levels) - 1; l >= 0; l-- {
+			spans, c := levels[l], cursors[l]
+			for c < len(spans) && !spans[c].end.After(start) {
+				c++
+			}
+
+			cursors[l] = c
+
+			if c < len(spans) && !spans[c].start.After(start) {
+				composed = append(composed, scheduleSpan{start: start, end: end, user: spans[c].user})
+				break
+			}
+		}
+	}
+
+	return mergeSpans(composed)
+}
+
+func renderedEntries(spans []scheduleSpan, loc *time.Location) []RenderedScheduleEntry {
+	entries := make([]RenderedScheduleEntry, 0, len(spans))
+	for _, s := range spans {
+		entries = append(entries, RenderedScheduleEntry{
+			Start: NewTime(s.start.In(loc)),
+			End:   NewTime(s.end.In(loc)),
+			User:  s.user,
+		})
+	}
+
+	return entries
+}
+
+// coveragePercentage returns the percentage of the time window covered by the
+// spans, rounded to two decimals.
+func coveragePercentage(spans []scheduleSpan, since, until time.Time) float64 {
+	var covered time.Duration
+	for _, s := range spans {
+		covered += s.end.Sub(s.start)
+	}
+
+	return math.Round(float64(covered)/float64(until.Sub(since))*10000) / 100
+}
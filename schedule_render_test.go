@@ -0,0 +1,221 @@
+package pagerduty
+
+import (
+	"testing"
+	"time"
+)
+
+func renderedUsers(entries []RenderedScheduleEntry) []string {
+	var users []string
+	for _, e := range entries {
+		users = append(users, e.Start.String()+" "+e.End.String()+" "+e.User.ID)
+	}
+
+	return users
+}
+
+func testUsers(ids ...string) []UserReference {
+	users := make([]UserReference, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, UserReference{User: APIObject{ID: id, Type: "user_reference"}})
+	}
+
+	return users
+}
+
+func TestRenderSchedule(t *testing.T) {
+	s := Schedule{
+		TimeZone: "UTC",
+		ScheduleLayers: []ScheduleLayer{
+			{
+				Name:                      "Primary",
+				Start:                     "2026-01-05T00:00:00Z",
+				RotationVirtualStart:      "2026-01-05T00:00:00Z",
+				RotationTurnLengthSeconds: 7 * 86400,
+				Users:                     testUsers("PA", "PB"),
+			},
+			{
+				// business hours on weekdays
+				Name:                      "Business hours",
+				Start:                     "2026-01-05T00:00:00Z",
+				RotationVirtualStart:      "2026-01-05T00:00:00Z",
+				RotationTurnLengthSeconds: 86400,
+				Users:                     testUsers("PC"),
+				Restrictions: []Restriction{
+					{Type: RestrictionTypeWeekly, StartDayOfWeek: 1, StartTimeOfDay: "09:00:00", DurationSeconds: 8 * 3600},
+					{Type: RestrictionTypeWeekly, StartDayOfWeek: 2, StartTimeOfDay: "09:00:00", DurationSeconds: 8 * 3600},
+				},
+			},
+		},
+	}
+
+	got, err := RenderSchedule(s, RenderScheduleOptions{
+		Since: time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC),
+		Overrides: []Override{
+			{ID: "PO1", Start: "2026-01-13T12:00:00Z", End: "2026-01-13T20:00:00Z", User: APIObject{ID: "PD"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []string{
+		"2026-01-12T00:00:00Z 2026-01-14T00:00:00Z PB",
+	}, renderedUsers(got.ScheduleLayers[0].RenderedScheduleEntries))
+
+	testEqual(t, []string{
+		"2026-01-12T09:00:00Z 2026-01-12T17:00:00Z PC",
+		"2026-01-13T09:00:00Z 2026-01-13T17:00:00Z PC",
+	}, renderedUsers(got.ScheduleLayers[1].RenderedScheduleEntries))
+
+	testEqual(t, 33.33, got.ScheduleLayers[1].RenderedCoveragePercentage)
+
+	testEqual(t, []string{
+		"2026-01-13T12:00:00Z 2026-01-13T20:00:00Z PD",
+	}, renderedUsers(got.OverrideSubschedule.RenderedScheduleEntries))
+
+	testEqual(t, []string{
+		"2026-01-12T00:00:00Z 2026-01-12T09:00:00Z PB",
+		"2026-01-12T09:00:00Z 2026-01-12T17:00:00Z PC",
+		"2026-01-12T17:00:00Z 2026-01-13T09:00:00Z PB",
+		"2026-01-13T09:00:00Z 2026-01-13T12:00:00Z PC",
+		"2026-01-13T12:00:00Z 2026-01-13T20:00:00Z PD",
+		"2026-01-13T20:00:00Z 2026-01-14T00:00:00Z PB",
+	}, renderedUsers(got.FinalSchedule.RenderedScheduleEntries))
+
+	testEqual(t, 100.0, got.FinalSchedule.RenderedCoveragePercentage)
+
+	// the schedule isn't modified
+	testEqual(t, 0, len(s.ScheduleLayers[0].RenderedScheduleEntries))
+}
+
+func TestRenderSchedule_dst(t *testing.T) {
+	// a daily rotation handing off at 09:00 in New York, across the start of
+	// DST on 2026-03-08
+	s := Schedule{
+		TimeZone: "America/New_York",
+		ScheduleLayers: []ScheduleLayer{
+			{
+				Start:                     "2026-03-01T09:00:00-05:00",
+				RotationVirtualStart:      "2026-03-01T09:00:00-05:00",
+				RotationTurnLengthSeconds: 86400,
+				Users:                     testUsers("PA", "PB"),
+				Restrictions: []Restriction{
+					{Type: RestrictionTypeDaily, StartTimeOfDay: "08:00:00", DurationSeconds: 12 * 3600},
+				},
+			},
+		},
+	}
+
+	got, err := RenderSchedule(s, RenderScheduleOptions{
+		Since: time.Date(2026, 3, 7, 5, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []string{
+		"2026-03-07T08:00:00-05:00 2026-03-07T09:00:00-05:00 PB",
+		"2026-03-07T09:00:00-05:00 2026-03-07T20:00:00-05:00 PA",
+		"2026-03-08T08:00:00-04:00 2026-03-08T09:00:00-04:00 PA",
+		"2026-03-08T09:00:00-04:00 2026-03-08T20:00:00-04:00 PB",
+		"2026-03-09T08:00:00-04:00 2026-03-09T09:00:00-04:00 PB",
+		"2026-03-09T09:00:00-04:00 2026-03-09T20:00:00-04:00 PA",
+	}, renderedUsers(got.FinalSchedule.RenderedScheduleEntries))
+
+	// the entries can be rendered in another time zone
+	got, err = RenderSchedule(s, RenderScheduleOptions{
+		Since:    time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+		Until:    time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC),
+		TimeZone: "UTC",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []string{
+		"2026-03-09T12:00:00Z 2026-03-09T13:00:00Z PB",
+		"2026-03-09T13:00:00Z 2026-03-09T14:00:00Z PA",
+	}, renderedUsers(got.FinalSchedule.RenderedScheduleEntries))
+}
+
+func TestRenderSchedule_gaps(t *testing.T) {
+	s := Schedule{
+		ScheduleLayers: []ScheduleLayer{
+			{
+				Start:                     "2026-01-01T00:00:00Z",
+				End:                       "2026-01-01T12:00:00Z",
+				RotationVirtualStart:      "2025-12-31T18:00:00Z",
+				RotationTurnLengthSeconds: 4 * 3600,
+				Users:                     testUsers("PA", "PB", "PC"),
+			},
+		},
+	}
+
+	got, err := RenderSchedule(s, RenderScheduleOptions{
+		Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEqual(t, []string{
+		"2026-01-01T00:00:00Z 2026-01-01T02:00:00Z PB",
+		"2026-01-01T02:00:00Z 2026-01-01T06:00:00Z PC",
+		"2026-01-01T06:00:00Z 2026-01-01T10:00:00Z PA",
+		"2026-01-01T10:00:00Z 2026-01-01T12:00:00Z PB",
+	}, renderedUsers(got.FinalSchedule.RenderedScheduleEntries))
+
+	testEqual(t, 50.0, got.FinalSchedule.RenderedCoveragePercentage)
+}
+
+func TestRenderSchedule_errors(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+
+	tests := []struct {
+		name string
+		s    Schedule
+		o    RenderScheduleOptions
+		want string
+	}{
+		{
+			name: "window",
+			o:    RenderScheduleOptions{Since: until, Until: since},
+			want: "the time window to render must have a start before its end",
+		},
+		{
+			name: "time_zone",
+			s:    Schedule{TimeZone: "Mars/Olympus_Mons"},
+			o:    RenderScheduleOptions{Since: since, Until: until},
+			want: `invalid time zone "Mars/Olympus_Mons"`,
+		},
+		{
+			name: "turn_length",
+			s:    Schedule{ScheduleLayers: []ScheduleLayer{{Name: "Primary", Start: "2026-01-01T00:00:00Z", Users: testUsers("PA")}}},
+			o:    RenderScheduleOptions{Since: since, Until: until},
+			want: "failed to render schedule layer Primary: rotation turn length must be positive",
+		},
+		{
+			name: "restriction",
+			s: Schedule{ScheduleLayers: []ScheduleLayer{{
+				Start:                     "2026-01-01T00:00:00Z",
+				RotationTurnLengthSeconds: 86400,
+				Users:                     testUsers("PA"),
+				Restrictions:              []Restriction{{Type: RestrictionTypeDaily, StartTimeOfDay: "25:00:00"}},
+			}}},
+			o:    RenderScheduleOptions{Since: since, Until: until},
+			want: `failed to render schedule layer 1: invalid restriction start time of day "25:00:00"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderSchedule(tt.s, tt.o)
+			testErrCheck(t, "RenderSchedule()", tt.want, err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package pagerduty
 
 import (
+	"context"
 	"net/http"
 	"testing"
 )
@@ -74,7 +75,42 @@ func TestSchedule_Create(t *testing.T) {
 	testEqual(t, want, res)
 }
 
-// TODO: Preview a schedule -- should this function be changed to actually return a preview?
+// Preview a schedule
+func TestSchedule_Preview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/schedules/preview", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testEqual(t, "2026-01-01T00:00:00Z", r.URL.Query().Get("since"))
+		_, _ = w.Write([]byte(`{"schedule": {"name":"foo","final_schedule":{"name":"Final Schedule","rendered_schedule_entries":[{"start":"2026-01-01T00:00:00Z","end":"2026-01-02T00:00:00Z","user":{"id":"PA"}}],"rendered_coverage_percentage":100}}}`))
+	})
+
+	client := defaultTestClient(server.URL, "foo")
+	input := Schedule{Name: "foo"}
+	opts := PreviewScheduleOptions{Since: "2026-01-01T00:00:00Z", Until: "2026-01-02T00:00:00Z"}
+
+	res, err := client.PreviewScheduleWithContext(context.Background(), input, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Schedule{
+		Name: "foo",
+		FinalSchedule: ScheduleLayer{
+			Name: "Final Schedule",
+			RenderedScheduleEntries: []RenderedScheduleEntry{
+				{Start: mustParseTime("2026-01-01T00:00:00Z"), End: mustParseTime("2026-01-02T00:00:00Z"), User: APIObject{ID: "PA"}},
+			},
+			RenderedCoveragePercentage: 100,
+		},
+	}
+	testEqual(t, want, res)
+
+	if err := client.PreviewSchedule(input, opts); err != nil {
+		t.Fatal(err)
+	}
+}
 
 // Delete a schedule
 func TestSchedule_Delete(t *testing.T) {
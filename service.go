@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -168,7 +169,7 @@ func (c *Client) ListServicesWithContext(ctx context.Context, o ListServiceOptio
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/services?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListServicesWithContext", "/services?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -205,13 +206,21 @@ func (c *Client) ListServicesPaginated(ctx context.Context, o ListServiceOptions
 		}, nil
 	}
 
-	if err := c.pagedGet(ctx, "/services?"+v.Encode(), responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListServicesPaginated", "/services?"+v.Encode(), responseHandler); err != nil {
 		return nil, err
 	}
 
 	return services, nil
 }
 
+// Services returns an iterator over all of the existing services matching
+// the options, automatically fetching additional pages as needed.
+func (c *Client) Services(ctx context.Context, o ListServiceOptions) iter.Seq2[Service, error] {
+	return offsetSeq(ctx, c, "Services", "/services", o, func(r *ListServiceResponse) ([]Service, APIListObject) {
+		return r.Services, r.APIListObject
+	})
+}
+
 // GetServiceOptions is the data structure used when calling the GetService API endpoint.
 type GetServiceOptions struct {
 	Includes []string `url:"include,brackets,omitempty"`
@@ -231,7 +240,7 @@ func (c *Client) GetServiceWithContext(ctx context.Context, id string, o *GetSer
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/services/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetServiceWithContext", "/services/"+id+"?"+v.Encode(), nil)
 	return getServiceFromResponse(c, resp, err)
 }
 
@@ -248,7 +257,7 @@ func (c *Client) CreateServiceWithContext(ctx context.Context, s Service) (*Serv
 		"service": s,
 	}
 
-	resp, err := c.post(ctx, "/services", d, nil)
+	resp, err := c.post(ctx, "CreateServiceWithContext", "/services", d, nil)
 	return getServiceFromResponse(c, resp, err)
 }
 
@@ -265,7 +274,7 @@ func (c *Client) UpdateServiceWithContext(ctx context.Context, s Service) (*Serv
 		"service": s,
 	}
 
-	resp, err := c.put(ctx, "/services/"+s.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateServiceWithContext", "/services/"+s.ID, d, nil)
 	return getServiceFromResponse(c, resp, err)
 }
 
@@ -278,7 +287,7 @@ func (c *Client) DeleteService(id string) error {
 
 // DeleteServiceWithContext deletes an existing service.
 func (c *Client) DeleteServiceWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/services/"+id)
+	_, err := c.delete(ctx, "DeleteServiceWithContext", "/services/"+id)
 	return err
 }
 
@@ -307,22 +316,30 @@ func (c *Client) ListServiceRulesPaginated(ctx context.Context, serviceID string
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/services/"+serviceID+"/rules", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListServiceRulesPaginated", "/services/"+serviceID+"/rules", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return rules, nil
 }
 
+// ServiceRules returns an iterator over all of the rules for a service,
+// automatically fetching additional pages as needed.
+func (c *Client) ServiceRules(ctx context.Context, serviceID string) iter.Seq2[ServiceRule, error] {
+	return offsetSeq(ctx, c, "ServiceRules", "/services/"+serviceID+"/rules", nil, func(r *ListServiceRulesResponse) ([]ServiceRule, APIListObject) {
+		return r.Rules, APIListObject{Limit: r.Limit, Offset: r.Offset, More: r.More}
+	})
+}
+
 // GetServiceRule gets a service rule.
 func (c *Client) GetServiceRule(ctx context.Context, serviceID, ruleID string) (ServiceRule, error) {
-	resp, err := c.get(ctx, "/services/"+serviceID+"/rules/"+ruleID, nil)
+	resp, err := c.get(ctx, "GetServiceRule", "/services/"+serviceID+"/rules/"+ruleID, nil)
 	return getServiceRuleFromResponse(c, resp, err)
 }
 
 // DeleteServiceRule deletes a service rule.
 func (c *Client) DeleteServiceRule(ctx context.Context, serviceID, ruleID string) error {
-	_, err := c.delete(ctx, "/services/"+serviceID+"/rules/"+ruleID)
+	_, err := c.delete(ctx, "DeleteServiceRule", "/services/"+serviceID+"/rules/"+ruleID)
 	return err
 }
 
@@ -331,7 +348,7 @@ func (c *Client) CreateServiceRule(ctx context.Context, serviceID string, rule S
 	d := map[string]ServiceRule{
 		"rule": rule,
 	}
-	resp, err := c.post(ctx, "/services/"+serviceID+"/rules/", d, nil)
+	resp, err := c.post(ctx, "CreateServiceRule", "/services/"+serviceID+"/rules/", d, nil)
 	return getServiceRuleFromResponse(c, resp, err)
 }
 
@@ -340,7 +357,7 @@ func (c *Client) UpdateServiceRule(ctx context.Context, serviceID, ruleID string
 	d := map[string]ServiceRule{
 		"rule": rule,
 	}
-	resp, err := c.put(ctx, "/services/"+serviceID+"/rules/"+ruleID, d, nil)
+	resp, err := c.put(ctx, "UpdateServiceRule", "/services/"+serviceID+"/rules/"+ruleID, d, nil)
 	return getServiceRuleFromResponse(c, resp, err)
 }
 
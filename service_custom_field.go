@@ -85,7 +85,7 @@ func (c *Client) ListServiceCustomFields(ctx context.Context, o ListServiceCusto
 		"X-EARLY-ACCESS": "service-custom-fields-preview",
 	}
 
-	resp, err := c.get(ctx, "/services/custom_fields?"+v.Encode(), headers)
+	resp, err := c.get(ctx, "ListServiceCustomFields", "/services/custom_fields?"+v.Encode(), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +109,7 @@ func (c *Client) GetServiceCustomField(ctx context.Context, id string, o ListSer
 		"X-EARLY-ACCESS": "service-custom-fields-preview",
 	}
 
-	resp, err := c.get(ctx, "/services/custom_fields/"+id+"?"+v.Encode(), headers)
+	resp, err := c.get(ctx, "GetServiceCustomField", "/services/custom_fields/"+id+"?"+v.Encode(), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +134,7 @@ func (c *Client) CreateServiceCustomField(ctx context.Context, field *ServiceCus
 		"field": field,
 	}
 
-	resp, err := c.post(ctx, "/services/custom_fields", d, headers)
+	resp, err := c.post(ctx, "CreateServiceCustomField", "/services/custom_fields", d, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +162,7 @@ func (c *Client) UpdateServiceCustomField(ctx context.Context, field *ServiceCus
 		"field": field,
 	}
 
-	resp, err := c.put(ctx, "/services/custom_fields/"+id, d, headers)
+	resp, err := c.put(ctx, "UpdateServiceCustomField", "/services/custom_fields/"+id, d, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +181,6 @@ func (c *Client) UpdateServiceCustomField(ctx context.Context, field *ServiceCus
 func (c *Client) DeleteServiceCustomField(ctx context.Context, id string) error {
 	// Set the required X-EARLY-ACCESS header for this API
 	headers := map[string]string{"X-EARLY-ACCESS": "service-custom-fields-preview"}
-	_, err := c.deleteWithHeaders(ctx, "/services/custom_fields/"+id, headers)
+	_, err := c.deleteWithHeaders(ctx, "DeleteServiceCustomField", "/services/custom_fields/"+id, headers)
 	return err
 }
@@ -21,7 +21,7 @@ func (c *Client) ListServiceCustomFieldOptions(ctx context.Context, fieldID stri
 		"X-EARLY-ACCESS": "service-custom-fields-preview",
 	}
 
-	resp, err := c.get(ctx, "/services/custom_fields/"+fieldID+"/field_options", headers)
+	resp, err := c.get(ctx, "ListServiceCustomFieldOptions", "/services/custom_fields/"+fieldID+"/field_options", headers)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +40,7 @@ func (c *Client) GetServiceCustomFieldOption(ctx context.Context, fieldID string
 		"X-EARLY-ACCESS": "service-custom-fields-preview",
 	}
 
-	resp, err := c.get(ctx, "/services/custom_fields/"+fieldID+"/field_options/"+optionID, headers)
+	resp, err := c.get(ctx, "GetServiceCustomFieldOption", "/services/custom_fields/"+fieldID+"/field_options/"+optionID, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (c *Client) CreateServiceCustomFieldOption(ctx context.Context, fieldID str
 		"field_option": option,
 	}
 
-	resp, err := c.post(ctx, "/services/custom_fields/"+fieldID+"/field_options", d, headers)
+	resp, err := c.post(ctx, "CreateServiceCustomFieldOption", "/services/custom_fields/"+fieldID+"/field_options", d, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +86,7 @@ func (c *Client) UpdateServiceCustomFieldOption(ctx context.Context, fieldID str
 		"field_option": option,
 	}
 
-	resp, err := c.put(ctx, "/services/custom_fields/"+fieldID+"/field_options/"+option.ID, d, headers)
+	resp, err := c.put(ctx, "UpdateServiceCustomFieldOption", "/services/custom_fields/"+fieldID+"/field_options/"+option.ID, d, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +105,6 @@ func (c *Client) DeleteServiceCustomFieldOption(ctx context.Context, fieldID str
 		"X-EARLY-ACCESS": "service-custom-fields-preview",
 	}
 
-	_, err := c.deleteWithHeaders(ctx, "/services/custom_fields/"+fieldID+"/field_options/"+optionID, headers)
+	_, err := c.deleteWithHeaders(ctx, "DeleteServiceCustomFieldOption", "/services/custom_fields/"+fieldID+"/field_options/"+optionID, headers)
 	return err
 }
@@ -32,7 +32,7 @@ func (c *Client) ListBusinessServiceDependencies(businessServiceID string) (*Lis
 
 // ListBusinessServiceDependenciesWithContext lists dependencies of a business service.
 func (c *Client) ListBusinessServiceDependenciesWithContext(ctx context.Context, businessServiceID string) (*ListServiceDependencies, error) {
-	resp, err := c.get(ctx, "/service_dependencies/business_services/"+businessServiceID, nil)
+	resp, err := c.get(ctx, "ListBusinessServiceDependenciesWithContext", "/service_dependencies/business_services/"+businessServiceID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (c *Client) ListTechnicalServiceDependencies(serviceID string) (*ListServic
 
 // ListTechnicalServiceDependenciesWithContext lists dependencies of a technical service.
 func (c *Client) ListTechnicalServiceDependenciesWithContext(ctx context.Context, serviceID string) (*ListServiceDependencies, error) {
-	resp, err := c.get(ctx, "/service_dependencies/technical_services/"+serviceID, nil)
+	resp, err := c.get(ctx, "ListTechnicalServiceDependenciesWithContext", "/service_dependencies/technical_services/"+serviceID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (c *Client) AssociateServiceDependencies(dependencies *ListServiceDependenc
 
 // AssociateServiceDependenciesWithContext Create new dependencies between two services.
 func (c *Client) AssociateServiceDependenciesWithContext(ctx context.Context, dependencies *ListServiceDependencies) (*ListServiceDependencies, error) {
-	resp, err := c.post(ctx, "/service_dependencies/associate", dependencies, nil)
+	resp, err := c.post(ctx, "AssociateServiceDependenciesWithContext", "/service_dependencies/associate", dependencies, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +98,7 @@ func (c *Client) DisassociateServiceDependencies(dependencies *ListServiceDepend
 
 // DisassociateServiceDependenciesWithContext Disassociate dependencies between two services.
 func (c *Client) DisassociateServiceDependenciesWithContext(ctx context.Context, dependencies *ListServiceDependencies) (*ListServiceDependencies, error) {
-	resp, err := c.post(ctx, "/service_dependencies/disassociate", dependencies, nil)
+	resp, err := c.post(ctx, "DisassociateServiceDependenciesWithContext", "/service_dependencies/disassociate", dependencies, nil)
 	if err != nil {
 		return nil, err
 	}
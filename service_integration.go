@@ -298,7 +298,7 @@ func (c *Client) CreateIntegrationWithContext(ctx context.Context, id string, i
 		"integration": i,
 	}
 
-	resp, err := c.post(ctx, "/services/"+id+"/integrations", d, nil)
+	resp, err := c.post(ctx, "CreateIntegrationWithContext", "/services/"+id+"/integrations", d, nil)
 	return getIntegrationFromResponse(c, resp, err)
 }
 
@@ -321,7 +321,7 @@ func (c *Client) GetIntegrationWithContext(ctx context.Context, serviceID, integ
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/services/"+serviceID+"/integrations/"+integrationID+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetIntegrationWithContext", "/services/"+serviceID+"/integrations/"+integrationID+"?"+v.Encode(), nil)
 	return getIntegrationFromResponse(c, resp, err)
 }
 
@@ -334,7 +334,7 @@ func (c *Client) UpdateIntegration(serviceID string, i Integration) (*Integratio
 
 // UpdateIntegrationWithContext updates an integration belonging to a service.
 func (c *Client) UpdateIntegrationWithContext(ctx context.Context, serviceID string, i Integration) (*Integration, error) {
-	resp, err := c.put(ctx, "/services/"+serviceID+"/integrations/"+i.ID, i, nil)
+	resp, err := c.put(ctx, "UpdateIntegrationWithContext", "/services/"+serviceID+"/integrations/"+i.ID, i, nil)
 	return getIntegrationFromResponse(c, resp, err)
 }
 
@@ -347,6 +347,6 @@ func (c *Client) DeleteIntegration(serviceID string, integrationID string) error
 
 // DeleteIntegrationWithContext deletes an existing integration.
 func (c *Client) DeleteIntegrationWithContext(ctx context.Context, serviceID string, integrationID string) error {
-	_, err := c.delete(ctx, "/services/"+serviceID+"/integrations/"+integrationID)
+	_, err := c.delete(ctx, "DeleteIntegrationWithContext", "/services/"+serviceID+"/integrations/"+integrationID)
 	return err
 }
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -92,7 +93,7 @@ func (c *Client) ListTags(o ListTagOptions) (*ListTagResponse, error) {
 
 // ListTagsPaginated lists tags on your PagerDuty account, optionally filtered by a search query.
 func (c *Client) ListTagsPaginated(ctx context.Context, o ListTagOptions) ([]*Tag, error) {
-	tags, err := getTagList(ctx, c, "", "", o)
+	tags, err := getTagList(ctx, c, "ListTagsPaginated", "", "", o)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +101,15 @@ func (c *Client) ListTagsPaginated(ctx context.Context, o ListTagOptions) ([]*Ta
 	return tags, nil
 }
 
+// Tags returns an iterator over the tags on your PagerDuty account,
+// optionally filtered by a search query, automatically fetching additional
+// pages as needed.
+func (c *Client) Tags(ctx context.Context, o ListTagOptions) iter.Seq2[*Tag, error] {
+	return offsetSeq(ctx, c, "Tags", "/tags", o, func(r *ListTagResponse) ([]*Tag, APIListObject) {
+		return r.Tags, r.APIListObject
+	})
+}
+
 // CreateTag creates a new tag.
 //
 // Deprecated: Use CreateTagWithContext instead.
@@ -113,7 +123,7 @@ func (c *Client) CreateTagWithContext(ctx context.Context, t *Tag) (*Tag, error)
 		"tag": t,
 	}
 
-	resp, err := c.post(ctx, "/tags", d, nil)
+	resp, err := c.post(ctx, "CreateTagWithContext", "/tags", d, nil)
 	return getTagFromResponse(c, resp, err)
 }
 
@@ -126,7 +136,7 @@ func (c *Client) DeleteTag(id string) error {
 
 // DeleteTagWithContext removes an existing tag.
 func (c *Client) DeleteTagWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/tags/"+id)
+	_, err := c.delete(ctx, "DeleteTagWithContext", "/tags/"+id)
 	return err
 }
 
@@ -139,7 +149,7 @@ func (c *Client) GetTag(id string) (*Tag, error) {
 
 // GetTagWithContext gets details about an existing tag.
 func (c *Client) GetTagWithContext(ctx context.Context, id string) (*Tag, error) {
-	resp, err := c.get(ctx, "/tags/"+id, nil)
+	resp, err := c.get(ctx, "GetTagWithContext", "/tags/"+id, nil)
 	return getTagFromResponse(c, resp, err)
 }
 
@@ -153,7 +163,7 @@ func (c *Client) AssignTags(e, eid string, a *TagAssignments) error {
 // AssignTagsWithContext adds and removes tag assignments with entities.
 // Permitted entity types are users, teams, and escalation_policies.
 func (c *Client) AssignTagsWithContext(ctx context.Context, entityType, entityID string, a *TagAssignments) error {
-	_, err := c.post(ctx, "/"+entityType+"/"+entityID+"/change_tags", a, nil)
+	_, err := c.post(ctx, "AssignTagsWithContext", "/"+entityType+"/"+entityID+"/change_tags", a, nil)
 	if err != nil {
 		return err
 	}
@@ -202,13 +212,21 @@ func (c *Client) GetUsersByTagPaginated(ctx context.Context, tagID string) ([]*A
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/tags/"+tagID+"/users/", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "GetUsersByTagPaginated", "/tags/"+tagID+"/users/", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return users, nil
 }
 
+// UsersByTag returns an iterator over the user references related to the
+// tag, automatically fetching additional pages as needed.
+func (c *Client) UsersByTag(ctx context.Context, tagID string) iter.Seq2[*APIObject, error] {
+	return offsetSeq(ctx, c, "UsersByTag", "/tags/"+tagID+"/users/", nil, func(r *ListUserResponse) ([]*APIObject, APIListObject) {
+		return r.Users, r.APIListObject
+	})
+}
+
 // GetTeamsByTag gets related teams based on the tag. This method currently
 // handles pagination of the response, so all team references with the tag
 // should be present.
@@ -250,13 +268,21 @@ func (c *Client) GetTeamsByTagPaginated(ctx context.Context, tagID string) ([]*A
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/tags/"+tagID+"/teams/", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "GetTeamsByTagPaginated", "/tags/"+tagID+"/teams/", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return teams, nil
 }
 
+// TeamsByTag returns an iterator over the team references related to the
+// tag, automatically fetching additional pages as needed.
+func (c *Client) TeamsByTag(ctx context.Context, tagID string) iter.Seq2[*APIObject, error] {
+	return offsetSeq(ctx, c, "TeamsByTag", "/tags/"+tagID+"/teams/", nil, func(r *ListTeamsForTagResponse) ([]*APIObject, APIListObject) {
+		return r.Teams, r.APIListObject
+	})
+}
+
 // GetEscalationPoliciesByTag gets related escalation policies based on the tag.
 // This method currently handles pagination of the response, so all escalation
 // policy references with the tag should be present.
@@ -299,13 +325,22 @@ func (c *Client) GetEscalationPoliciesByTagPaginated(ctx context.Context, tagID
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/tags/"+tagID+"/escalation_policies/", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "GetEscalationPoliciesByTagPaginated", "/tags/"+tagID+"/escalation_policies/", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return eps, nil
 }
 
+// EscalationPoliciesByTag returns an iterator over the escalation policy
+// references related to the tag, automatically fetching additional pages as
+// needed.
+func (c *Client) EscalationPoliciesByTag(ctx context.Context, tagID string) iter.Seq2[*APIObject, error] {
+	return offsetSeq(ctx, c, "EscalationPoliciesByTag", "/tags/"+tagID+"/escalation_policies/", nil, func(r *ListEPResponse) ([]*APIObject, APIListObject) {
+		return r.EscalationPolicies, r.APIListObject
+	})
+}
+
 // GetTagsForEntity get related tags for Users, Teams or Escalation Policies.
 // This method currently handles pagination of the response, so all tags should
 // be present.
@@ -324,7 +359,15 @@ func (c *Client) GetTagsForEntity(entityType, entityID string, o ListTagOptions)
 // GetTagsForEntityPaginated gets related tags for Users, Teams or Escalation
 // Policies.
 func (c *Client) GetTagsForEntityPaginated(ctx context.Context, entityType, entityID string, o ListTagOptions) ([]*Tag, error) {
-	return getTagList(ctx, c, entityType, entityID, o)
+	return getTagList(ctx, c, "GetTagsForEntityPaginated", entityType, entityID, o)
+}
+
+// TagsForEntity returns an iterator over the tags related to a User, Team or
+// Escalation Policy, automatically fetching additional pages as needed.
+func (c *Client) TagsForEntity(ctx context.Context, entityType, entityID string, o ListTagOptions) iter.Seq2[*Tag, error] {
+	return offsetSeq(ctx, c, "TagsForEntity", "/"+entityType+"/"+entityID+"/tags", o, func(r *ListTagResponse) ([]*Tag, APIListObject) {
+		return r.Tags, r.APIListObject
+	})
 }
 
 func getTagFromResponse(c *Client, resp *http.Response, err error) (*Tag, error) {
@@ -348,7 +391,7 @@ func getTagFromResponse(c *Client, resp *http.Response, err error) (*Tag, error)
 }
 
 // getTagList  is a utility function that processes all pages of a ListTagResponse
-func getTagList(ctx context.Context, c *Client, entityType, entityID string, o ListTagOptions) ([]*Tag, error) {
+func getTagList(ctx context.Context, c *Client, op, entityType, entityID string, o ListTagOptions) ([]*Tag, error) {
 	queryParms, err := query.Values(o)
 	if err != nil {
 		return nil, err
@@ -381,7 +424,7 @@ func getTagList(ctx context.Context, c *Client, entityType, entityID string, o L
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, path+"?"+queryParms.Encode(), responseHandler); err != nil {
+	if err := c.pagedGet(ctx, op, path+"?"+queryParms.Encode(), responseHandler); err != nil {
 		return nil, err
 	}
 
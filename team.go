@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -63,7 +64,7 @@ func (c *Client) ListTeamsWithContext(ctx context.Context, o ListTeamOptions) (*
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/teams?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListTeamsWithContext", "/teams?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +77,14 @@ func (c *Client) ListTeamsWithContext(ctx context.Context, o ListTeamOptions) (*
 	return &result, nil
 }
 
+// Teams returns an iterator over all of the teams matching the options,
+// automatically fetching additional pages as needed.
+func (c *Client) Teams(ctx context.Context, o ListTeamOptions) iter.Seq2[Team, error] {
+	return offsetSeq(ctx, c, "Teams", "/teams", o, func(r *ListTeamResponse) ([]Team, APIListObject) {
+		return r.Teams, r.APIListObject
+	})
+}
+
 // CreateTeam creates a new team.
 //
 // Deprecated: Use CreateTeamWithContext instead.
@@ -89,7 +98,7 @@ func (c *Client) CreateTeamWithContext(ctx context.Context, t *Team) (*Team, err
 		"team": t,
 	}
 
-	resp, err := c.post(ctx, "/teams", p, nil)
+	resp, err := c.post(ctx, "CreateTeamWithContext", "/teams", p, nil)
 	return getTeamFromResponse(c, resp, err)
 }
 
@@ -102,7 +111,7 @@ func (c *Client) DeleteTeam(id string) error {
 
 // DeleteTeamWithContext removes an existing team.
 func (c *Client) DeleteTeamWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/teams/"+id)
+	_, err := c.delete(ctx, "DeleteTeamWithContext", "/teams/"+id)
 	return err
 }
 
@@ -115,7 +124,7 @@ func (c *Client) GetTeam(id string) (*Team, error) {
 
 // GetTeamWithContext gets details about an existing team.
 func (c *Client) GetTeamWithContext(ctx context.Context, id string) (*Team, error) {
-	resp, err := c.get(ctx, "/teams/"+id, nil)
+	resp, err := c.get(ctx, "GetTeamWithContext", "/teams/"+id, nil)
 	return getTeamFromResponse(c, resp, err)
 }
 
@@ -132,7 +141,7 @@ func (c *Client) UpdateTeamWithContext(ctx context.Context, id string, t *Team)
 		"team": t,
 	}
 
-	resp, err := c.put(ctx, "/teams/"+id, p, nil)
+	resp, err := c.put(ctx, "UpdateTeamWithContext", "/teams/"+id, p, nil)
 	return getTeamFromResponse(c, resp, err)
 }
 
@@ -145,7 +154,7 @@ func (c *Client) RemoveEscalationPolicyFromTeam(teamID, epID string) error {
 
 // RemoveEscalationPolicyFromTeamWithContext removes an escalation policy from a team.
 func (c *Client) RemoveEscalationPolicyFromTeamWithContext(ctx context.Context, teamID, epID string) error {
-	_, err := c.delete(ctx, "/teams/"+teamID+"/escalation_policies/"+epID)
+	_, err := c.delete(ctx, "RemoveEscalationPolicyFromTeamWithContext", "/teams/"+teamID+"/escalation_policies/"+epID)
 	return err
 }
 
@@ -158,7 +167,7 @@ func (c *Client) AddEscalationPolicyToTeam(teamID, epID string) error {
 
 // AddEscalationPolicyToTeamWithContext adds an escalation policy to a team.
 func (c *Client) AddEscalationPolicyToTeamWithContext(ctx context.Context, teamID, epID string) error {
-	_, err := c.put(ctx, "/teams/"+teamID+"/escalation_policies/"+epID, nil, nil)
+	_, err := c.put(ctx, "AddEscalationPolicyToTeamWithContext", "/teams/"+teamID+"/escalation_policies/"+epID, nil, nil)
 	return err
 }
 
@@ -171,7 +180,7 @@ func (c *Client) RemoveUserFromTeam(teamID, userID string) error {
 
 // RemoveUserFromTeamWithContext removes a user from a team.
 func (c *Client) RemoveUserFromTeamWithContext(ctx context.Context, teamID, userID string) error {
-	_, err := c.delete(ctx, "/teams/"+teamID+"/users/"+userID)
+	_, err := c.delete(ctx, "RemoveUserFromTeamWithContext", "/teams/"+teamID+"/users/"+userID)
 	return err
 }
 
@@ -215,7 +224,7 @@ type AddUserToTeamOptions struct {
 
 // AddUserToTeamWithContext adds a user to a team.
 func (c *Client) AddUserToTeamWithContext(ctx context.Context, o AddUserToTeamOptions) error {
-	_, err := c.put(ctx, "/teams/"+o.TeamID+"/users/"+o.UserID, o, nil)
+	_, err := c.put(ctx, "AddUserToTeamWithContext", "/teams/"+o.TeamID+"/users/"+o.UserID, o, nil)
 	return err
 }
 
@@ -306,7 +315,7 @@ func (c *Client) ListTeamMembers(ctx context.Context, teamID string, o ListTeamM
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/teams/"+teamID+"/members?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListTeamMembers", "/teams/"+teamID+"/members?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -357,9 +366,17 @@ func (c *Client) ListTeamMembersPaginated(ctx context.Context, teamID string) ([
 	}
 
 	// Make call to get all pages associated with the base endpoint.
-	if err := c.pagedGet(ctx, "/teams/"+teamID+"/members", responseHandler); err != nil {
+	if err := c.pagedGet(ctx, "ListTeamMembersPaginated", "/teams/"+teamID+"/members", responseHandler); err != nil {
 		return nil, err
 	}
 
 	return members, nil
 }
+
+// TeamMembers returns an iterator over all of the members of the specified
+// team, automatically fetching additional pages as needed.
+func (c *Client) TeamMembers(ctx context.Context, teamID string, o ListTeamMembersOptions) iter.Seq2[Member, error] {
+	return offsetSeq(ctx, c, "TeamMembers", "/teams/"+teamID+"/members", o, func(r *ListTeamMembersResponse) ([]Member, APIListObject) {
+		return r.Members, r.APIListObject
+	})
+}
@@ -0,0 +1,97 @@
+package pagerduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Time is a timestamp used by the PagerDuty API, such as the creation time of
+// an incident, or the start of an on-call shift. It wraps time.Time, so its
+// methods, such as Before and IsZero, can be called directly.
+//
+// Timestamps are sent to the API in RFC 3339 format, and are decoded from the
+// ISO 8601 formats used by the API. The zero Time is an unset timestamp, which
+// is the value of timestamps which are null, empty, or absent from responses,
+// and which is sent as null.
+//
+// Code which used the timestamps as strings, before they were converted to
+// Time, can use the String method to get them in RFC 3339 format.
+type Time struct {
+	time.Time
+}
+
+// timeLayouts are the layouts of the timestamps accepted by ParseTime, with
+// the ISO 8601 variants used by the PagerDuty API.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+}
+
+// NewTime returns the Time of t.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// ParseTime parses a timestamp in one of the ISO 8601 formats used by the
+// PagerDuty API. An empty string results in the zero Time, and a nil error.
+func ParseTime(s string) (Time, error) {
+	if s == "" {
+		return Time{}, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return Time{Time: parsed}, nil
+		}
+	}
+
+	return Time{}, fmt.Errorf("failed to parse timestamp %q", s)
+}
+
+// String returns the timestamp in RFC 3339 format, or an empty string if it's
+// unset.
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalJSON satisfies json.Marshaler, encoding the zero Time as null.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler, decoding null and empty strings as
+// the zero Time.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to decode timestamp: %w", err)
+	}
+
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// mustParseTime returns the Time of the timestamp s, for test fixtures.
+func mustParseTime(s string) Time {
+	parsed, err := ParseTime(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// mustParseTimePtr returns a pointer to the Time of the timestamp s, for test
+// fixtures.
+func mustParseTimePtr(s string) *Time {
+	parsed := mustParseTime(s)
+	return &parsed
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "zero", in: "", want: time.Time{}},
+		{name: "rfc3339_utc", in: "2024-03-01T10:00:00Z", want: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "rfc3339_offset", in: "2024-03-01T05:00:00-05:00", want: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "fractional", in: "2024-03-01T10:00:00.123Z", want: time.Date(2024, 3, 1, 10, 0, 0, 123000000, time.UTC)},
+		{name: "compact_offset", in: "2024-03-01T11:00:00+0100", want: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "minutes", in: "2024-03-01T10:00Z", want: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "date", in: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid", in: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.in)
+			if tt.wantErr {
+				testErrCheck(t, "ParseTime()", `failed to parse timestamp "yesterday"`, err)
+				testEqual(t, true, got.IsZero())
+				return
+			}
+
+			testErrCheck(t, "ParseTime()", "", err)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTime_String(t *testing.T) {
+	testEqual(t, "", NewTime(time.Time{}).String())
+	testEqual(t, "2024-03-01T10:00:00Z", NewTime(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)).String())
+
+	loc := time.FixedZone("EST", -5*3600)
+	testEqual(t, "2024-03-01T05:00:00.5-05:00", NewTime(time.Date(2024, 3, 1, 5, 0, 0, 500000000, loc)).String())
+}
+
+func TestTime_JSON(t *testing.T) {
+	type record struct {
+		Start Time  `json:"start"`
+		End   Time  `json:"end"`
+		Since *Time `json:"since,omitempty"`
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		const data = `{"start":"2024-03-01T05:00:00-05:00","end":null}`
+
+		var r record
+		testErrCheck(t, "json.Unmarshal()", "", json.Unmarshal([]byte(data), &r))
+
+		testEqual(t, true, r.Start.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+		testEqual(t, true, r.End.IsZero())
+
+		out, err := json.Marshal(r)
+		testErrCheck(t, "json.Marshal()", "", err)
+
+		// the timestamps are unchanged, and unset ones are null
+		testEqual(t, data, string(out))
+	})
+
+	t.Run("null", func(t *testing.T) {
+		r := record{Start: mustParseTime("2024-03-01T10:00:00Z")}
+		testErrCheck(t, "json.Unmarshal()", "", json.Unmarshal([]byte(`{"start":null,"end":""}`), &r))
+
+		testEqual(t, true, r.Start.IsZero())
+		testEqual(t, true, r.End.IsZero())
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		out, err := json.Marshal(record{Since: mustParseTimePtr("2024-03-01T10:00:00Z")})
+		testErrCheck(t, "json.Marshal()", "", err)
+		testEqual(t, `{"start":null,"end":null,"since":"2024-03-01T10:00:00Z"}`, string(out))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var r record
+		testErrCheck(t, "json.Unmarshal()", "failed to decode timestamp", json.Unmarshal([]byte(`{"start":123}`), &r))
+		testErrCheck(t, "json.Unmarshal()", `failed to parse timestamp "yesterday"`, json.Unmarshal([]byte(`{"start":"yesterday"}`), &r))
+	})
+}
+
+func TestTime_fields(t *testing.T) {
+	const data = `{"id":"1","created_at":"2024-03-01T05:00:00-05:00","last_status_change_at":"2024-03-01T10:00:00.000Z","resolved_at":null}`
+
+	var i Incident
+	testErrCheck(t, "json.Unmarshal()", "", json.Unmarshal([]byte(data), &i))
+
+	testEqual(t, true, i.CreatedAt.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+	testEqual(t, true, i.LastStatusChangeAt.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+	testEqual(t, true, i.ResolvedAt.IsZero())
+
+	// the string accessor keeps the timestamp as it was received
+	testEqual(t, "2024-03-01T05:00:00-05:00", i.CreatedAt.String())
+
+	_, err := Override{Start: "yesterday"}.StartTime()
+	testErrCheck(t, "StartTime()", `failed to parse timestamp "yesterday"`, err)
+}
@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -132,7 +133,7 @@ func (c *Client) ListUsersWithContext(ctx context.Context, o ListUsersOptions) (
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/users?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListUsersWithContext", "/users?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +146,14 @@ func (c *Client) ListUsersWithContext(ctx context.Context, o ListUsersOptions) (
 	return &result, nil
 }
 
+// Users returns an iterator over all of the users matching the options,
+// automatically fetching additional pages as needed.
+func (c *Client) Users(ctx context.Context, o ListUsersOptions) iter.Seq2[User, error] {
+	return offsetSeq(ctx, c, "Users", "/users", o, func(r *ListUsersResponse) ([]User, APIListObject) {
+		return r.Users, r.APIListObject
+	})
+}
+
 // CreateUser creates a new user.
 //
 // Deprecated: Use CreateUserWithContext instead.
@@ -158,7 +167,7 @@ func (c *Client) CreateUserWithContext(ctx context.Context, u User) (*User, erro
 		"user": u,
 	}
 
-	resp, err := c.post(ctx, "/users", d, nil)
+	resp, err := c.post(ctx, "CreateUserWithContext", "/users", d, nil)
 	return getUserFromResponse(c, resp, err)
 }
 
@@ -171,7 +180,7 @@ func (c *Client) DeleteUser(id string) error {
 
 // DeleteUserWithContext deletes a user.
 func (c *Client) DeleteUserWithContext(ctx context.Context, id string) error {
-	_, err := c.delete(ctx, "/users/"+id)
+	_, err := c.delete(ctx, "DeleteUserWithContext", "/users/"+id)
 	return err
 }
 
@@ -189,7 +198,7 @@ func (c *Client) GetUserWithContext(ctx context.Context, id string, o GetUserOpt
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/users/"+id+"?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetUserWithContext", "/users/"+id+"?"+v.Encode(), nil)
 	return getUserFromResponse(c, resp, err)
 }
 
@@ -206,7 +215,7 @@ func (c *Client) UpdateUserWithContext(ctx context.Context, u User) (*User, erro
 		"user": u,
 	}
 
-	resp, err := c.put(ctx, "/users/"+u.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateUserWithContext", "/users/"+u.ID, d, nil)
 	return getUserFromResponse(c, resp, err)
 }
 
@@ -226,7 +235,7 @@ func (c *Client) GetCurrentUserWithContext(ctx context.Context, o GetCurrentUser
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/users/me?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "GetCurrentUserWithContext", "/users/me?"+v.Encode(), nil)
 	return getUserFromResponse(c, resp, err)
 }
 
@@ -259,7 +268,7 @@ func (c *Client) ListUserContactMethods(userID string) (*ListContactMethodsRespo
 
 // ListUserContactMethodsWithContext fetches contact methods of the existing user.
 func (c *Client) ListUserContactMethodsWithContext(ctx context.Context, userID string) (*ListContactMethodsResponse, error) {
-	resp, err := c.get(ctx, "/users/"+userID+"/contact_methods", nil)
+	resp, err := c.get(ctx, "ListUserContactMethodsWithContext", "/users/"+userID+"/contact_methods", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +290,7 @@ func (c *Client) GetUserContactMethod(userID, contactMethodID string) (*ContactM
 
 // GetUserContactMethodWithContext gets details about a contact method.
 func (c *Client) GetUserContactMethodWithContext(ctx context.Context, userID, contactMethodID string) (*ContactMethod, error) {
-	resp, err := c.get(ctx, "/users/"+userID+"/contact_methods/"+contactMethodID, nil)
+	resp, err := c.get(ctx, "GetUserContactMethodWithContext", "/users/"+userID+"/contact_methods/"+contactMethodID, nil)
 	return getContactMethodFromResponse(c, resp, err)
 }
 
@@ -294,7 +303,7 @@ func (c *Client) DeleteUserContactMethod(userID, contactMethodID string) error {
 
 // DeleteUserContactMethodWithContext deletes a user.
 func (c *Client) DeleteUserContactMethodWithContext(ctx context.Context, userID, contactMethodID string) error {
-	_, err := c.delete(ctx, "/users/"+userID+"/contact_methods/"+contactMethodID)
+	_, err := c.delete(ctx, "DeleteUserContactMethodWithContext", "/users/"+userID+"/contact_methods/"+contactMethodID)
 	return err
 }
 
@@ -311,7 +320,7 @@ func (c *Client) CreateUserContactMethodWithContext(ctx context.Context, userID
 		"contact_method": cm,
 	}
 
-	resp, err := c.post(ctx, "/users/"+userID+"/contact_methods", d, nil)
+	resp, err := c.post(ctx, "CreateUserContactMethodWithContext", "/users/"+userID+"/contact_methods", d, nil)
 	return getContactMethodFromResponse(c, resp, err)
 }
 
@@ -327,7 +336,7 @@ func (c *Client) UpdateUserContactMethodWthContext(ctx context.Context, userID s
 		"contact_method": cm,
 	}
 
-	resp, err := c.put(ctx, "/users/"+userID+"/contact_methods/"+cm.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateUserContactMethodWthContext", "/users/"+userID+"/contact_methods/"+cm.ID, d, nil)
 	return getContactMethodFromResponse(c, resp, err)
 }
 
@@ -360,7 +369,7 @@ func (c *Client) GetUserNotificationRule(userID, ruleID string) (*NotificationRu
 
 // GetUserNotificationRuleWithContext gets details about a notification rule.
 func (c *Client) GetUserNotificationRuleWithContext(ctx context.Context, userID, ruleID string) (*NotificationRule, error) {
-	resp, err := c.get(ctx, "/users/"+userID+"/notification_rules/"+ruleID, nil)
+	resp, err := c.get(ctx, "GetUserNotificationRuleWithContext", "/users/"+userID+"/notification_rules/"+ruleID, nil)
 	return getUserNotificationRuleFromResponse(c, resp, err)
 }
 
@@ -377,7 +386,7 @@ func (c *Client) CreateUserNotificationRuleWithContext(ctx context.Context, user
 		"notification_rule": rule,
 	}
 
-	resp, err := c.post(ctx, "/users/"+userID+"/notification_rules", d, nil)
+	resp, err := c.post(ctx, "CreateUserNotificationRuleWithContext", "/users/"+userID+"/notification_rules", d, nil)
 	return getUserNotificationRuleFromResponse(c, resp, err)
 }
 
@@ -394,7 +403,7 @@ func (c *Client) UpdateUserNotificationRuleWithContext(ctx context.Context, user
 		"notification_rule": rule,
 	}
 
-	resp, err := c.put(ctx, "/users/"+userID+"/notification_rules/"+rule.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateUserNotificationRuleWithContext", "/users/"+userID+"/notification_rules/"+rule.ID, d, nil)
 	return getUserNotificationRuleFromResponse(c, resp, err)
 }
 
@@ -407,7 +416,7 @@ func (c *Client) DeleteUserNotificationRule(userID, ruleID string) error {
 
 // DeleteUserNotificationRuleWithContext deletes a notification rule for a user.
 func (c *Client) DeleteUserNotificationRuleWithContext(ctx context.Context, userID, ruleID string) error {
-	_, err := c.delete(ctx, "/users/"+userID+"/notification_rules/"+ruleID)
+	_, err := c.delete(ctx, "DeleteUserNotificationRuleWithContext", "/users/"+userID+"/notification_rules/"+ruleID)
 	return err
 }
 
@@ -420,7 +429,7 @@ func (c *Client) ListUserNotificationRules(userID string) (*ListUserNotification
 
 // ListUserNotificationRulesWithContext fetches notification rules of the existing user.
 func (c *Client) ListUserNotificationRulesWithContext(ctx context.Context, userID string) (*ListUserNotificationRulesResponse, error) {
-	resp, err := c.get(ctx, "/users/"+userID+"/notification_rules", nil)
+	resp, err := c.get(ctx, "ListUserNotificationRulesWithContext", "/users/"+userID+"/notification_rules", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -455,7 +464,7 @@ func getUserNotificationRuleFromResponse(c *Client, resp *http.Response, err err
 
 // GetUserOncallHandoffNotificationRule gets details about an oncall handoff notification rule.
 func (c *Client) GetUserOncallHandoffNotificationRuleWithContext(ctx context.Context, userID, ruleID string) (*OncallHandoffNotificationRule, error) {
-	resp, err := c.get(ctx, "/users/"+userID+"/oncall_handoff_notification_rules/"+ruleID, nil)
+	resp, err := c.get(ctx, "GetUserOncallHandoffNotificationRuleWithContext", "/users/"+userID+"/oncall_handoff_notification_rules/"+ruleID, nil)
 	return getUserOncallHandoffNotificationRuleFromResponse(c, resp, err)
 }
 
@@ -465,7 +474,7 @@ func (c *Client) CreateUserOncallHandoffNotificationRuleWithContext(ctx context.
 		"oncall_handoff_notification_rule": rule,
 	}
 
-	resp, err := c.post(ctx, "/users/"+userID+"/oncall_handoff_notification_rules", d, nil)
+	resp, err := c.post(ctx, "CreateUserOncallHandoffNotificationRuleWithContext", "/users/"+userID+"/oncall_handoff_notification_rules", d, nil)
 	return getUserOncallHandoffNotificationRuleFromResponse(c, resp, err)
 }
 
@@ -475,13 +484,13 @@ func (c *Client) UpdateUserOncallHandoffNotificationRuleWithContext(ctx context.
 		"oncall_handoff_notification_rule": rule,
 	}
 
-	resp, err := c.put(ctx, "/users/"+userID+"/oncall_handoff_notification_rules/"+rule.ID, d, nil)
+	resp, err := c.put(ctx, "UpdateUserOncallHandoffNotificationRuleWithContext", "/users/"+userID+"/oncall_handoff_notification_rules/"+rule.ID, d, nil)
 	return getUserOncallHandoffNotificationRuleFromResponse(c, resp, err)
 }
 
 // DeleteUserOncallHandoffNotificationRuleWithContext deletes an oncall handoff notification rule for a user.
 func (c *Client) DeleteUserOncallHandoffNotificationRuleWithContext(ctx context.Context, userID, ruleID string) error {
-	_, err := c.delete(ctx, "/users/"+userID+"/oncall_handoff_notification_rules/"+ruleID)
+	_, err := c.delete(ctx, "DeleteUserOncallHandoffNotificationRuleWithContext", "/users/"+userID+"/oncall_handoff_notification_rules/"+ruleID)
 	return err
 }
 
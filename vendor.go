@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/go-querystring/query"
@@ -67,7 +68,7 @@ func (c *Client) ListVendorsWithContext(ctx context.Context, o ListVendorOptions
 		return nil, err
 	}
 
-	resp, err := c.get(ctx, "/vendors?"+v.Encode(), nil)
+	resp, err := c.get(ctx, "ListVendorsWithContext", "/vendors?"+v.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +81,14 @@ func (c *Client) ListVendorsWithContext(ctx context.Context, o ListVendorOptions
 	return &result, nil
 }
 
+// Vendors returns an iterator over all of the vendors matching the options,
+// automatically fetching additional pages as needed.
+func (c *Client) Vendors(ctx context.Context, o ListVendorOptions) iter.Seq2[Vendor, error] {
+	return offsetSeq(ctx, c, "Vendors", "/vendors", o, func(r *ListVendorResponse) ([]Vendor, APIListObject) {
+		return r.Vendors, r.APIListObject
+	})
+}
+
 // GetVendor gets details about an existing vendor.
 //
 // Deprecated: Use GetVendorWithContext instead.
@@ -89,7 +98,7 @@ func (c *Client) GetVendor(id string) (*Vendor, error) {
 
 // GetVendorWithContext gets details about an existing vendor.
 func (c *Client) GetVendorWithContext(ctx context.Context, id string) (*Vendor, error) {
-	resp, err := c.get(ctx, "/vendors/"+id, nil)
+	resp, err := c.get(ctx, "GetVendorWithContext", "/vendors/"+id, nil)
 	return getVendorFromResponse(c, resp, err)
 }
 
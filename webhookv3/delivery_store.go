@@ -0,0 +1,281 @@
+package webhookv3
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultDeliveryStoreSize is the number of event IDs remembered by the
+// delivery stores when they're created with a size of 0.
+const DefaultDeliveryStoreSize = 10000
+
+// DeliveryStore records the IDs of the webhook events which were processed, so
+// that retried deliveries of the same event can be recognized. It must be safe
+// for concurrent use.
+type DeliveryStore interface {
+	// Seen reports whether the event with the ID was processed.
+	Seen(ctx context.Context, id string) (bool, error)
+
+	// Add records that the event with the ID was processed.
+	Add(ctx context.Context, id string) error
+}
+
+// lru is a set of IDs, which evicts the least recently used ID once it holds
+// more than size IDs.
+type lru struct {
+	size  int
+	ids   map[string]*list.Element
+	order *list.List // the most recently used ID is at the front
+}
+
+func newLRU(size int) *lru {
+	if size <= 0 {
+		size = DefaultDeliveryStoreSize
+	}
+
+	return &lru{
+		size:  size,
+		ids:   make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// contains reports whether the ID is in the set, marking it as used.
+func (l *lru) contains(id string) bool {
+	e, ok := l.ids[id]
+	if ok {
+		l.order.MoveToFront(e)
+	}
+
+	return ok
+}
+
+// add adds the ID to the set, and reports whether it wasn't in it already.
+func (l *lru) add(id string) bool {
+	if l.contains(id) {
+		return false
+	}
+
+	l.ids[id] = l.order.PushFront(id)
+
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.ids, oldest.Value.(string))
+	}
+
+	return true
+}
+
+// keys returns the IDs in the set, from the least to the most recently used.
+func (l *lru) keys() []string {
+	keys := make([]string, 0, l.order.Len())
+	for e := l.order.Back(); e != nil; e = e.Prev() {
+		keys = append(keys, e.Value.(string))
+	}
+
+	return keys
+}
+
+// MemoryDeliveryStore is a DeliveryStore keeping the IDs of the most recently
+// processed events in memory. The IDs are lost when the process exits.
+type MemoryDeliveryStore struct {
+	mu  sync.Mutex
+	ids *lru
+}
+
+var _ DeliveryStore = (*MemoryDeliveryStore)(nil) // assert that it satisfies the DeliveryStore interface.
+
+// NewMemoryDeliveryStore returns a MemoryDeliveryStore remembering the IDs of
+// the size most recently processed events, or DefaultDeliveryStoreSize if
+// size is 0.
+func NewMemoryDeliveryStore(size int) *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{ids: newLRU(size)}
+}
+
+// Seen satisfies the DeliveryStore interface.
+func (s *MemoryDeliveryStore) Seen(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ids.contains(id), nil
+}
+
+// Add satisfies the DeliveryStore interface.
+func (s *MemoryDeliveryStore) Add(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids.add(id)
+
+	return nil
+}
+
+// FileDeliveryStore is a DeliveryStore keeping the IDs of the most recently
+// processed events in memory, and in a file so that they're remembered when
+// the process restarts. The file holds one ID per line, and new IDs are
+// appended to it, until it's compacted to the IDs being remembered.
+type FileDeliveryStore struct {
+	mu    sync.Mutex
+	path  string
+	ids   *lru
+	file  *os.File
+	lines int
+}
+
+var _ DeliveryStore = (*FileDeliveryStore)(nil) // assert that it satisfies the DeliveryStore interface.
+
+// NewFileDeliveryStore returns a FileDeliveryStore remembering the IDs of the
+// size most recently processed events, or DefaultDeliveryStoreSize if size is
+// 0, in the file at the path. The IDs already in the file are loaded, and the
+// file is created if it doesn't exist. The store should be closed once it's no
+// longer used.
+func NewFileDeliveryStore(path string, size int) (*FileDeliveryStore, error) {
+	s := &FileDeliveryStore{
+		path: path,
+		ids:  newLRU(size),
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+
+	case err != nil:
+		return nil, fmt.Errorf("failed to load delivery store: %w", err)
+
+	default:
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if id := strings.TrimSpace(sc.Text()); id != "" {
+				s.ids.add(id)
+			}
+		}
+
+		err := sc.Err()
+		_ = f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to load delivery store: %w", err)
+		}
+	}
+
+	// compacting the file also terminates a partially written last line, left
+	// by a process which exited while adding an ID, so the next ID isn't
+	// appended to it
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Seen satisfies the DeliveryStore interface.
+func (s *FileDeliveryStore) Seen(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ids.contains(id), nil
+}
+
+// Add satisfies the DeliveryStore interface.
+func (s *FileDeliveryStore) Add(_ context.Context, id string) error {
+	if id == "" || strings.ContainsAny(id, "\r\n") {
+		return fmt.Errorf("invalid event ID %q", id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("delivery store is closed")
+	}
+
+	if !s.ids.add(id) {
+		return nil
+	}
+
+	if s.lines >= 2*s.ids.size {
+		return s.compact()
+	}
+
+	if _, err := s.file.WriteString(id + "\n"); err != nil {
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	s.lines++
+
+	return nil
+}
+
+// compact replaces the file with the IDs being remembered, using a temporary
+// file so that a partially written file is never read, and reopens it for
+// appending. s.mu must be held, or s not shared yet.
+func (s *FileDeliveryStore) compact() error {
+	keys := s.ids.keys()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := bufio.NewWriter(tmp)
+	for _, id := range keys {
+		_, _ = w.WriteString(id + "\n")
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save delivery store: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open delivery store: %w", err)
+	}
+
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+
+	s.file = f
+	s.lines = len(keys)
+
+	return nil
+}
+
+// Close closes the file of the store.
+func (s *FileDeliveryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
@@ -0,0 +1,300 @@
+package webhookv3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+)
+
+// EventType is the type of a webhook event, such as "incident.triggered".
+type EventType string
+
+// The types of webhook events. See
+// https://developer.pagerduty.com/docs/db0fa8c8984fc-overview#event-types for
+// more details.
+const (
+	EventTypeIncidentAcknowledged            EventType = "incident.acknowledged"
+	EventTypeIncidentAnnotated               EventType = "incident.annotated"
+	EventTypeIncidentConferenceBridgeUpdated EventType = "incident.conference_bridge.updated"
+	EventTypeIncidentDelegated               EventType = "incident.delegated"
+	EventTypeIncidentEscalated               EventType = "incident.escalated"
+	EventTypeIncidentPriorityUpdated         EventType = "incident.priority_updated"
+	EventTypeIncidentReassigned              EventType = "incident.reassigned"
+	EventTypeIncidentReopened                EventType = "incident.reopened"
+	EventTypeIncidentResolved                EventType = "incident.resolved"
+	EventTypeIncidentResponderAdded          EventType = "incident.responder.added"
+	EventTypeIncidentResponderReplied        EventType = "incident.responder.replied"
+	EventTypeIncidentStatusUpdatePublished   EventType = "incident.status_update_published"
+	EventTypeIncidentTriggered               EventType = "incident.triggered"
+	EventTypeIncidentTypeChanged             EventType = "incident.incident_type.changed"
+	EventTypeIncidentUnacknowledged          EventType = "incident.unacknowledged"
+
+	EventTypeServiceCreated EventType = "service.created"
+	EventTypeServiceDeleted EventType = "service.deleted"
+	EventTypeServiceUpdated EventType = "service.updated"
+
+	EventTypePageyPing EventType = "pagey.ping"
+)
+
+// newEventData returns a pointer to the typed data of the events with the
+// event type, which is nil for event types whose data isn't typed.
+func newEventData(t EventType) interface{} {
+	switch t {
+	case EventTypeIncidentAcknowledged,
+		EventTypeIncidentDelegated,
+		EventTypeIncidentEscalated,
+		EventTypeIncidentPriorityUpdated,
+		EventTypeIncidentReassigned,
+		EventTypeIncidentReopened,
+		EventTypeIncidentResolved,
+		EventTypeIncidentTriggered,
+		EventTypeIncidentTypeChanged,
+		EventTypeIncidentUnacknowledged:
+		return &IncidentData{}
+
+	case EventTypeIncidentAnnotated:
+		return &IncidentNoteData{}
+
+	case EventTypeIncidentConferenceBridgeUpdated:
+		return &IncidentConferenceBridgeData{}
+
+	case EventTypeIncidentResponderAdded, EventTypeIncidentResponderReplied:
+		return &IncidentResponderData{}
+
+	case EventTypeIncidentStatusUpdatePublished:
+		return &IncidentStatusUpdateData{}
+
+	case EventTypeServiceCreated, EventTypeServiceDeleted, EventTypeServiceUpdated:
+		return &ServiceData{}
+
+	case EventTypePageyPing:
+		return &PingData{}
+
+	default:
+		return nil
+	}
+}
+
+// WebhookEvent is an event delivered by a V3 webhook subscription.
+type WebhookEvent struct {
+	ID           string    `json:"id"`
+	EventType    EventType `json:"event_type"`
+	ResourceType string    `json:"resource_type"`
+	OccurredAt   time.Time `json:"occurred_at"`
+
+	// Agent is a reference to the user, or other actor, which caused the
+	// event, if any.
+	Agent *pagerduty.APIObject `json:"agent"`
+
+	// Client is the integration which caused the event, if any.
+	Client *EventClient `json:"client"`
+
+	// Data is the typed data of the event, set by DecodeEvent, which depends
+	// on the event type:
+	//
+	//	- *IncidentData for most incident events, such as incident.triggered
+	//	- *IncidentNoteData for incident.annotated
+	//	- *IncidentConferenceBridgeData for incident.conference_bridge.updated
+	//	- *IncidentResponderData for incident.responder.added and incident.responder.replied
+	//	- *IncidentStatusUpdateData for incident.status_update_published
+	//	- *ServiceData for service events
+	//	- *PingData for pagey.ping
+	//
+	// Data is nil for other event types, whose data can be decoded from
+	// RawData.
+	Data interface{} `json:"-"`
+
+	// RawData is the undecoded data of the event.
+	RawData json.RawMessage `json:"data"`
+}
+
+// EventClient is the integration which caused a webhook event.
+type EventClient struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// IncidentData is the data of incident webhook events.
+type IncidentData struct {
+	pagerduty.APIObject
+	Number           int                   `json:"number"`
+	Status           string                `json:"status"`
+	IncidentKey      string                `json:"incident_key"`
+	CreatedAt        time.Time             `json:"created_at"`
+	Title            string                `json:"title"`
+	Service          pagerduty.APIObject   `json:"service"`
+	Assignees        []pagerduty.APIObject `json:"assignees"`
+	EscalationPolicy pagerduty.APIObject   `json:"escalation_policy"`
+	Teams            []pagerduty.APIObject `json:"teams"`
+	Priority         *pagerduty.APIObject  `json:"priority"`
+	Urgency          string                `json:"urgency"`
+	ConferenceBridge *ConferenceBridge     `json:"conference_bridge"`
+	ResolveReason    *ResolveReason        `json:"resolve_reason"`
+	IncidentType     *IncidentType         `json:"incident_type,omitempty"`
+}
+
+// ConferenceBridge is the conference bridge of an incident.
+type ConferenceBridge struct {
+	ConferenceNumber string `json:"conference_number"`
+	ConferenceURL    string `json:"conference_url"`
+}
+
+var _ json.Unmarshaler = (*ConferenceBridge)(nil) // assert that it satisfies the json.Unmarshaler interface.
+
+// UnmarshalJSON satisfies encoding/json.Unmarshaler, accepting conference
+// numbers encoded as JSON numbers as well as strings.
+func (c *ConferenceBridge) UnmarshalJSON(data []byte) error {
+	var v struct {
+		ConferenceNumber json.RawMessage `json:"conference_number"`
+		ConferenceURL    string          `json:"conference_url"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	c.ConferenceURL = v.ConferenceURL
+	c.ConferenceNumber = ""
+
+	if len(v.ConferenceNumber) == 0 || string(v.ConferenceNumber) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(v.ConferenceNumber, &c.ConferenceNumber); err == nil {
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(v.ConferenceNumber, &n); err != nil {
+		return fmt.Errorf("conference_number must be a string or a number: %w", err)
+	}
+
+	c.ConferenceNumber = n.String()
+
+	return nil
+}
+
+// ResolveReason is the reason an incident was resolved, such as being merged
+// into another incident.
+type ResolveReason struct {
+	Type     string              `json:"type"`
+	Incident pagerduty.APIObject `json:"incident"`
+}
+
+// IncidentType is the type of an incident.
+type IncidentType struct {
+	Name string `json:"name"`
+}
+
+// IncidentNoteData is the data of incident.annotated events.
+type IncidentNoteData struct {
+	ID       string              `json:"id"`
+	Type     string              `json:"type"`
+	Incident pagerduty.APIObject `json:"incident"`
+	Content  string              `json:"content"`
+
+	// Trimmed is true when the content was trimmed, as it was too long to be
+	// included in the event.
+	Trimmed bool `json:"trimmed"`
+}
+
+// IncidentConferenceBridgeData is the data of
+// incident.conference_bridge.updated events.
+type IncidentConferenceBridgeData struct {
+	Type              string              `json:"type"`
+	Incident          pagerduty.APIObject `json:"incident"`
+	ConferenceNumbers []ConferenceNumber  `json:"conference_numbers"`
+	ConferenceURLs    []ConferenceURL     `json:"conference_urls"`
+}
+
+// ConferenceNumber is a phone number of a conference bridge.
+type ConferenceNumber struct {
+	Label  string `json:"label"`
+	Number string `json:"number"`
+}
+
+// ConferenceURL is a URL of a conference bridge.
+type ConferenceURL struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// IncidentResponderData is the data of incident.responder.added and
+// incident.responder.replied events.
+type IncidentResponderData struct {
+	Type             string               `json:"type"`
+	Incident         pagerduty.APIObject  `json:"incident"`
+	User             *pagerduty.APIObject `json:"user"`
+	EscalationPolicy *pagerduty.APIObject `json:"escalation_policy"`
+	Message          string               `json:"message"`
+
+	// State is the state of the responder request, such as "pending",
+	// "joined", or "declined".
+	State string `json:"state"`
+}
+
+// IncidentStatusUpdateData is the data of incident.status_update_published
+// events.
+type IncidentStatusUpdateData struct {
+	ID       string               `json:"id"`
+	Type     string               `json:"type"`
+	Incident pagerduty.APIObject  `json:"incident"`
+	Message  string               `json:"message"`
+	Sender   *pagerduty.APIObject `json:"sender"`
+}
+
+// ServiceData is the data of service webhook events.
+type ServiceData struct {
+	pagerduty.APIObject
+	Name                   string                `json:"name"`
+	Description            string                `json:"description"`
+	Status                 string                `json:"status"`
+	CreatedAt              time.Time             `json:"created_at"`
+	UpdatedAt              time.Time             `json:"updated_at"`
+	AlertCreation          string                `json:"alert_creation"`
+	AutoResolveTimeout     *uint                 `json:"auto_resolve_timeout"`
+	AcknowledgementTimeout *uint                 `json:"acknowledgement_timeout"`
+	EscalationPolicy       *pagerduty.APIObject  `json:"escalation_policy"`
+	Teams                  []pagerduty.APIObject `json:"teams"`
+}
+
+// PingData is the data of pagey.ping events, which are sent to test webhook
+// subscriptions.
+type PingData struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// DecodeEvent decodes a V3 webhook event from the body of a webhook request,
+// including its typed data, which depends on its event type. The signature of
+// the request should be verified using VerifySignature before decoding it.
+//
+// This function will fail to read any body that's 2MB or larger.
+func DecodeEvent(r io.Reader) (*WebhookEvent, error) {
+	var payload struct {
+		Event *WebhookEvent `json:"event"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(r, webhookBodyReaderLimit)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	e := payload.Event
+	if e == nil {
+		return nil, ErrMalformedBody
+	}
+
+	if data := newEventData(e.EventType); data != nil && len(e.RawData) > 0 && string(e.RawData) != "null" {
+		if err := json.Unmarshal(e.RawData, data); err != nil {
+			return nil, fmt.Errorf("failed to decode data of %s webhook event: %w", strconv.Quote(string(e.EventType)), err)
+		}
+
+		e.Data = data
+	}
+
+	return e, nil
+}
@@ -0,0 +1,168 @@
+package webhookv3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	e, err := DecodeEvent(strings.NewReader(defaultBody))
+	if err != nil {
+		t.Fatalf("DecodeEvent() error = %v", err)
+	}
+
+	if e.ID != "01BWDWL3NYY7LUFPZCC28QUCMK" || e.EventType != EventTypeIncidentPriorityUpdated || e.ResourceType != "incident" {
+		t.Errorf("event = %+v", e)
+	}
+
+	if want := time.Date(2021, 4, 26, 17, 36, 27, 458000000, time.UTC); !e.OccurredAt.Equal(want) {
+		t.Errorf("OccurredAt = %v, want %v", e.OccurredAt, want)
+	}
+
+	if e.Agent == nil || e.Agent.ID != "PLH1HKV" || e.Agent.Type != "user_reference" {
+		t.Errorf("Agent = %+v", e.Agent)
+	}
+
+	if e.Client != nil {
+		t.Errorf("Client = %+v, want nil", e.Client)
+	}
+
+	incident, ok := e.Data.(*IncidentData)
+	if !ok {
+		t.Fatalf("Data = %T, want *IncidentData", e.Data)
+	}
+
+	if incident.ID != "PGR0VU2" || incident.Number != 2 || incident.Status != "triggered" || incident.Urgency != "high" {
+		t.Errorf("incident = %+v", incident)
+	}
+
+	if incident.Service.ID != "PF9KMXH" || len(incident.Assignees) != 1 || incident.Assignees[0].ID != "PTUXL6G" {
+		t.Errorf("incident = %+v", incident)
+	}
+
+	if incident.Priority == nil || incident.Priority.Summary != "P1" {
+		t.Errorf("Priority = %+v", incident.Priority)
+	}
+
+	if cb := incident.ConferenceBridge; cb == nil || cb.ConferenceNumber != "1000" || cb.ConferenceURL != "https://example.com" {
+		t.Errorf("ConferenceBridge = %+v", cb)
+	}
+
+	if incident.ResolveReason != nil {
+		t.Errorf("ResolveReason = %+v, want nil", incident.ResolveReason)
+	}
+}
+
+func TestDecodeEvent_types(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		check func(t *testing.T, data interface{})
+	}{
+		{
+			name: "incident.annotated",
+			body: `{"event":{"event_type":"incident.annotated","resource_type":"incident","data":{"incident":{"id":"PGR0VU2","type":"incident_reference"},"id":"P7VBDK7","content":"Investigating","trimmed":false,"type":"incident_note"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				note, ok := data.(*IncidentNoteData)
+				if !ok || note.ID != "P7VBDK7" || note.Content != "Investigating" || note.Incident.ID != "PGR0VU2" {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "incident.responder.added",
+			body: `{"event":{"event_type":"incident.responder.added","resource_type":"incident","data":{"incident":{"id":"PGR0VU2"},"user":{"id":"PTUXL6G"},"message":"Please help","state":"pending","type":"incident_responder"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				r, ok := data.(*IncidentResponderData)
+				if !ok || r.State != "pending" || r.User == nil || r.User.ID != "PTUXL6G" || r.EscalationPolicy != nil {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "incident.status_update_published",
+			body: `{"event":{"event_type":"incident.status_update_published","resource_type":"incident","data":{"id":"PXYZ123","incident":{"id":"PGR0VU2"},"message":"Mitigated","sender":{"id":"PLH1HKV"},"type":"status_update"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				u, ok := data.(*IncidentStatusUpdateData)
+				if !ok || u.Message != "Mitigated" || u.Sender == nil || u.Sender.ID != "PLH1HKV" {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "incident.conference_bridge.updated",
+			body: `{"event":{"event_type":"incident.conference_bridge.updated","resource_type":"incident","data":{"incident":{"id":"PGR0VU2"},"conference_numbers":[{"label":"US","number":"+1 555-555-5555,,,,1234#"}],"conference_urls":[{"label":"Zoom","url":"https://example.com"}],"type":"incident_conference_bridge"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				cb, ok := data.(*IncidentConferenceBridgeData)
+				if !ok || len(cb.ConferenceNumbers) != 1 || cb.ConferenceNumbers[0].Label != "US" || len(cb.ConferenceURLs) != 1 {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "service.updated",
+			body: `{"event":{"event_type":"service.updated","resource_type":"service","data":{"id":"PF9KMXH","type":"service","name":"API Service","status":"active","auto_resolve_timeout":null,"acknowledgement_timeout":1800,"escalation_policy":{"id":"PUS0KTE"}}}}`,
+			check: func(t *testing.T, data interface{}) {
+				s, ok := data.(*ServiceData)
+				if !ok || s.ID != "PF9KMXH" || s.Name != "API Service" || s.AutoResolveTimeout != nil || s.AcknowledgementTimeout == nil || *s.AcknowledgementTimeout != 1800 {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "pagey.ping",
+			body: `{"event":{"event_type":"pagey.ping","resource_type":"pagey","data":{"message":"Hello from your friend Pagey!","type":"ping"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				p, ok := data.(*PingData)
+				if !ok || p.Message != "Hello from your friend Pagey!" {
+					t.Errorf("Data = %#v", data)
+				}
+			},
+		},
+		{
+			name: "incident.workflow.started",
+			body: `{"event":{"event_type":"incident.workflow.started","resource_type":"incident","data":{"type":"incident_workflow_instance"}}}`,
+			check: func(t *testing.T, data interface{}) {
+				if data != nil {
+					t.Errorf("Data = %#v, want nil", data)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := DecodeEvent(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("DecodeEvent() error = %v", err)
+			}
+
+			if string(e.EventType) != tt.name {
+				t.Errorf("EventType = %q, want %q", e.EventType, tt.name)
+			}
+
+			if len(e.RawData) == 0 {
+				t.Error("RawData is empty")
+			}
+
+			tt.check(t, e.Data)
+		})
+	}
+}
+
+func TestDecodeEvent_errors(t *testing.T) {
+	if _, err := DecodeEvent(strings.NewReader(`{}`)); !errors.Is(err, ErrMalformedBody) {
+		t.Errorf("DecodeEvent() error = %v, want %v", err, ErrMalformedBody)
+	}
+
+	if _, err := DecodeEvent(strings.NewReader(`{"event":`)); err == nil {
+		t.Error("DecodeEvent() error = nil, want an error for a truncated body")
+	}
+
+	_, err := DecodeEvent(strings.NewReader(`{"event":{"event_type":"incident.triggered","data":{"number":"two"}}}`))
+	if err == nil || !strings.Contains(err.Error(), `"incident.triggered"`) {
+		t.Errorf("DecodeEvent() error = %v, want an error decoding the data", err)
+	}
+}
@@ -0,0 +1,329 @@
+package webhookv3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// IncidentEvent is an incident webhook event, such as incident.triggered,
+// with its typed data.
+type IncidentEvent struct {
+	WebhookEvent
+	Data *IncidentData
+}
+
+// IncidentNoteEvent is an incident.annotated webhook event, with its typed
+// data.
+type IncidentNoteEvent struct {
+	WebhookEvent
+	Data *IncidentNoteData
+}
+
+// IncidentConferenceBridgeEvent is an incident.conference_bridge.updated
+// webhook event, with its typed data.
+type IncidentConferenceBridgeEvent struct {
+	WebhookEvent
+	Data *IncidentConferenceBridgeData
+}
+
+// IncidentResponderEvent is an incident.responder.added or
+// incident.responder.replied webhook event, with its typed data.
+type IncidentResponderEvent struct {
+	WebhookEvent
+	Data *IncidentResponderData
+}
+
+// IncidentStatusUpdateEvent is an incident.status_update_published webhook
+// event, with its typed data.
+type IncidentStatusUpdateEvent struct {
+	WebhookEvent
+	Data *IncidentStatusUpdateData
+}
+
+// ServiceEvent is a service webhook event, such as service.updated, with its
+// typed data.
+type ServiceEvent struct {
+	WebhookEvent
+	Data *ServiceData
+}
+
+// PingEvent is a pagey.ping webhook event, with its typed data.
+type PingEvent struct {
+	WebhookEvent
+	Data *PingData
+}
+
+// EventHandlerFunc handles a webhook event. Returning an error causes the
+// webhook delivery to fail, so that PagerDuty retries it.
+type EventHandlerFunc func(ctx context.Context, e *WebhookEvent) error
+
+// Handler is an http.Handler receiving V3 webhooks. It verifies the signature
+// of each webhook request, decodes its event, and dispatches it to the
+// callbacks registered for its event type.
+//
+// It responds with:
+//
+//   - 204 No Content when the event was handled, or when no callback is
+//     registered for its event type
+//   - 400 Bad Request when the request is malformed (ErrMalformedHeader,
+//     ErrMalformedBody, or an event which can't be decoded)
+//   - 403 Forbidden when the request isn't signed using any of the secrets
+//     (ErrNoValidSignatures)
+//   - 500 Internal Server Error when a callback returned an error, so that
+//     PagerDuty retries the delivery. The error isn't included in the
+//     response, so callbacks should log or report it themselves.
+//
+// Callbacks should be registered before the Handler starts serving requests.
+type Handler struct {
+	mu      sync.RWMutex
+	secrets []string
+
+	callbacks map[EventType][]EventHandlerFunc
+}
+
+var _ http.Handler = (*Handler)(nil) // assert that it satisfies the http.Handler interface.
+
+// NewHandler returns a Handler verifying webhook requests using the secrets of
+// the webhook subscription. A request is accepted when it's signed using any
+// of the secrets, so that a secret can be rotated without rejecting the
+// requests in flight.
+func NewHandler(secrets ...string) *Handler {
+	return &Handler{
+		secrets:   secrets,
+		callbacks: make(map[EventType][]EventHandlerFunc),
+	}
+}
+
+// SetSecrets replaces the secrets used to verify webhook requests, such as to
+// rotate them while the Handler is serving requests.
+func (h *Handler) SetSecrets(secrets ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.secrets = secrets
+}
+
+// verifySignature verifies the signature of the request using each of the
+// secrets, returning ErrNoValidSignatures when it's not signed using any of
+// them.
+func (h *Handler) verifySignature(r *http.Request) error {
+	h.mu.RLock()
+	secrets := h.secrets
+	h.mu.RUnlock()
+
+	for _, secret := range secrets {
+		err := VerifySignature(r, secret)
+		if !errors.Is(err, ErrNoValidSignatures) {
+			return err
+		}
+	}
+
+	return ErrNoValidSignatures
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.verifySignature(r); err != nil {
+		status := signatureErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	e, err := DecodeEvent(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(r.Context(), e); err != nil {
+		// the error may contain internal details, which aren't for PagerDuty
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// signatureErrorStatus returns the status code of the response to a request
+// whose signature couldn't be verified.
+func signatureErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNoValidSignatures):
+		return http.StatusForbidden
+
+	case errors.Is(err, ErrMalformedHeader), errors.Is(err, ErrMalformedBody):
+		return http.StatusBadRequest
+
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// handle calls the callbacks registered for the event type of the event, in
+// the order they were registered, until one of them returns an error.
+func (h *Handler) handle(ctx context.Context, e *WebhookEvent) error {
+	for _, fn := range h.callbacks[e.EventType] {
+		if err := fn(ctx, e); err != nil {
+			return fmt.Errorf("failed to handle %s event %s: %w", e.EventType, e.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// On registers the callback for the events of the event type, including event
+// types which don't have a typed callback, such as incident.workflow.started.
+func (h *Handler) On(t EventType, fn EventHandlerFunc) {
+	h.callbacks[t] = append(h.callbacks[t], fn)
+}
+
+func (h *Handler) onIncident(t EventType, fn func(context.Context, *IncidentEvent) error) {
+	h.On(t, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*IncidentData)
+		return fn(ctx, &IncidentEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+func (h *Handler) onService(t EventType, fn func(context.Context, *ServiceEvent) error) {
+	h.On(t, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*ServiceData)
+		return fn(ctx, &ServiceEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+func (h *Handler) onIncidentResponder(t EventType, fn func(context.Context, *IncidentResponderEvent) error) {
+	h.On(t, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*IncidentResponderData)
+		return fn(ctx, &IncidentResponderEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+// OnIncidentAcknowledged registers the callback for incident.acknowledged
+// events.
+func (h *Handler) OnIncidentAcknowledged(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentAcknowledged, fn)
+}
+
+// OnIncidentAnnotated registers the callback for incident.annotated events.
+func (h *Handler) OnIncidentAnnotated(fn func(context.Context, *IncidentNoteEvent) error) {
+	h.On(EventTypeIncidentAnnotated, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*IncidentNoteData)
+		return fn(ctx, &IncidentNoteEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+// OnIncidentConferenceBridgeUpdated registers the callback for
+// incident.conference_bridge.updated events.
+func (h *Handler) OnIncidentConferenceBridgeUpdated(fn func(context.Context, *IncidentConferenceBridgeEvent) error) {
+	h.On(EventTypeIncidentConferenceBridgeUpdated, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*IncidentConferenceBridgeData)
+		return fn(ctx, &IncidentConferenceBridgeEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+// OnIncidentDelegated registers the callback for incident.delegated events.
+func (h *Handler) OnIncidentDelegated(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentDelegated, fn)
+}
+
+// OnIncidentEscalated registers the callback for incident.escalated events.
+func (h *Handler) OnIncidentEscalated(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentEscalated, fn)
+}
+
+// OnIncidentPriorityUpdated registers the callback for
+// incident.priority_updated events.
+func (h *Handler) OnIncidentPriorityUpdated(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentPriorityUpdated, fn)
+}
+
+// OnIncidentReassigned registers the callback for incident.reassigned events.
+func (h *Handler) OnIncidentReassigned(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentReassigned, fn)
+}
+
+// OnIncidentReopened registers the callback for incident.reopened events.
+func (h *Handler) OnIncidentReopened(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentReopened, fn)
+}
+
+// OnIncidentResolved registers the callback for incident.resolved events.
+func (h *Handler) OnIncidentResolved(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentResolved, fn)
+}
+
+// OnIncidentResponderAdded registers the callback for
+// incident.responder.added events.
+func (h *Handler) OnIncidentResponderAdded(fn func(context.Context, *IncidentResponderEvent) error) {
+	h.onIncidentResponder(EventTypeIncidentResponderAdded, fn)
+}
+
+// OnIncidentResponderReplied registers the callback for
+// incident.responder.replied events.
+func (h *Handler) OnIncidentResponderReplied(fn func(context.Context, *IncidentResponderEvent) error) {
+	h.onIncidentResponder(EventTypeIncidentResponderReplied, fn)
+}
+
+// OnIncidentStatusUpdatePublished registers the callback for
+// incident.status_update_published events.
+func (h *Handler) OnIncidentStatusUpdatePublished(fn func(context.Context, *IncidentStatusUpdateEvent) error) {
+	h.On(EventTypeIncidentStatusUpdatePublished, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*IncidentStatusUpdateData)
+		return fn(ctx, &IncidentStatusUpdateEvent{WebhookEvent: *e, Data: data})
+	})
+}
+
+// OnIncidentTriggered registers the callback for incident.triggered events.
+func (h *Handler) OnIncidentTriggered(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentTriggered, fn)
+}
+
+// OnIncidentTypeChanged registers the callback for
+// incident.incident_type.changed events.
+func (h *Handler) OnIncidentTypeChanged(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentTypeChanged, fn)
+}
+
+// OnIncidentUnacknowledged registers the callback for
+// incident.unacknowledged events.
+func (h *Handler) OnIncidentUnacknowledged(fn func(context.Context, *IncidentEvent) error) {
+	h.onIncident(EventTypeIncidentUnacknowledged, fn)
+}
+
+// OnServiceCreated registers the callback for service.created events.
+func (h *Handler) OnServiceCreated(fn func(context.Context, *ServiceEvent) error) {
+	h.onService(EventTypeServiceCreated, fn)
+}
+
+// OnServiceDeleted registers the callback for service.deleted events.
+func (h *Handler) OnServiceDeleted(fn func(context.Context, *ServiceEvent) error) {
+	h.onService(EventTypeServiceDeleted, fn)
+}
+
+// OnServiceUpdated registers the callback for service.updated events.
+func (h *Handler) OnServiceUpdated(fn func(context.Context, *ServiceEvent) error) {
+	h.onService(EventTypeServiceUpdated, fn)
+}
+
+// OnPing registers the callback for pagey.ping events, which are sent to test
+// the webhook subscription.
+func (h *Handler) OnPing(fn func(context.Context, *PingEvent) error) {
+	h.On(EventTypePageyPing, func(ctx context.Context, e *WebhookEvent) error {
+		data, _ := e.Data.(*PingData)
+		return fn(ctx, &PingEvent{WebhookEvent: *e, Data: data})
+	})
+}
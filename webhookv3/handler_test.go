@@ -0,0 +1,136 @@
+package webhookv3
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newSignedRequest returns a webhook request with the body, signed using each
+// of the secrets.
+func newSignedRequest(t *testing.T, body string, secrets ...string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+
+	sigs := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		sigs = append(sigs, "v1="+hex.EncodeToString(calculateSignature([]byte(body), s)))
+	}
+
+	if len(sigs) > 0 {
+		req.Header.Set("X-PagerDuty-Signature", strings.Join(sigs, ","))
+	}
+
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(secret)
+
+	var got *IncidentEvent
+	h.OnIncidentPriorityUpdated(func(_ context.Context, e *IncidentEvent) error {
+		got = e
+		return nil
+	})
+
+	h.OnIncidentTriggered(func(context.Context, *IncidentEvent) error {
+		t.Error("incident.triggered callback called for an incident.priority_updated event")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(t, defaultBody, secret))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+
+	if got == nil {
+		t.Fatal("incident.priority_updated callback wasn't called")
+	}
+
+	if got.ID != "01BWDWL3NYY7LUFPZCC28QUCMK" || got.Data == nil || got.Data.Title != "A little bump in the road" {
+		t.Errorf("event = %+v", got)
+	}
+}
+
+func TestHandler_status(t *testing.T) {
+	failing := errors.New("jira is down")
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		sign   []string
+		want   int
+	}{
+		{name: "no_callback", body: `{"event":{"id":"1","event_type":"service.created","data":{}}}`, sign: []string{secret}, want: http.StatusNoContent},
+		{name: "callback_error", body: `{"event":{"id":"1","event_type":"incident.triggered","data":{}}}`, sign: []string{secret}, want: http.StatusInternalServerError},
+		{name: "invalid_signature", body: defaultBody, sign: []string{"other"}, want: http.StatusForbidden},
+		{name: "missing_signature", body: defaultBody, want: http.StatusBadRequest},
+		{name: "empty_body", sign: []string{secret}, want: http.StatusBadRequest},
+		{name: "invalid_event", body: `{"event":`, sign: []string{secret}, want: http.StatusBadRequest},
+		{name: "method", method: http.MethodGet, body: defaultBody, sign: []string{secret}, want: http.StatusMethodNotAllowed},
+	}
+
+	h := NewHandler(secret)
+	h.OnIncidentTriggered(func(context.Context, *IncidentEvent) error { return failing })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newSignedRequest(t, tt.body, tt.sign...)
+			if tt.method != "" {
+				req.Method = tt.method
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+
+			// the errors of callbacks aren't sent to PagerDuty
+			if strings.Contains(rec.Body.String(), failing.Error()) {
+				t.Errorf("body = %q, contains the callback error", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_secretRotation(t *testing.T) {
+	h := NewHandler("old", "new")
+
+	var calls int
+	h.On(EventTypeIncidentPriorityUpdated, func(context.Context, *WebhookEvent) error {
+		calls++
+		return nil
+	})
+
+	for _, s := range []string{"old", "new"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newSignedRequest(t, defaultBody, s))
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("status for a request signed using %q = %d, want %d", s, rec.Code, http.StatusNoContent)
+		}
+	}
+
+	h.SetSecrets("new")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(t, defaultBody, "old"))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status for a request signed using a retired secret = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	if calls != 2 {
+		t.Errorf("callback called %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,198 @@
+package webhookv3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrEventTooOld is the error returned by ReplayProtection for events which
+// occurred longer ago than the maximum age.
+var ErrEventTooOld = errors.New("webhook event is too old")
+
+// ReplayOption is a functional option for ReplayProtection.
+type ReplayOption func(*replayProtection)
+
+// WithMaxAge rejects the events which occurred longer ago than the maximum
+// age, according to their occurred_at field, with a 400 Bad Request. PagerDuty
+// retries failed deliveries for a limited time, so events older than that can
+// only be replayed. The maximum age isn't checked by default.
+func WithMaxAge(d time.Duration) ReplayOption {
+	return func(p *replayProtection) {
+		p.maxAge = d
+	}
+}
+
+// WithReplayErrorHandler sets the function called when the event couldn't be
+// recorded in the DeliveryStore after it was processed. The response was
+// already written by then, so the error is otherwise ignored, and a retried
+// delivery of the event is processed again.
+func WithReplayErrorHandler(fn func(r *http.Request, err error)) ReplayOption {
+	return func(p *replayProtection) {
+		p.errorHandler = fn
+	}
+}
+
+type replayProtection struct {
+	next         http.Handler
+	store        DeliveryStore
+	maxAge       time.Duration
+	errorHandler func(r *http.Request, err error)
+	now          func() time.Time
+
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+}
+
+// ReplayProtection returns a middleware for the handler of webhook requests,
+// such as a Handler, which prevents processing the same event more than once.
+// PagerDuty retries the deliveries which failed or timed out, so the same
+// event can be received again after it was processed.
+//
+// The IDs of the events the handler responded to with a 2xx status code are
+// recorded in the store, and later deliveries of those events are responded to
+// with 204 No Content without calling the handler. Deliveries of an event
+// which is being processed are responded to with 409 Conflict, so that
+// PagerDuty retries them later.
+//
+// Only the events which were processed successfully are recorded, so the
+// handler must verify the signature of the requests, like Handler does, for
+// forged requests not to be recorded.
+func ReplayProtection(next http.Handler, store DeliveryStore, opts ...ReplayOption) http.Handler {
+	p := &replayProtection{
+		next:     next,
+		store:    store,
+		now:      time.Now,
+		inFlight: make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (p *replayProtection) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		p.next.ServeHTTP(w, r)
+		return
+	}
+
+	orb := r.Body
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, webhookBodyReaderLimit))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	_ = orb.Close()
+	r.Body = io.NopCloser(bytes.NewReader(b))
+
+	var payload struct {
+		Event struct {
+			ID         string    `json:"id"`
+			OccurredAt time.Time `json:"occurred_at"`
+		} `json:"event"`
+	}
+
+	if err := json.Unmarshal(b, &payload); err != nil || payload.Event.ID == "" {
+		// leave rejecting the malformed request to the handler
+		p.next.ServeHTTP(w, r)
+		return
+	}
+
+	id := payload.Event.ID
+
+	if p.maxAge > 0 && !payload.Event.OccurredAt.IsZero() && p.now().Sub(payload.Event.OccurredAt) > p.maxAge {
+		http.Error(w, ErrEventTooOld.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !p.start(id) {
+		http.Error(w, fmt.Sprintf("webhook event %s is being processed", id), http.StatusConflict)
+		return
+	}
+
+	defer p.finish(id)
+
+	seen, err := p.store.Seen(r.Context(), id)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if seen {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	sw := &statusResponseWriter{ResponseWriter: w}
+	p.next.ServeHTTP(sw, r)
+
+	// the response is 200 OK when the handler didn't write it
+	if sw.status != 0 && (sw.status < 200 || sw.status > 299) {
+		return
+	}
+
+	if err := p.store.Add(r.Context(), id); err != nil && p.errorHandler != nil {
+		p.errorHandler(r, fmt.Errorf("failed to record webhook event %s: %w", id, err))
+	}
+}
+
+// start marks the event as being processed, and reports whether it wasn't
+// already.
+func (p *replayProtection) start(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.inFlight[id]; ok {
+		return false
+	}
+
+	p.inFlight[id] = struct{}{}
+
+	return true
+}
+
+func (p *replayProtection) finish(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.inFlight, id)
+}
+
+// statusResponseWriter records the status code of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for use by
+// http.ResponseController.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,192 @@
+package webhookv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayProtection(t *testing.T) {
+	h := NewHandler(secret)
+
+	var calls int
+	fail := true
+	h.OnIncidentPriorityUpdated(func(context.Context, *IncidentEvent) error {
+		calls++
+		if fail {
+			return errors.New("jira is down")
+		}
+		return nil
+	})
+
+	rp := ReplayProtection(h, NewMemoryDeliveryStore(0))
+
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		rp.ServeHTTP(rec, newSignedRequest(t, defaultBody, secret))
+		return rec.Code
+	}
+
+	// failed deliveries are retried
+	if code := serve(); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	fail = false
+
+	if code := serve(); code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", code, http.StatusNoContent)
+	}
+
+	// the retried delivery of a processed event isn't processed again
+	if code := serve(); code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if calls != 2 {
+		t.Errorf("callback called %d times, want 2", calls)
+	}
+
+	// forged requests aren't recorded
+	rec := httptest.NewRecorder()
+	rp.ServeHTTP(rec, newSignedRequest(t, strings.Replace(defaultBody, "01BWDWL3NYY7LUFPZCC28QUCMK", "forged", 1), "other"))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestReplayProtection_inFlight(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rp := ReplayProtection(next, NewMemoryDeliveryStore(0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rp.ServeHTTP(httptest.NewRecorder(), newSignedRequest(t, defaultBody))
+	}()
+
+	<-started
+
+	rec := httptest.NewRecorder()
+	rp.ServeHTTP(rec, newSignedRequest(t, defaultBody))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestReplayProtection_maxAge(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rp := ReplayProtection(next, NewMemoryDeliveryStore(0), WithMaxAge(time.Hour)).(*replayProtection)
+
+	occurredAt := time.Date(2021, 4, 26, 17, 36, 27, 458000000, time.UTC)
+
+	for _, tt := range []struct {
+		now  time.Time
+		want int
+	}{
+		{now: occurredAt.Add(2 * time.Hour), want: http.StatusBadRequest},
+		{now: occurredAt.Add(time.Minute), want: http.StatusNoContent},
+	} {
+		rp.now = func() time.Time { return tt.now }
+
+		rec := httptest.NewRecorder()
+		rp.ServeHTTP(rec, newSignedRequest(t, defaultBody))
+		if rec.Code != tt.want {
+			t.Errorf("status at %v = %d, want %d", tt.now, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestMemoryDeliveryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryDeliveryStore(2)
+
+	for _, id := range []string{"a", "b"} {
+		if err := s.Add(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// using "a" makes "b" the least recently used
+	if seen, _ := s.Seen(ctx, "a"); !seen {
+		t.Error("Seen(a) = false, want true")
+	}
+
+	_ = s.Add(ctx, "c")
+
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if seen, _ := s.Seen(ctx, id); seen != want {
+			t.Errorf("Seen(%s) = %t, want %t", id, seen, want)
+		}
+	}
+}
+
+func TestFileDeliveryStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "deliveries")
+
+	s, err := NewFileDeliveryStore(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.Add(ctx, id); err != nil {
+			t.Fatalf("Add(%s) error = %v", id, err)
+		}
+	}
+
+	if err := s.Add(ctx, "f\ng"); err == nil {
+		t.Error("Add() error = nil, want an error for an ID with a newline")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file is compacted once it holds twice as many IDs as remembered
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(data); got != "d\ne\n" {
+		t.Errorf("file = %q, want %q", got, "d\ne\n")
+	}
+
+	// a partially written last line is loaded, and terminated
+	if err := os.WriteFile(path, append(data, "f"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewFileDeliveryStore(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for id, want := range map[string]bool{"d": false, "e": true, "f": true} {
+		if seen, _ := s.Seen(ctx, id); seen != want {
+			t.Errorf("Seen(%s) = %t, want %t", id, seen, want)
+		}
+	}
+}